@@ -5,6 +5,7 @@ import (
 
 	"github.com/aditya-goyal-omniful/ims/docs"
 	localConfig "github.com/aditya-goyal-omniful/ims/pkg/configs"
+	"github.com/aditya-goyal-omniful/ims/pkg/jobs"
 	"github.com/aditya-goyal-omniful/ims/pkg/middlewares"
 	"github.com/aditya-goyal-omniful/ims/pkg/routes"
 	"github.com/omniful/go_commons/config"
@@ -36,6 +37,12 @@ func main() {
 	localConfig.InitRedis(ctx)
 	defer localConfig.RedisClient.Close()
 
+	// Background jobs
+	jobs.StartReservationExpiry(ctx)
+	jobs.StartStockAlertEvaluator(ctx)
+	jobs.StartInventorySnapshots(ctx)
+	jobs.StartDemandForecasts(ctx)
+
 	// Swagger metadata
 	docs.SwaggerInfo.Title = "Inventory Management Service"
 	docs.SwaggerInfo.Description = "API documentation for IMS"
@@ -15,15 +15,15 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/hubs": {
+        "/adjustments": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Hubs"
+                    "Adjustments"
                 ],
-                "summary": "Get all hubs",
+                "summary": "List the tenant's adjustments, newest first; filter by status to build the approval queue",
                 "parameters": [
                     {
                         "type": "string",
@@ -31,6 +31,12 @@ const docTemplate = `{
                         "name": "X-Tenant-ID",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, applied or rejected",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -39,7 +45,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Hub"
+                                "$ref": "#/definitions/models.InventoryAdjustment"
                             }
                         }
                     }
@@ -53,9 +59,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Hubs"
+                    "Adjustments"
                 ],
-                "summary": "Create a new hub",
+                "summary": "Adjust an inventory row with a reason code; adjustments above the tenant's thresholds are left pending for approval",
                 "parameters": [
                     {
                         "type": "string",
@@ -65,12 +71,19 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Hub to create",
-                        "name": "hub",
+                        "type": "string",
+                        "description": "Requesting user",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Adjustment",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Hub"
+                            "$ref": "#/definitions/controllers.CreateAdjustmentRequest"
                         }
                     }
                 ],
@@ -78,29 +91,22 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Hub"
+                            "$ref": "#/definitions/models.InventoryAdjustment"
                         }
                     }
                 }
             }
         },
-        "/hubs/{id}": {
+        "/adjustments/reason-codes": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Hubs"
+                    "Adjustments"
                 ],
-                "summary": "Get hub by ID",
+                "summary": "List the tenant's adjustment reason codes, or the built-in set if it has none",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Hub ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Tenant ID",
@@ -113,12 +119,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Hub"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AdjustmentReasonCode"
+                            }
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -126,13 +135,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Hubs"
+                    "Adjustments"
                 ],
-                "summary": "Update hub by ID",
+                "summary": "Add an adjustment reason code for the tenant; once it has one, the built-in set no longer applies",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Hub ID",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason code",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateReasonCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AdjustmentReasonCode"
+                        }
+                    }
+                }
+            }
+        },
+        "/adjustments/{id}/approve": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Adjustments"
+                ],
+                "summary": "Approve a pending adjustment and apply it; the approver must not be the requester",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Adjustment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -145,36 +191,36 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated hub",
-                        "name": "hub",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Hub"
-                        }
+                        "type": "string",
+                        "description": "Approving user",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Hub"
+                            "$ref": "#/definitions/models.InventoryAdjustment"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/adjustments/{id}/reject": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Hubs"
+                    "Adjustments"
                 ],
-                "summary": "Delete hub by ID",
+                "summary": "Reject a pending adjustment without changing stock",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Hub ID",
+                        "description": "Adjustment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -185,27 +231,34 @@ const docTemplate = `{
                         "name": "X-Tenant-ID",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reviewing user",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Hub"
+                            "$ref": "#/definitions/models.InventoryAdjustment"
                         }
                     }
                 }
             }
         },
-        "/inventories": {
+        "/alerts": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "Alerts"
                 ],
-                "summary": "Get all inventories",
+                "summary": "List low-stock alerts of the tenant, unresolved ones unless a status is given",
                 "parameters": [
                     {
                         "type": "string",
@@ -213,6 +266,18 @@ const docTemplate = `{
                         "name": "X-Tenant-ID",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hub ID",
+                        "name": "hub_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "open, acknowledged or resolved",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -221,23 +286,57 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Inventory"
+                                "$ref": "#/definitions/models.StockAlert"
                             }
                         }
                     }
                 }
-            },
+            }
+        },
+        "/alerts/{id}/acknowledge": {
             "post": {
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Acknowledge an unresolved low-stock alert",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockAlert"
+                        }
+                    }
+                }
+            }
+        },
+        "/atp": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "ATP"
                 ],
-                "summary": "Create new inventory",
+                "summary": "Available-to-promise of a SKU at a hub, or per hub and in total across the tenant",
                 "parameters": [
                     {
                         "type": "string",
@@ -247,26 +346,36 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Inventory to create",
-                        "name": "inventory",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Inventory"
-                        }
+                        "type": "string",
+                        "description": "SKU ID",
+                        "name": "sku_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hub ID; omit for every hub of the tenant",
+                        "name": "hub_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Count inbound stock expected within this many days (default 7)",
+                        "name": "horizon_days",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Inventory"
+                            "$ref": "#/definitions/models.ATPResult"
                         }
                     }
                 }
             }
         },
-        "/inventories/upsert": {
+        "/atp/batch": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -275,9 +384,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "ATP"
                 ],
-                "summary": "Upsert (create or update) inventory",
+                "summary": "Available-to-promise for several SKU/hub pairs in one call, in request order",
                 "parameters": [
                     {
                         "type": "string",
@@ -287,12 +396,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Inventory object",
-                        "name": "inventory",
+                        "description": "SKUs with optional hubs",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Inventory"
+                            "$ref": "#/definitions/controllers.BatchATPRequest"
                         }
                     }
                 ],
@@ -300,24 +409,24 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ATPResult"
                             }
                         }
                     }
                 }
             }
         },
-        "/inventories/view": {
+        "/forecasts": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "Forecasts"
                 ],
-                "summary": "View inventory including SKUs with zero quantity",
+                "summary": "List the nightly daily demand forecasts and suggested reorder quantities per hub and SKU",
                 "parameters": [
                     {
                         "type": "string",
@@ -328,10 +437,21 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Hub ID",
+                        "description": "Only this hub",
                         "name": "hub_id",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this SKU",
+                        "name": "sku_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only rows with a suggested reorder",
+                        "name": "reorder_only",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -340,30 +460,23 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.InventoryView"
+                                "$ref": "#/definitions/models.DemandForecast"
                             }
                         }
                     }
                 }
             }
         },
-        "/inventories/{id}": {
+        "/hubs": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "Hubs"
                 ],
-                "summary": "Get inventory by ID",
+                "summary": "Get all hubs",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Inventory ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Tenant ID",
@@ -376,12 +489,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Inventory"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Hub"
+                            }
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -389,17 +505,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "Hubs"
                 ],
-                "summary": "Update inventory by ID",
+                "summary": "Create a new hub",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Inventory ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Tenant ID",
@@ -408,36 +517,38 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated inventory",
-                        "name": "inventory",
+                        "description": "Hub to create",
+                        "name": "hub",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Inventory"
+                            "$ref": "#/definitions/models.Hub"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Inventory"
+                            "$ref": "#/definitions/models.Hub"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/hubs/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "Hubs"
                 ],
-                "summary": "Delete inventory by ID",
+                "summary": "Get hub by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Inventory ID",
+                        "description": "Hub ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -454,14 +565,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Inventory"
+                            "$ref": "#/definitions/models.Hub"
                         }
                     }
                 }
-            }
-        },
-        "/inventory/check-and-update": {
-            "post": {
+            },
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -469,10 +578,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Inventories"
+                    "Hubs"
                 ],
-                "summary": "Check and update inventory if sufficient",
+                "summary": "Update hub by ID",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hub ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Tenant ID",
@@ -481,12 +597,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Inventory check payload",
-                        "name": "payload",
+                        "description": "Updated hub",
+                        "name": "hub",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.CheckInventoryRequest"
+                            "$ref": "#/definitions/models.Hub"
                         }
                     }
                 ],
@@ -494,36 +610,90 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "boolean"
-                            }
+                            "$ref": "#/definitions/models.Hub"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hubs"
+                ],
+                "summary": "Delete hub by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hub ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Hub"
                         }
                     }
                 }
             }
         },
-        "/sellers": {
+        "/hubs/{id}/bins/inventory": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Locations"
+                ],
+                "summary": "List per-bin stock in a hub",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hub ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by SKU ID",
+                        "name": "sku_id",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all sellers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Seller"
+                                "$ref": "#/definitions/models.BinStockView"
                             }
                         }
                     }
                 }
-            },
+            }
+        },
+        "/hubs/{id}/bins/move": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -532,58 +702,85 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Locations"
                 ],
-                "summary": "Create a new seller",
+                "summary": "Move stock between bins; omit from_bin_id to put away unbinned stock, omit to_bin_id to unbin it",
                 "parameters": [
                     {
-                        "description": "Seller to create",
-                        "name": "seller",
+                        "type": "string",
+                        "description": "Hub ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Bin move",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Seller"
+                            "$ref": "#/definitions/controllers.MoveBinStockRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Seller"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/sellers/{id}": {
+        "/hubs/{id}/locations": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Locations"
                 ],
-                "summary": "Get seller by ID",
+                "summary": "List the zones, aisles and bins of a hub",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Seller ID",
+                        "description": "Hub ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Seller"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Location"
+                            }
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -591,72 +788,97 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Locations"
                 ],
-                "summary": "Update seller by ID",
+                "summary": "Create a zone, aisle (parent: zone) or bin (parent: aisle) in a hub",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Seller ID",
+                        "description": "Hub ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated seller",
-                        "name": "seller",
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Location to create",
+                        "name": "location",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Seller"
+                            "$ref": "#/definitions/models.Location"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Seller"
+                            "$ref": "#/definitions/models.Location"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/hubs/{id}/lots/expiring": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sellers"
+                    "Lots"
                 ],
-                "summary": "Delete seller by ID",
+                "summary": "List lots in a hub expiring within N days, already expired included",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Seller ID",
+                        "description": "Hub ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Window in days (default 30)",
+                        "name": "days",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Seller"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ExpiringLotView"
+                            }
                         }
                     }
                 }
             }
         },
-        "/skus": {
+        "/inventories": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "SKUs"
+                    "Inventories"
                 ],
-                "summary": "Get all SKUs (with optional filters)",
+                "summary": "Get all inventories",
                 "parameters": [
                     {
                         "type": "string",
@@ -664,22 +886,6 @@ const docTemplate = `{
                         "name": "X-Tenant-ID",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by Seller ID",
-                        "name": "seller_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Filter by multiple SKU codes (repeat param)",
-                        "name": "sku_codes",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -688,7 +894,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Sku"
+                                "$ref": "#/definitions/models.Inventory"
                             }
                         }
                     }
@@ -702,9 +908,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SKUs"
+                    "Inventories"
                 ],
-                "summary": "Create a new SKU",
+                "summary": "Create new inventory; a non-zero quantity needs a reason_code and becomes an adjustment, pending above the tenant's thresholds",
                 "parameters": [
                     {
                         "type": "string",
@@ -714,12 +920,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "SKU to create",
-                        "name": "sku",
+                        "type": "string",
+                        "description": "Requesting user, required for a non-zero quantity",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Inventory to create",
+                        "name": "inventory",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Sku"
+                            "$ref": "#/definitions/models.Inventory"
                         }
                     }
                 ],
@@ -727,47 +939,58 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Sku"
+                            "$ref": "#/definitions/models.Inventory"
                         }
                     }
                 }
             }
         },
-        "/skus/{id}": {
+        "/inventories/aging": {
             "get": {
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "SKUs"
+                    "Inventories"
                 ],
-                "summary": "Get SKU by ID",
+                "summary": "Age on-hand stock per hub and SKU into 0-30, 31-60, 61-90 and 90+ day buckets",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "SKU ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Tenant ID",
                         "name": "X-Tenant-ID",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only age this hub",
+                        "name": "hub_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv to download",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Sku"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.InventoryAging"
+                            }
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/inventories/upsert": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -775,17 +998,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SKUs"
+                    "Inventories"
                 ],
-                "summary": "Update SKU by ID",
+                "summary": "Upsert (create or update) inventory; a quantity change needs a reason_code and becomes an adjustment, pending above the tenant's thresholds",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "SKU ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Tenant ID",
@@ -794,12 +1010,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated SKU",
-                        "name": "sku",
+                        "type": "string",
+                        "description": "Requesting user, required to change quantity",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Inventory object",
+                        "name": "inventory",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Sku"
+                            "$ref": "#/definitions/models.Inventory"
                         }
                     }
                 ],
@@ -807,67 +1029,132 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Sku"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/inventories/view": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "SKUs"
+                    "Inventories"
                 ],
-                "summary": "Delete SKU by ID",
+                "summary": "View inventory including SKUs with zero quantity",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SKU ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Tenant ID",
-                        "name": "X-Tenant-ID",
-                        "in": "header",
+                        "description": "Hub ID",
+                        "name": "hub_id",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Show the hub as it stood at the end of this day, YYYY-MM-DD",
+                        "name": "as_of",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Sku"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.InventoryView"
+                            }
                         }
                     }
                 }
             }
         },
-        "/tenants": {
+        "/inventories/view/detailed": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tenants"
+                    "Inventories"
+                ],
+                "summary": "View inventory of a hub broken down by status bucket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hub ID",
+                        "name": "hub_id",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all tenants",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Tenant"
+                                "$ref": "#/definitions/models.InventoryDetailView"
                             }
                         }
                     }
                 }
+            }
+        },
+        "/inventories/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventories"
+                ],
+                "summary": "Get inventory by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Inventory ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Inventory"
+                        }
+                    }
+                }
             },
-            "post": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -875,229 +1162,4436 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tenants"
+                    "Inventories"
                 ],
-                "summary": "Create a new tenant",
+                "summary": "Update inventory by ID; a quantity change needs a reason_code and becomes an adjustment, pending above the tenant's thresholds",
                 "parameters": [
                     {
-                        "description": "Tenant to create",
-                        "name": "tenant",
+                        "type": "string",
+                        "description": "Inventory ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Requesting user, required to change quantity",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Updated inventory",
+                        "name": "inventory",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Tenant"
+                            "$ref": "#/definitions/controllers.UpdateInventoryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Tenant"
+                            "$ref": "#/definitions/models.Inventory"
                         }
                     }
                 }
-            }
-        },
-        "/tenants/{id}": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tenants"
+                    "Inventories"
                 ],
-                "summary": "Get tenant by ID",
+                "summary": "Delete an empty inventory row by ID; adjust stocked rows to zero first",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tenant ID",
+                        "description": "Inventory ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Tenant"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.Inventory"
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/inventories/{id}/lots": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tenants"
+                    "Lots"
                 ],
-                "summary": "Update tenant by ID",
+                "summary": "List the lots holding stock of an inventory row, first expiry first",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tenant ID",
+                        "description": "Inventory ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Tenant data",
-                        "name": "tenant",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Tenant"
-                        }
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Tenant"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.InventoryLot"
+                            }
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tenants"
+                    "Lots"
                 ],
-                "summary": "Delete tenant by ID",
+                "summary": "Receive stock into a lot of an inventory row, adding to its quantity",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tenant ID",
+                        "description": "Inventory ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Lot receipt",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ReceiveLotRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Tenant"
+                            "$ref": "#/definitions/models.InventoryLot"
                         }
                     }
                 }
             }
         },
-        "/validators/validate_order/{hub_id}/{sku_id}": {
+        "/inventories/{id}/movements": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Validators"
+                    "Inventories"
                 ],
-                "summary": "Validate hub and SKU IDs",
+                "summary": "List the quantity movements of an inventory row, newest first",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Hub ID",
-                        "name": "hub_id",
+                        "description": "Inventory ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "SKU ID",
-                        "name": "sku_id",
-                        "in": "path",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of time range (RFC3339, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of time range (RFC3339, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 500)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "boolean"
-                            }
+                            "$ref": "#/definitions/controllers.MovementHistory"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controllers.CheckInventoryRequest": {
-            "type": "object",
-            "required": [
-                "hub_id",
-                "quantity",
-                "sku_id"
-            ],
-            "properties": {
-                "hub_id": {
+        },
+        "/inventories/{id}/status-moves": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventories"
+                ],
+                "summary": "Move quantity between the sellable, damaged, quarantined, in_transit and disposal buckets of an inventory row",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Inventory ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Status move",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.MoveInventoryStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.InventoryBuckets"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventories/{id}/thresholds": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Change the thresholds, safety stock, lead time or days of cover of an inventory row; fields left out are kept and null clears one",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Inventory ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Thresholds",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StockThresholds"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Inventory"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/check-and-update": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventories"
+                ],
+                "summary": "Check and update inventory if sufficient, or allocate what is available when allow_partial is set",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Inventory check payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CheckInventoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CheckInventoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/check-and-update/batch": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventories"
+                ],
+                "summary": "Check and decrement all order lines in one transaction, or none of them",
+                "parameters": [
+                    {
+                        "description": "Order lines",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BatchCheckInventoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.BatchCheckInventoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/reservations": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reservations"
+                ],
+                "summary": "Reserve stock for an order line until it is committed, released or expires",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Reservation payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ReserveInventoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Reservation"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/reservations/{order_reference}/commit": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reservations"
+                ],
+                "summary": "Commit an order's reservations into real stock decrements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order reference",
+                        "name": "order_reference",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Reservation"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/reservations/{order_reference}/release": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reservations"
+                ],
+                "summary": "Release an order's reservations back to available stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order reference",
+                        "name": "order_reference",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Reservation"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/serials": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Serials"
+                ],
+                "summary": "Receive serialized units into a hub, one unit of stock per serial",
+                "parameters": [
+                    {
+                        "description": "Serials to register",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RegisterSerialsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Serial"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/serials/allocate": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Serials"
+                ],
+                "summary": "Dispatch in-stock serials from a hub against an order, all or none",
+                "parameters": [
+                    {
+                        "description": "Serials to allocate",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.AllocateSerialsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Serial"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/serials/{serial_number}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Serials"
+                ],
+                "summary": "Look up a serial's current hub and status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Serial number",
+                        "name": "serial_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Restrict to one SKU",
+                        "name": "sku_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Serial"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "List the tenant's products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Create a product to group SKU variants under",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Product",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/variants": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "List the SKU variants of a product with stock summed across hubs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.VariantStock"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "List the tenant's purchase orders, newest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "open, partially_received or closed",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PurchaseOrder"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Create a purchase order of expected SKUs and quantities for a hub",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Purchase order",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreatePurchaseOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Get a purchase order with its lines and shipping notices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/close": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Close a purchase order that will not be received in full; outstanding quantities remain as under-receipts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/receive": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Receive stock against a purchase order, optionally for one shipping notice; the order closes once every line is received in full",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Received quantities per SKU; omit with a shipping notice to receive it as shipped",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ReceivePurchaseOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/shipping-notices": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Record an advance shipping notice against an open purchase order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Shipped quantities per SKU",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateShippingNoticeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AdvanceShippingNotice"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Returns"
+                ],
+                "summary": "List the tenant's returns, newest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only returns of this order",
+                        "name": "order_reference",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "open or received",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ReturnAuthorization"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Returns"
+                ],
+                "summary": "Authorize a customer return of an order's SKUs to a hub",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Return authorization",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateReturnRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReturnAuthorization"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns/ledger": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Returns"
+                ],
+                "summary": "List the tenant's received return units by SKU and grade, newest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only returns to this hub",
+                        "name": "hub_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of time range (RFC3339, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of time range (RFC3339, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 500)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ReturnLedger"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Returns"
+                ],
+                "summary": "Get a return with its lines and grading",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Return ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReturnAuthorization"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns/{id}/receive": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Returns"
+                ],
+                "summary": "Receive and grade a return: sellable units are restocked, damaged and disposal units go to their buckets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Return ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Graded quantities per SKU",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ReceiveReturnRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReturnAuthorization"
+                        }
+                    }
+                }
+            }
+        },
+        "/sellers": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Get all sellers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Seller"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Create a new seller",
+                "parameters": [
+                    {
+                        "description": "Seller to create",
+                        "name": "seller",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    }
+                }
+            }
+        },
+        "/sellers/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Get seller by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Seller ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Update seller by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Seller ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated seller",
+                        "name": "seller",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sellers"
+                ],
+                "summary": "Delete seller by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Seller ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Seller"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SKUs"
+                ],
+                "summary": "Get all SKUs (with optional filters)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by Seller ID",
+                        "name": "seller_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Filter by multiple SKU codes (repeat param)",
+                        "name": "sku_codes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by product",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by variant attribute as attributes[name]=value, e.g. attributes[size]=M (repeat for more)",
+                        "name": "attributes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Sku"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SKUs"
+                ],
+                "summary": "Create a new SKU",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "SKU to create",
+                        "name": "sku",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Sku"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sku"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/lookup": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SKUs"
+                ],
+                "summary": "Resolve a scanned barcode to its SKU, with stock at the caller's hub",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hub of the scanner; adds the SKU's stock there",
+                        "name": "X-Hub-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scanned code",
+                        "name": "barcode",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BarcodeLookup"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SKUs"
+                ],
+                "summary": "Get SKU by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SKU ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sku"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SKUs"
+                ],
+                "summary": "Update SKU by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SKU ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated SKU",
+                        "name": "sku",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Sku"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sku"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SKUs"
+                ],
+                "summary": "Delete SKU by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SKU ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sku"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{id}/barcodes": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SKUs"
+                ],
+                "summary": "Add a barcode to a SKU; GS1 codes are checked for length and check digit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SKU ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Barcode",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.AddBarcodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SkuBarcode"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{id}/components": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SKUs"
+                ],
+                "summary": "Set the component SKUs and quantities of a bundle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SKU ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Components of one bundle",
+                        "name": "bundle",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SetBundleComponentsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sku"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{id}/units": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SKUs"
+                ],
+                "summary": "List the pack-size conversions of a SKU to its base unit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SKU ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SkuUnit"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SKUs"
+                ],
+                "summary": "Add or replace a pack-size conversion of a SKU, e.g. 1 case = 24 each",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SKU ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "unit_quantity of unit equal base_quantity base units",
+                        "name": "unit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SkuUnitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SkuUnit"
+                        }
+                    }
+                }
+            }
+        },
+        "/sourcing/plan": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sourcing"
+                ],
+                "summary": "Choose the hub, or split across hubs, to fulfil order lines for a destination using the tenant's sourcing strategy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Destination and order lines",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SourcingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/sourcing.Plan"
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Open a stocktake for a hub, snapshotting expected quantities (optionally for a SKU list)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Stocktake scope",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateStocktakeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Stocktake"
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Get a stocktake with its lines",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Stocktake"
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes/{id}/approve": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Approve a stocktake, booking counted variances as adjustments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Approving user, other than the stocktake's creator",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Stocktake"
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes/{id}/counts": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Record counted quantities for an open stocktake",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Counts per SKU",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RecordStocktakeCountsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stocktakes/{id}/variance": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Variance report of a stocktake, largest variances first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StocktakeVarianceReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/tenants": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenants"
+                ],
+                "summary": "Get all tenants",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tenant"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenants"
+                ],
+                "summary": "Create a new tenant",
+                "parameters": [
+                    {
+                        "description": "Tenant to create",
+                        "name": "tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    }
+                }
+            }
+        },
+        "/tenants/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenants"
+                ],
+                "summary": "Get tenant by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenants"
+                ],
+                "summary": "Update tenant by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tenant data",
+                        "name": "tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenants"
+                ],
+                "summary": "Delete tenant by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "List the tenant's transfer orders, newest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "created, dispatched or received",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TransferOrder"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Create a transfer order between two hubs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Transfer order",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Get a transfer order with its lines",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transfer order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{id}/dispatch": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Dispatch a transfer, moving its lines from the source hub's sellable stock to in-transit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transfer order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{id}/receive": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Receive a dispatched transfer at the destination hub; short lines are recorded as discrepancies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transfer order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Received quantities per SKU",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ReceiveTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/validators/validate_order/{hub_id}/{sku_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Validators"
+                ],
+                "summary": "Validate hub and SKU IDs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hub ID",
+                        "name": "hub_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "SKU ID",
+                        "name": "sku_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "boolean"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/valuation": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Valuation"
+                ],
+                "summary": "Value sellable stock per SKU, hub and tenant from its cost layers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "X-Tenant-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Value at the end of this day, YYYY-MM-DD; defaults to now",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only value this hub",
+                        "name": "hub_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValuationReport"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.AddBarcodeRequest": {
+            "type": "object",
+            "required": [
+                "barcode",
+                "type"
+            ],
+            "properties": {
+                "barcode": {
+                    "type": "string",
+                    "example": "4006381333931"
+                },
+                "type": {
+                    "description": "ean13, upca, gtin14 or internal",
+                    "type": "string",
+                    "example": "ean13"
+                }
+            }
+        },
+        "controllers.AllocateSerialsRequest": {
+            "type": "object",
+            "required": [
+                "hub_id",
+                "order_reference",
+                "serial_numbers",
+                "sku_id"
+            ],
+            "properties": {
+                "hub_id": {
+                    "type": "string"
+                },
+                "order_reference": {
+                    "type": "string"
+                },
+                "serial_numbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.BatchATPRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "horizon_days": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ATPQuery"
+                    }
+                }
+            }
+        },
+        "controllers.BatchCheckInventoryRequest": {
+            "type": "object",
+            "required": [
+                "lines"
+            ],
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockLine"
+                    }
+                },
+                "order_reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.BatchCheckInventoryResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "shortages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockShortage"
+                    }
+                }
+            }
+        },
+        "controllers.BundleComponentRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "sku_id"
+            ],
+            "properties": {
+                "quantity": {
+                    "description": "units of the component in one bundle",
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CheckInventoryRequest": {
+            "type": "object",
+            "required": [
+                "hub_id",
+                "quantity",
+                "sku_id"
+            ],
+            "properties": {
+                "allow_partial": {
+                    "type": "boolean"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "order_reference": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "defaults to the SKU's base unit",
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CheckInventoryResponse": {
+            "type": "object",
+            "properties": {
+                "allocated": {
+                    "type": "integer"
+                },
+                "available": {
+                    "description": "the full requested quantity was allocated",
+                    "type": "boolean"
+                },
+                "current_stock": {
+                    "description": "on hand after the allocation",
+                    "type": "integer"
+                },
+                "remaining": {
+                    "description": "requested but not allocated",
+                    "type": "integer"
+                },
+                "requested": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.CreateAdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "inventory_id",
+                "reason_code"
+            ],
+            "properties": {
+                "delta": {
+                    "type": "integer"
+                },
+                "inventory_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "reason_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CreateProductRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CreatePurchaseOrderRequest": {
+            "type": "object",
+            "required": [
+                "hub_id",
+                "lines"
+            ],
+            "properties": {
+                "expected_at": {
+                    "description": "RFC3339, optional",
+                    "type": "string",
+                    "example": "2025-01-31T00:00:00Z"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.PurchaseOrderLineRequest"
+                    }
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "supplier": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CreateReasonCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "direction": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CreateReturnRequest": {
+            "type": "object",
+            "required": [
+                "hub_id",
+                "lines",
+                "order_reference"
+            ],
+            "properties": {
+                "hub_id": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.ReturnLineRequest"
+                    }
+                },
+                "order_reference": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CreateShippingNoticeRequest": {
+            "type": "object",
+            "required": [
+                "lines"
+            ],
+            "properties": {
+                "expected_at": {
+                    "description": "RFC3339, optional",
+                    "type": "string",
+                    "example": "2025-01-31T00:00:00Z"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.PurchaseOrderLineRequest"
+                    }
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CreateStocktakeRequest": {
+            "type": "object",
+            "required": [
+                "hub_id"
+            ],
+            "properties": {
+                "hub_id": {
+                    "type": "string"
+                },
+                "sku_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "controllers.CreateTransferRequest": {
+            "type": "object",
+            "required": [
+                "destination_hub_id",
+                "lines",
+                "source_hub_id"
+            ],
+            "properties": {
+                "destination_hub_id": {
+                    "type": "string"
+                },
+                "expected_at": {
+                    "description": "RFC3339, optional",
+                    "type": "string",
+                    "example": "2025-01-31T00:00:00Z"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.TransferLineRequest"
+                    }
+                },
+                "source_hub_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.GradedLineRequest": {
+            "type": "object",
+            "required": [
+                "sku_id"
+            ],
+            "properties": {
+                "damaged": {
+                    "type": "integer"
+                },
+                "disposal": {
+                    "type": "integer"
+                },
+                "sellable": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.MoveBinStockRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "sku_id"
+            ],
+            "properties": {
+                "from_bin_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "to_bin_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.MoveInventoryStatusRequest": {
+            "type": "object",
+            "required": [
+                "from_status",
+                "quantity",
+                "to_status"
+            ],
+            "properties": {
+                "from_status": {
+                    "type": "string",
+                    "example": "sellable"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "to_status": {
+                    "type": "string",
+                    "example": "damaged"
+                }
+            }
+        },
+        "controllers.MovementHistory": {
+            "type": "object",
+            "properties": {
+                "movements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InventoryMovement"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.PurchaseOrderLineRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "sku_id"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "unit_cost": {
+                    "description": "cost per unit, taken on purchase order creation only",
+                    "type": "number"
+                }
+            }
+        },
+        "controllers.ReceiveLotRequest": {
+            "type": "object",
+            "required": [
+                "lot_number",
+                "quantity"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-07-31"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "manufactured_at": {
+                    "type": "string",
+                    "example": "2026-01-31"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_cost": {
+                    "description": "per unit; defaults to the row's current average cost",
+                    "type": "number"
+                }
+            }
+        },
+        "controllers.ReceivePurchaseOrderRequest": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.PurchaseOrderLineRequest"
+                    }
+                },
+                "shipping_notice_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.ReceiveReturnRequest": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.GradedLineRequest"
+                    }
+                }
+            }
+        },
+        "controllers.ReceiveTransferRequest": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.ReceivedLineRequest"
+                    }
+                }
+            }
+        },
+        "controllers.ReceivedLineRequest": {
+            "type": "object",
+            "required": [
+                "sku_id"
+            ],
+            "properties": {
+                "received_quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.RecordStocktakeCountsRequest": {
+            "type": "object",
+            "required": [
+                "counts"
+            ],
+            "properties": {
+                "counts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.StocktakeCount"
+                    }
+                }
+            }
+        },
+        "controllers.RegisterSerialsRequest": {
+            "type": "object",
+            "required": [
+                "hub_id",
+                "serial_numbers",
+                "sku_id"
+            ],
+            "properties": {
+                "hub_id": {
+                    "type": "string"
+                },
+                "serial_numbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.ReserveInventoryRequest": {
+            "type": "object",
+            "required": [
+                "hub_id",
+                "order_reference",
+                "quantity",
+                "sku_id"
+            ],
+            "properties": {
+                "hub_id": {
+                    "type": "string"
+                },
+                "order_reference": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.ReturnLedger": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReturnLedgerEntry"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.ReturnLineRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "sku_id"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.SetBundleComponentsRequest": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "description": "empty to stop selling the SKU as a bundle",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.BundleComponentRequest"
+                    }
+                }
+            }
+        },
+        "controllers.SkuUnitRequest": {
+            "type": "object",
+            "required": [
+                "base_quantity",
+                "unit"
+            ],
+            "properties": {
+                "base_quantity": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "case"
+                },
+                "unit_quantity": {
+                    "description": "defaults to 1",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "controllers.SourcingRequest": {
+            "type": "object",
+            "required": [
+                "lines"
+            ],
+            "properties": {
+                "destination": {
+                    "$ref": "#/definitions/sourcing.Destination"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sourcing.Line"
+                    }
+                }
+            }
+        },
+        "controllers.StocktakeCount": {
+            "type": "object",
+            "required": [
+                "sku_id"
+            ],
+            "properties": {
+                "counted_quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.TransferLineRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "sku_id"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.UpdateInventoryRequest": {
+            "type": "object",
+            "properties": {
+                "adjustment": {
+                    "description": "recorded by a quantity change on a write",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.InventoryAdjustment"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lead_time_days": {
+                    "type": "integer"
+                },
+                "min_quantity": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reason_code": {
+                    "description": "adjustment reason of a quantity change on writes",
+                    "type": "string"
+                },
+                "reorder_point": {
+                    "type": "integer"
+                },
+                "safety_stock": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "target_days_of_cover": {
+                    "type": "integer"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "unit of Quantity on writes; stored in the SKU's base unit",
+                    "type": "string"
+                },
+                "unit_cost": {
+                    "description": "cost per base unit of any quantity added by a write",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ATPLine": {
+            "type": "object",
+            "properties": {
+                "atp": {
+                    "type": "integer"
+                },
+                "hub_id": {
+                    "description": "nil on the tenant total",
+                    "type": "string"
+                },
+                "inbound": {
+                    "description": "confirmed to arrive within the horizon",
+                    "type": "integer"
+                },
+                "on_hand": {
+                    "type": "integer"
+                },
+                "reserved": {
+                    "type": "integer"
+                },
+                "safety_stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ATPQuery": {
+            "type": "object",
+            "required": [
+                "sku_id"
+            ],
+            "properties": {
+                "hub_id": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ATPResult": {
+            "type": "object",
+            "properties": {
+                "hubs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ATPLine"
+                    }
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/models.ATPLine"
+                }
+            }
+        },
+        "models.AdjustmentReasonCode": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AdvanceShippingNotice": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expected_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AdvanceShippingNoticeLine"
+                    }
+                },
+                "purchase_order_id": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AdvanceShippingNoticeLine": {
+            "type": "object",
+            "properties": {
+                "advance_shipping_notice_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "received_quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BarcodeLookup": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "$ref": "#/definitions/models.SkuBarcode"
+                },
+                "sku": {
+                    "$ref": "#/definitions/models.Sku"
+                },
+                "stock": {
+                    "$ref": "#/definitions/models.InventoryView"
+                }
+            }
+        },
+        "models.BinStockView": {
+            "type": "object",
+            "properties": {
+                "bin_code": {
+                    "type": "string"
+                },
+                "bin_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_code": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BundleComponent": {
+            "type": "object",
+            "properties": {
+                "bundle_sku_id": {
+                    "type": "string"
+                },
+                "component_sku_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DemandForecast": {
+            "type": "object",
+            "properties": {
+                "computed_at": {
+                    "type": "string"
+                },
+                "exponential_smoothing": {
+                    "description": "units per day; drives the suggestion",
+                    "type": "number"
+                },
+                "forecast_date": {
+                    "type": "string"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "inbound": {
+                    "description": "in-transit shipping notices and dispatched transfers, as in ATP",
+                    "type": "integer"
+                },
+                "lead_time_days": {
+                    "type": "integer"
+                },
+                "moving_average": {
+                    "description": "units per day",
+                    "type": "number"
+                },
+                "on_hand": {
+                    "type": "integer"
+                },
+                "reserved": {
+                    "type": "integer"
+                },
+                "safety_stock": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "suggested_quantity": {
+                    "type": "integer"
+                },
+                "target_days_of_cover": {
+                    "type": "integer"
+                },
+                "tenant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExpiringLotView": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "inventory_id": {
+                    "type": "string"
+                },
+                "lot_id": {
+                    "type": "string"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "manufactured_at": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_code": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Hub": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HubValuation": {
+            "type": "object",
+            "properties": {
+                "hub_id": {
+                    "type": "string"
+                },
+                "hub_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "skus": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SkuValuation"
+                    }
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Inventory": {
+            "type": "object",
+            "properties": {
+                "adjustment": {
+                    "description": "recorded by a quantity change on a write",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.InventoryAdjustment"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lead_time_days": {
+                    "type": "integer"
+                },
+                "min_quantity": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reason_code": {
+                    "description": "adjustment reason of a quantity change on writes",
+                    "type": "string"
+                },
+                "reorder_point": {
+                    "type": "integer"
+                },
+                "safety_stock": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "target_days_of_cover": {
+                    "type": "integer"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "unit of Quantity on writes; stored in the SKU's base unit",
+                    "type": "string"
+                },
+                "unit_cost": {
+                    "description": "cost per base unit of any quantity added by a write",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InventoryAdjustment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "delta": {
+                    "type": "integer"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inventory_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "quantity_after": {
+                    "type": "integer"
+                },
+                "quantity_before": {
+                    "description": "set once applied",
+                    "type": "integer"
+                },
+                "reason_code": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InventoryAging": {
+            "type": "object",
+            "properties": {
+                "days_0_30": {
+                    "type": "integer"
+                },
+                "days_31_60": {
+                    "type": "integer"
+                },
+                "days_61_90": {
+                    "type": "integer"
+                },
+                "days_90_plus": {
+                    "type": "integer"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "hub_name": {
+                    "type": "string"
+                },
+                "oldest_age_days": {
+                    "type": "integer"
+                },
+                "on_hand": {
+                    "type": "integer"
+                },
+                "sku_code": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "sku_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InventoryBuckets": {
+            "type": "object",
+            "properties": {
+                "damaged": {
+                    "type": "integer"
+                },
+                "disposal": {
+                    "type": "integer"
+                },
+                "in_transit": {
+                    "type": "integer"
+                },
+                "quarantined": {
+                    "type": "integer"
+                },
+                "sellable": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InventoryDetailView": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "sellable minus active reservations",
+                    "type": "integer"
+                },
+                "damaged": {
+                    "type": "integer"
+                },
+                "disposal": {
+                    "type": "integer"
+                },
+                "in_transit": {
+                    "type": "integer"
+                },
+                "quarantined": {
+                    "type": "integer"
+                },
+                "reserved": {
+                    "type": "integer"
+                },
+                "sellable": {
+                    "type": "integer"
+                },
+                "sku_code": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "sku_name": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InventoryLot": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inventory_id": {
+                    "type": "string"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "manufactured_at": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_cost": {
+                    "description": "cost per unit of this receipt",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InventoryMovement": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delta": {
+                    "type": "integer"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inventory_id": {
+                    "type": "string"
+                },
+                "quantity_after": {
+                    "type": "integer"
+                },
+                "quantity_before": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reference_id": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InventoryView": {
+            "type": "object",
+            "properties": {
+                "bundle": {
+                    "description": "figures are whole bundles the components make up",
+                    "type": "boolean"
+                },
+                "on_hand": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "description": "available: on hand minus active reservations",
+                    "type": "integer"
+                },
+                "reserved": {
+                    "type": "integer"
+                },
+                "sku_code": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "sku_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Location": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Product": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PurchaseOrder": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expected_at": {
+                    "type": "string"
+                },
+                "has_discrepancy": {
+                    "type": "boolean"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurchaseOrderLine"
+                    }
+                },
+                "reference": {
+                    "description": "the supplier's or ERP's PO number",
+                    "type": "string"
+                },
+                "shipments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AdvanceShippingNotice"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "supplier": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PurchaseOrderLine": {
+            "type": "object",
+            "properties": {
+                "expected_quantity": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "purchase_order_id": {
+                    "type": "string"
+                },
+                "received_quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "unit_cost": {
+                    "description": "booked as the cost of received units",
+                    "type": "number"
+                },
+                "variance": {
+                    "description": "received minus expected: negative while outstanding, positive when over-received",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Reservation": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "order_reference": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReturnAuthorization": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReturnAuthorizationLine"
+                    }
+                },
+                "order_reference": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReturnAuthorizationLine": {
+            "type": "object",
+            "properties": {
+                "damaged_quantity": {
+                    "type": "integer"
+                },
+                "disposal_quantity": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "authorized",
+                    "type": "integer"
+                },
+                "return_authorization_id": {
+                    "type": "string"
+                },
+                "sellable_quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReturnLedgerEntry": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "grade": {
+                    "type": "string"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "order_reference": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "return_authorization_id": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Seller": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Serial": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "order_reference": {
+                    "type": "string"
+                },
+                "serial_number": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Sku": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/models.VariantAttributes"
+                },
+                "barcodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SkuBarcode"
+                    }
+                },
+                "base_unit": {
+                    "type": "string"
+                },
+                "components": {
+                    "description": "set for bundles only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BundleComponent"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "seller_id": {
+                    "type": "string"
+                },
+                "serialized": {
+                    "type": "boolean"
+                },
+                "sku_code": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SkuBarcode": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "gtin": {
+                    "description": "GS1 codes as 14 digits; nil for internal codes",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SkuUnit": {
+            "type": "object",
+            "properties": {
+                "base_quantity": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "unit_quantity": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SkuValuation": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_code": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "sku_name": {
+                    "type": "string"
+                },
+                "unit_cost": {
+                    "description": "value over quantity",
+                    "type": "number"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.StockAlert": {
+            "type": "object",
+            "properties": {
+                "acknowledged_at": {
+                    "type": "string"
+                },
+                "acknowledged_by": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inventory_id": {
+                    "type": "string"
+                },
+                "level": {
                     "type": "string"
                 },
                 "quantity": {
+                    "description": "sellable quantity when last evaluated",
                     "type": "integer"
                 },
+                "resolved_at": {
+                    "type": "string"
+                },
                 "sku_id": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "models.Hub": {
+        "models.StockLine": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "hub_id": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "quantity": {
+                    "type": "integer"
                 },
-                "location": {
+                "sku_id": {
                     "type": "string"
                 },
-                "name": {
+                "unit": {
+                    "description": "defaults to the SKU's base unit",
                     "type": "string"
+                }
+            }
+        },
+        "models.StockShortage": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
                 },
-                "tenant_id": {
+                "hub_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "requested": {
+                    "type": "integer"
+                },
+                "short_by": {
+                    "type": "integer"
+                },
+                "sku_id": {
                     "type": "string"
                 }
             }
         },
-        "models.Inventory": {
+        "models.StockThresholds": {
+            "type": "object",
+            "properties": {
+                "lead_time_days": {
+                    "description": "days a reorder takes to arrive; null uses the default",
+                    "type": "integer"
+                },
+                "min_quantity": {
+                    "type": "integer"
+                },
+                "reorder_point": {
+                    "type": "integer"
+                },
+                "safety_stock": {
+                    "description": "held back from available-to-promise; null is zero",
+                    "type": "integer"
+                },
+                "target_days_of_cover": {
+                    "description": "demand to hold after a reorder arrives; null uses the default",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Stocktake": {
             "type": "object",
             "properties": {
+                "approved_at": {
+                    "type": "string"
+                },
+                "approved_by": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "created_by": {
+                    "type": "string"
+                },
                 "hub_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StocktakeLine"
+                    }
                 },
-                "sku_id": {
+                "status": {
                     "type": "string"
                 },
                 "tenant_id": {
@@ -1108,10 +5602,53 @@ const docTemplate = `{
                 }
             }
         },
-        "models.InventoryView": {
+        "models.StocktakeLine": {
             "type": "object",
             "properties": {
-                "quantity": {
+                "adjusted_quantity": {
+                    "description": "delta applied on approval",
+                    "type": "integer"
+                },
+                "adjustment_id": {
+                    "type": "string"
+                },
+                "counted_at": {
+                    "type": "string"
+                },
+                "counted_quantity": {
+                    "type": "integer"
+                },
+                "expected_quantity": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inventory_id": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "stocktake_id": {
+                    "type": "string"
+                },
+                "variance": {
+                    "description": "counted minus expected",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StocktakeVarianceLine": {
+            "type": "object",
+            "properties": {
+                "adjusted_quantity": {
+                    "type": "integer"
+                },
+                "counted_quantity": {
+                    "type": "integer"
+                },
+                "expected_quantity": {
                     "type": "integer"
                 },
                 "sku_code": {
@@ -1120,14 +5657,57 @@ const docTemplate = `{
                 "sku_id": {
                     "type": "string"
                 },
-                "sku_name": {
+                "variance": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StocktakeVarianceReport": {
+            "type": "object",
+            "properties": {
+                "absolute_variance": {
+                    "type": "integer"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StocktakeVarianceLine"
+                    }
+                },
+                "lines_counted": {
+                    "type": "integer"
+                },
+                "lines_total": {
+                    "type": "integer"
+                },
+                "lines_with_variance": {
+                    "type": "integer"
+                },
+                "net_variance": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stocktake_id": {
                     "type": "string"
                 }
             }
         },
-        "models.Seller": {
+        "models.Tenant": {
             "type": "object",
             "properties": {
+                "adjustment_approval_percent": {
+                    "description": "as do those above this % of on-hand",
+                    "type": "number"
+                },
+                "adjustment_approval_units": {
+                    "description": "adjustments of more units need approval",
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -1137,30 +5717,52 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
-                "tenant_id": {
+                "sourcing_strategy": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "valuation_method": {
+                    "description": "fifo or weighted_average",
+                    "type": "string"
                 }
             }
         },
-        "models.Sku": {
+        "models.TransferOrder": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
+                "destination_hub_id": {
+                    "type": "string"
+                },
+                "dispatched_at": {
+                    "type": "string"
+                },
+                "expected_at": {
+                    "type": "string"
+                },
+                "has_discrepancy": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransferOrderLine"
+                    }
+                },
+                "received_at": {
                     "type": "string"
                 },
-                "seller_id": {
+                "source_hub_id": {
                     "type": "string"
                 },
-                "sku_code": {
+                "status": {
                     "type": "string"
                 },
                 "tenant_id": {
@@ -1171,19 +5773,185 @@ const docTemplate = `{
                 }
             }
         },
-        "models.Tenant": {
+        "models.TransferOrderLine": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "discrepancy": {
+                    "description": "dispatched minus received",
+                    "type": "integer"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "received_quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "transfer_order_id": {
+                    "type": "string"
+                },
+                "unit_cost": {
+                    "description": "average cost of the dispatched units",
+                    "type": "number"
+                }
+            }
+        },
+        "models.ValuationReport": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "hubs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HubValuation"
+                    }
+                },
+                "method": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "skus": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SkuValuation"
+                    }
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.VariantAttributes": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "models.VariantStock": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/models.VariantAttributes"
+                },
+                "hubs": {
+                    "description": "hubs holding any stock",
+                    "type": "integer"
+                },
+                "on_hand": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "description": "available: on hand minus active reservations",
+                    "type": "integer"
+                },
+                "reserved": {
+                    "type": "integer"
+                },
+                "sku_code": {
+                    "type": "string"
+                },
+                "sku_id": {
+                    "type": "string"
+                },
+                "sku_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "sourcing.Allocation": {
+            "type": "object",
+            "properties": {
+                "distance_km": {
+                    "type": "number"
+                },
+                "hub_id": {
+                    "type": "string"
+                },
+                "hub_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "sourcing.Destination": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "postal_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "sourcing.Line": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "sku_id"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "sourcing.Plan": {
+            "type": "object",
+            "properties": {
+                "allocations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sourcing.Allocation"
+                    }
+                },
+                "fulfillable": {
+                    "type": "boolean"
+                },
+                "hub_count": {
+                    "type": "integer"
+                },
+                "shortages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/sourcing.Shortage"
+                    }
+                },
+                "strategy": {
+                    "type": "string"
+                }
+            }
+        },
+        "sourcing.Shortage": {
+            "type": "object",
+            "properties": {
+                "short_by": {
+                    "type": "integer"
+                },
+                "sku_id": {
                     "type": "string"
                 }
             }
@@ -0,0 +1,29 @@
+-- Reservations table
+CREATE TABLE IF NOT EXISTS reservations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    order_reference TEXT NOT NULL,
+    hub_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status TEXT NOT NULL DEFAULT 'active',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    FOREIGN KEY (hub_id) REFERENCES hubs(id),
+    FOREIGN KEY (sku_id) REFERENCES skus(id)
+);
+
+-- Only one live hold per order line
+CREATE UNIQUE INDEX IF NOT EXISTS uq_reservations_active_line
+    ON reservations (order_reference, hub_id, sku_id)
+    WHERE status = 'active';
+
+CREATE INDEX IF NOT EXISTS idx_reservations_active_stock
+    ON reservations (hub_id, sku_id)
+    WHERE status = 'active';
+
+CREATE INDEX IF NOT EXISTS idx_reservations_active_expiry
+    ON reservations (expires_at)
+    WHERE status = 'active';
@@ -0,0 +1 @@
+ALTER TABLE inventories DROP CONSTRAINT IF EXISTS chk_inventories_quantity_non_negative;
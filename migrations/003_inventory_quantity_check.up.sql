@@ -0,0 +1,3 @@
+-- Stock can never be decremented below zero
+ALTER TABLE inventories
+    ADD CONSTRAINT chk_inventories_quantity_non_negative CHECK (quantity >= 0);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS inventory_movements;
+DROP FUNCTION IF EXISTS forbid_inventory_movement_changes();
@@ -0,0 +1,35 @@
+-- Inventory movements ledger
+CREATE TABLE IF NOT EXISTS inventory_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    inventory_id UUID NOT NULL,
+    hub_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    delta INTEGER NOT NULL,
+    quantity_before INTEGER NOT NULL,
+    quantity_after INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    source TEXT,
+    actor TEXT,
+    reference_id TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id)
+);
+
+-- No FK to inventories: history outlives a deleted inventory row
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_inventory_time
+    ON inventory_movements (inventory_id, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_hub_sku_time
+    ON inventory_movements (hub_id, sku_id, created_at);
+
+-- The ledger is append-only
+CREATE OR REPLACE FUNCTION forbid_inventory_movement_changes() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'inventory_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_inventory_movements_append_only
+    BEFORE UPDATE OR DELETE ON inventory_movements
+    FOR EACH ROW EXECUTE FUNCTION forbid_inventory_movement_changes();
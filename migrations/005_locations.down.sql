@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS bin_inventories;
+DROP TABLE IF EXISTS locations;
@@ -0,0 +1,29 @@
+-- Locations table: zone -> aisle -> bin tree inside a hub
+CREATE TABLE IF NOT EXISTS locations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    hub_id UUID NOT NULL,
+    parent_id UUID,
+    type TEXT NOT NULL CHECK (type IN ('zone', 'aisle', 'bin')),
+    code TEXT NOT NULL,
+    name TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (hub_id, code),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    FOREIGN KEY (hub_id) REFERENCES hubs(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES locations(id) ON DELETE CASCADE
+);
+
+-- Bin inventories: per-bin breakdown of a hub-level inventory row
+CREATE TABLE IF NOT EXISTS bin_inventories (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    inventory_id UUID NOT NULL,
+    bin_id UUID NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (inventory_id, bin_id),
+    FOREIGN KEY (inventory_id) REFERENCES inventories(id) ON DELETE CASCADE,
+    FOREIGN KEY (bin_id) REFERENCES locations(id) ON DELETE CASCADE
+);
@@ -0,0 +1,15 @@
+-- Inventory lots: lot/batch breakdown of a hub-level inventory row
+CREATE TABLE IF NOT EXISTS inventory_lots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    inventory_id UUID NOT NULL,
+    lot_number TEXT NOT NULL,
+    manufactured_at DATE,
+    expires_at DATE,
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (inventory_id, lot_number),
+    FOREIGN KEY (inventory_id) REFERENCES inventories(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_lots_expiry ON inventory_lots (expires_at) WHERE quantity > 0;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS serials;
+
+ALTER TABLE skus
+    DROP COLUMN IF EXISTS serialized;
@@ -0,0 +1,23 @@
+-- Serial-tracked SKUs keep one row per unit
+ALTER TABLE skus
+    ADD COLUMN IF NOT EXISTS serialized BOOLEAN NOT NULL DEFAULT false;
+
+-- Serials: inventories.quantity of a serialized SKU equals its in-stock serials at the hub
+CREATE TABLE IF NOT EXISTS serials (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    hub_id UUID NOT NULL,
+    serial_number TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('in_stock', 'allocated')),
+    order_reference TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (sku_id, serial_number),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    FOREIGN KEY (sku_id) REFERENCES skus(id) ON DELETE CASCADE,
+    FOREIGN KEY (hub_id) REFERENCES hubs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_serials_serial_number ON serials (serial_number);
+CREATE INDEX IF NOT EXISTS idx_serials_hub_sku_status ON serials (hub_id, sku_id, status);
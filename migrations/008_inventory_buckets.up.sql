@@ -0,0 +1,11 @@
+-- Non-sellable status buckets; inventories.quantity remains the sellable bucket
+CREATE TABLE IF NOT EXISTS inventory_buckets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    inventory_id UUID NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('damaged', 'quarantined', 'in_transit')),
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (inventory_id, status),
+    FOREIGN KEY (inventory_id) REFERENCES inventories(id) ON DELETE CASCADE
+);
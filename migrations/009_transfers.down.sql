@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS transfer_order_lines;
+DROP TABLE IF EXISTS transfer_orders;
@@ -0,0 +1,32 @@
+-- Transfer orders: stock moving between two hubs of a tenant
+CREATE TABLE IF NOT EXISTS transfer_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    source_hub_id UUID NOT NULL,
+    destination_hub_id UUID NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('created', 'dispatched', 'received')),
+    has_discrepancy BOOLEAN NOT NULL DEFAULT false,
+    dispatched_at TIMESTAMPTZ,
+    received_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (source_hub_id <> destination_hub_id),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    FOREIGN KEY (source_hub_id) REFERENCES hubs(id),
+    FOREIGN KEY (destination_hub_id) REFERENCES hubs(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_orders_tenant_status ON transfer_orders (tenant_id, status, created_at);
+
+-- Transfer lines: dispatched quantity, what arrived and the shortfall
+CREATE TABLE IF NOT EXISTS transfer_order_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transfer_order_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    received_quantity INTEGER NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    discrepancy INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (transfer_order_id, sku_id),
+    FOREIGN KEY (transfer_order_id) REFERENCES transfer_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (sku_id) REFERENCES skus(id)
+);
@@ -0,0 +1,32 @@
+-- Stocktake sessions: physical counts of a hub against system stock
+CREATE TABLE IF NOT EXISTS stocktakes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    hub_id UUID NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('open', 'approved')),
+    created_by TEXT,
+    approved_by TEXT,
+    approved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    FOREIGN KEY (hub_id) REFERENCES hubs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_stocktakes_hub_status ON stocktakes (hub_id, status);
+
+-- Stocktake lines: expected quantity snapshot, count and variance per SKU
+CREATE TABLE IF NOT EXISTS stocktake_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    stocktake_id UUID NOT NULL,
+    inventory_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    expected_quantity INTEGER NOT NULL,
+    counted_quantity INTEGER CHECK (counted_quantity >= 0),
+    variance INTEGER,
+    adjusted_quantity INTEGER,
+    counted_at TIMESTAMPTZ,
+    UNIQUE (stocktake_id, sku_id),
+    FOREIGN KEY (stocktake_id) REFERENCES stocktakes(id) ON DELETE CASCADE,
+    FOREIGN KEY (inventory_id) REFERENCES inventories(id) ON DELETE CASCADE
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS stock_alerts;
+
+ALTER TABLE inventories DROP CONSTRAINT IF EXISTS chk_inventories_thresholds;
+
+ALTER TABLE inventories
+    DROP COLUMN IF EXISTS min_quantity,
+    DROP COLUMN IF EXISTS reorder_point;
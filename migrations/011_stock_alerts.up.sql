@@ -0,0 +1,41 @@
+-- Low-stock thresholds per hub/SKU; NULL means no threshold
+ALTER TABLE inventories
+    ADD COLUMN IF NOT EXISTS min_quantity INTEGER,
+    ADD COLUMN IF NOT EXISTS reorder_point INTEGER;
+
+ALTER TABLE inventories
+    ADD CONSTRAINT chk_inventories_thresholds CHECK (
+        (min_quantity IS NULL OR min_quantity >= 0)
+        AND (reorder_point IS NULL OR reorder_point >= 0)
+        AND (min_quantity IS NULL OR reorder_point IS NULL OR min_quantity <= reorder_point)
+    );
+
+-- Stock alerts: raised when sellable quantity drops below a threshold
+CREATE TABLE IF NOT EXISTS stock_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    inventory_id UUID NOT NULL,
+    hub_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    level TEXT NOT NULL CHECK (level IN ('reorder', 'minimum')),
+    threshold INTEGER NOT NULL,
+    quantity INTEGER NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('open', 'acknowledged', 'resolved')),
+    acknowledged_by TEXT,
+    acknowledged_at TIMESTAMPTZ,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    FOREIGN KEY (inventory_id) REFERENCES inventories(id) ON DELETE CASCADE,
+    FOREIGN KEY (hub_id) REFERENCES hubs(id),
+    FOREIGN KEY (sku_id) REFERENCES skus(id)
+);
+
+-- At most one unresolved alert per inventory row and level
+CREATE UNIQUE INDEX IF NOT EXISTS uq_stock_alerts_active
+    ON stock_alerts (inventory_id, level)
+    WHERE status <> 'resolved';
+
+CREATE INDEX IF NOT EXISTS idx_stock_alerts_tenant_hub
+    ON stock_alerts (tenant_id, hub_id, status, created_at);
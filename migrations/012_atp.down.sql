@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_transfer_orders_inbound;
+
+ALTER TABLE transfer_orders
+    DROP COLUMN IF EXISTS expected_at;
+
+ALTER TABLE inventories
+    DROP COLUMN IF EXISTS safety_stock;
@@ -0,0 +1,11 @@
+-- Safety stock held back from available-to-promise per hub/SKU
+ALTER TABLE inventories
+    ADD COLUMN IF NOT EXISTS safety_stock INTEGER NOT NULL DEFAULT 0 CHECK (safety_stock >= 0);
+
+-- Expected arrival of a transfer; in-transit stock counts as inbound within the ATP horizon
+ALTER TABLE transfer_orders
+    ADD COLUMN IF NOT EXISTS expected_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_transfer_orders_inbound
+    ON transfer_orders (destination_hub_id, expected_at)
+    WHERE status = 'dispatched';
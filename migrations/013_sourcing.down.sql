@@ -0,0 +1,7 @@
+ALTER TABLE tenants
+    DROP COLUMN IF EXISTS sourcing_strategy;
+
+ALTER TABLE hubs
+    DROP COLUMN IF EXISTS postal_code,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS longitude;
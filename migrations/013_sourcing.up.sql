@@ -0,0 +1,9 @@
+-- Hub position used to rank hubs by distance to an order's destination
+ALTER TABLE hubs
+    ADD COLUMN IF NOT EXISTS postal_code TEXT,
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180);
+
+-- Sourcing strategy applied to the tenant's orders
+ALTER TABLE tenants
+    ADD COLUMN IF NOT EXISTS sourcing_strategy TEXT NOT NULL DEFAULT 'single_hub';
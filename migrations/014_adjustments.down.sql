@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS inventory_adjustments;
+DROP TABLE IF EXISTS adjustment_reason_codes;
+
+ALTER TABLE tenants
+    DROP COLUMN IF EXISTS adjustment_approval_units,
+    DROP COLUMN IF EXISTS adjustment_approval_percent;
@@ -0,0 +1,44 @@
+-- Adjustment approval thresholds per tenant; NULL means no limit
+ALTER TABLE tenants
+    ADD COLUMN IF NOT EXISTS adjustment_approval_units INTEGER CHECK (adjustment_approval_units >= 0),
+    ADD COLUMN IF NOT EXISTS adjustment_approval_percent NUMERIC(6, 2) CHECK (adjustment_approval_percent >= 0);
+
+-- Reason codes a tenant accepts on adjustments; tenants without any use the built-in set
+CREATE TABLE IF NOT EXISTS adjustment_reason_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    code TEXT NOT NULL,
+    description TEXT,
+    direction TEXT NOT NULL DEFAULT 'any' CHECK (direction IN ('increase', 'decrease', 'any')),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, code),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+-- Adjustments: justified quantity changes, applied at once or after approval
+CREATE TABLE IF NOT EXISTS inventory_adjustments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    inventory_id UUID NOT NULL,
+    hub_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    delta INTEGER NOT NULL CHECK (delta <> 0),
+    reason_code TEXT NOT NULL,
+    note TEXT,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'applied', 'rejected')),
+    requested_by TEXT NOT NULL,
+    reviewed_by TEXT,
+    reviewed_at TIMESTAMPTZ,
+    quantity_before INTEGER,
+    quantity_after INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    FOREIGN KEY (inventory_id) REFERENCES inventories(id) ON DELETE CASCADE,
+    FOREIGN KEY (hub_id) REFERENCES hubs(id),
+    FOREIGN KEY (sku_id) REFERENCES skus(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_adjustments_tenant_status
+    ON inventory_adjustments (tenant_id, status, created_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS advance_shipping_notice_lines;
+DROP TABLE IF EXISTS advance_shipping_notices;
+DROP TABLE IF EXISTS purchase_order_lines;
+DROP TABLE IF EXISTS purchase_orders;
@@ -0,0 +1,61 @@
+-- Purchase orders: stock a tenant expects from a supplier at one of its hubs
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    hub_id UUID NOT NULL,
+    reference TEXT,
+    supplier TEXT,
+    status TEXT NOT NULL CHECK (status IN ('open', 'partially_received', 'closed')),
+    has_discrepancy BOOLEAN NOT NULL DEFAULT false,
+    expected_at TIMESTAMPTZ,
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    FOREIGN KEY (hub_id) REFERENCES hubs(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_tenant_status ON purchase_orders (tenant_id, status, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_inbound
+    ON purchase_orders (hub_id, expected_at)
+    WHERE status <> 'closed';
+
+-- Purchase order lines: expected quantity, what has arrived so far and the difference
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    purchase_order_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    expected_quantity INTEGER NOT NULL CHECK (expected_quantity > 0),
+    received_quantity INTEGER NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    variance INTEGER NOT NULL,
+    UNIQUE (purchase_order_id, sku_id),
+    FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (sku_id) REFERENCES skus(id)
+);
+
+-- Advance shipping notices: a supplier's announcement of one shipment against a PO
+CREATE TABLE IF NOT EXISTS advance_shipping_notices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    purchase_order_id UUID NOT NULL,
+    reference TEXT,
+    status TEXT NOT NULL CHECK (status IN ('in_transit', 'received')),
+    expected_at TIMESTAMPTZ,
+    received_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_advance_shipping_notices_po ON advance_shipping_notices (purchase_order_id);
+
+CREATE TABLE IF NOT EXISTS advance_shipping_notice_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    advance_shipping_notice_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    received_quantity INTEGER NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    UNIQUE (advance_shipping_notice_id, sku_id),
+    FOREIGN KEY (advance_shipping_notice_id) REFERENCES advance_shipping_notices(id) ON DELETE CASCADE,
+    FOREIGN KEY (sku_id) REFERENCES skus(id)
+);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS return_ledger_entries;
+DROP TABLE IF EXISTS return_authorization_lines;
+DROP TABLE IF EXISTS return_authorizations;
+
+DELETE FROM inventory_buckets WHERE status = 'disposal';
+ALTER TABLE inventory_buckets DROP CONSTRAINT IF EXISTS inventory_buckets_status_check;
+ALTER TABLE inventory_buckets
+    ADD CONSTRAINT inventory_buckets_status_check CHECK (status IN ('damaged', 'quarantined', 'in_transit'));
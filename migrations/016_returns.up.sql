@@ -0,0 +1,56 @@
+-- Disposal bucket for returned units that cannot be resold
+ALTER TABLE inventory_buckets DROP CONSTRAINT IF EXISTS inventory_buckets_status_check;
+ALTER TABLE inventory_buckets
+    ADD CONSTRAINT inventory_buckets_status_check CHECK (status IN ('damaged', 'quarantined', 'in_transit', 'disposal'));
+
+-- Return authorizations (RMAs): units a customer is sending back to a hub
+CREATE TABLE IF NOT EXISTS return_authorizations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    hub_id UUID NOT NULL,
+    order_reference TEXT NOT NULL,
+    reason TEXT,
+    status TEXT NOT NULL CHECK (status IN ('open', 'received')),
+    received_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    FOREIGN KEY (hub_id) REFERENCES hubs(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_return_authorizations_tenant_order
+    ON return_authorizations (tenant_id, order_reference);
+
+-- Return lines: authorized quantity and how the received units were graded
+CREATE TABLE IF NOT EXISTS return_authorization_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    return_authorization_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    sellable_quantity INTEGER NOT NULL DEFAULT 0 CHECK (sellable_quantity >= 0),
+    damaged_quantity INTEGER NOT NULL DEFAULT 0 CHECK (damaged_quantity >= 0),
+    disposal_quantity INTEGER NOT NULL DEFAULT 0 CHECK (disposal_quantity >= 0),
+    UNIQUE (return_authorization_id, sku_id),
+    CHECK (sellable_quantity + damaged_quantity + disposal_quantity <= quantity),
+    FOREIGN KEY (return_authorization_id) REFERENCES return_authorizations(id) ON DELETE CASCADE,
+    FOREIGN KEY (sku_id) REFERENCES skus(id)
+);
+
+-- Return ledger: one row per SKU and grade received, kept per tenant
+CREATE TABLE IF NOT EXISTS return_ledger_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    return_authorization_id UUID NOT NULL,
+    order_reference TEXT NOT NULL,
+    hub_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    grade TEXT NOT NULL CHECK (grade IN ('sellable', 'damaged', 'disposal')),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    actor TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    FOREIGN KEY (return_authorization_id) REFERENCES return_authorizations(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_return_ledger_entries_tenant_time
+    ON return_ledger_entries (tenant_id, created_at);
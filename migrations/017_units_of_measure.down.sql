@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS sku_units;
+
+ALTER TABLE skus
+    DROP COLUMN IF EXISTS base_unit;
@@ -0,0 +1,16 @@
+-- Quantities of a SKU are stored in its base unit
+ALTER TABLE skus
+    ADD COLUMN IF NOT EXISTS base_unit TEXT NOT NULL DEFAULT 'each';
+
+-- Pack-size conversions: unit_quantity of unit equal base_quantity base units
+CREATE TABLE IF NOT EXISTS sku_units (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sku_id UUID NOT NULL,
+    unit TEXT NOT NULL,
+    unit_quantity INTEGER NOT NULL DEFAULT 1 CHECK (unit_quantity > 0),
+    base_quantity INTEGER NOT NULL CHECK (base_quantity > 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (sku_id, unit),
+    FOREIGN KEY (sku_id) REFERENCES skus(id) ON DELETE CASCADE
+);
@@ -0,0 +1,15 @@
+-- Bundles: a SKU sold as a kit of other SKUs holds no stock of its own
+CREATE TABLE IF NOT EXISTS bundle_components (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bundle_sku_id UUID NOT NULL,
+    component_sku_id UUID NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (bundle_sku_id, component_sku_id),
+    CHECK (bundle_sku_id <> component_sku_id),
+    FOREIGN KEY (bundle_sku_id) REFERENCES skus(id) ON DELETE CASCADE,
+    FOREIGN KEY (component_sku_id) REFERENCES skus(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bundle_components_component ON bundle_components (component_sku_id);
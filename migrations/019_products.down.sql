@@ -0,0 +1,5 @@
+ALTER TABLE skus
+    DROP COLUMN IF EXISTS attributes,
+    DROP COLUMN IF EXISTS product_id;
+
+DROP TABLE IF EXISTS products;
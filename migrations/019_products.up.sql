@@ -0,0 +1,18 @@
+-- Products group the SKUs that are variants (size, colour, ...) of one item
+CREATE TABLE IF NOT EXISTS products (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+ALTER TABLE skus
+    ADD COLUMN IF NOT EXISTS product_id UUID REFERENCES products(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_products_tenant ON products (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_skus_product ON skus (product_id);
+CREATE INDEX IF NOT EXISTS idx_skus_attributes ON skus USING GIN (attributes);
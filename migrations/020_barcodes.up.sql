@@ -0,0 +1,14 @@
+-- Barcodes: any number per SKU; a code resolves to one SKU within a tenant
+CREATE TABLE IF NOT EXISTS sku_barcodes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    barcode TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('ean13', 'upca', 'gtin14', 'internal')),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, barcode),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    FOREIGN KEY (sku_id) REFERENCES skus(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sku_barcodes_sku ON sku_barcodes (sku_id);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS cost_layer_consumptions;
+DROP TABLE IF EXISTS cost_layers;
+
+ALTER TABLE transfer_order_lines DROP COLUMN IF EXISTS unit_cost;
+ALTER TABLE purchase_order_lines DROP COLUMN IF EXISTS unit_cost;
+ALTER TABLE tenants DROP COLUMN IF EXISTS valuation_method;
@@ -0,0 +1,48 @@
+-- How a tenant values stock: oldest cost first, or a running average
+ALTER TABLE tenants
+    ADD COLUMN IF NOT EXISTS valuation_method TEXT NOT NULL DEFAULT 'fifo'
+        CHECK (valuation_method IN ('fifo', 'weighted_average'));
+
+-- Unit cost agreed on purchase order lines, booked when the line is received
+ALTER TABLE purchase_order_lines
+    ADD COLUMN IF NOT EXISTS unit_cost NUMERIC(14, 4) CHECK (unit_cost >= 0);
+
+-- Cost of the units a transfer took out of its source hub, carried to the destination
+ALTER TABLE transfer_order_lines
+    ADD COLUMN IF NOT EXISTS unit_cost NUMERIC(14, 4) CHECK (unit_cost >= 0);
+
+-- Cost layers: units that came into a hub together at one unit cost
+CREATE TABLE IF NOT EXISTS cost_layers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL,
+    hub_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    remaining INTEGER NOT NULL CHECK (remaining >= 0 AND remaining <= quantity),
+    unit_cost NUMERIC(14, 4) NOT NULL CHECK (unit_cost >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    FOREIGN KEY (hub_id) REFERENCES hubs(id) ON DELETE CASCADE,
+    FOREIGN KEY (sku_id) REFERENCES skus(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_cost_layers_open
+    ON cost_layers (hub_id, sku_id, created_at) WHERE remaining > 0;
+CREATE INDEX IF NOT EXISTS idx_cost_layers_tenant_created ON cost_layers (tenant_id, created_at);
+
+-- What left each layer and when, so value can be rebuilt as of any date
+CREATE TABLE IF NOT EXISTS cost_layer_consumptions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cost_layer_id UUID NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (cost_layer_id) REFERENCES cost_layers(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_cost_layer_consumptions_layer ON cost_layer_consumptions (cost_layer_id, created_at);
+
+-- Stock already on hand has no known cost; open it at zero so layers match quantities
+INSERT INTO cost_layers (tenant_id, hub_id, sku_id, quantity, remaining, unit_cost)
+SELECT tenant_id, hub_id, sku_id, quantity, quantity, 0
+FROM inventories
+WHERE quantity > 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_inventory_movements_hub_time;
+DROP TABLE IF EXISTS inventory_snapshot_runs;
+DROP TABLE IF EXISTS inventory_snapshots;
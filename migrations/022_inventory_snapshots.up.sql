@@ -0,0 +1,27 @@
+-- Daily copies of every inventory row, partitioned by month of snapshot_date.
+-- Partitions are created by the snapshot job as it needs them.
+CREATE TABLE IF NOT EXISTS inventory_snapshots (
+    snapshot_date DATE NOT NULL,
+    inventory_id UUID NOT NULL,
+    tenant_id UUID NOT NULL,
+    hub_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    quantity INTEGER NOT NULL,
+    PRIMARY KEY (snapshot_date, inventory_id)
+) PARTITION BY RANGE (snapshot_date);
+
+-- No FKs: snapshots outlive deleted hubs, SKUs and inventory rows, like the ledger
+CREATE INDEX IF NOT EXISTS idx_inventory_snapshots_hub ON inventory_snapshots (snapshot_date, hub_id);
+
+-- One run per day; taken_at is the instant the copied quantities stood at
+CREATE TABLE IF NOT EXISTS inventory_snapshot_runs (
+    snapshot_date DATE PRIMARY KEY,
+    taken_at TIMESTAMPTZ NOT NULL,
+    row_count INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_snapshot_runs_taken ON inventory_snapshot_runs (taken_at);
+
+-- Ledger replay from a snapshot reads a hub's movements over a time range
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_hub_time
+    ON inventory_movements (hub_id, created_at);
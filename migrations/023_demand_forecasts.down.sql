@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_inventory_movements_demand;
+DROP TABLE IF EXISTS demand_forecasts;
+
+ALTER TABLE inventories DROP CONSTRAINT IF EXISTS chk_inventories_replenishment;
+
+ALTER TABLE inventories
+    DROP COLUMN IF EXISTS lead_time_days,
+    DROP COLUMN IF EXISTS target_days_of_cover;
@@ -0,0 +1,37 @@
+-- Replenishment settings per hub/SKU; NULL falls back to the defaults
+ALTER TABLE inventories
+    ADD COLUMN IF NOT EXISTS lead_time_days INTEGER,
+    ADD COLUMN IF NOT EXISTS target_days_of_cover INTEGER;
+
+ALTER TABLE inventories
+    ADD CONSTRAINT chk_inventories_replenishment CHECK (
+        (lead_time_days IS NULL OR lead_time_days >= 0)
+        AND (target_days_of_cover IS NULL OR target_days_of_cover >= 0)
+    );
+
+-- Latest demand forecast and reorder suggestion per hub/SKU, rebuilt nightly
+CREATE TABLE IF NOT EXISTS demand_forecasts (
+    hub_id UUID NOT NULL,
+    sku_id UUID NOT NULL,
+    tenant_id UUID NOT NULL,
+    forecast_date DATE NOT NULL,
+    moving_average NUMERIC(14,4) NOT NULL,
+    exponential_smoothing NUMERIC(14,4) NOT NULL,
+    lead_time_days INTEGER NOT NULL,
+    target_days_of_cover INTEGER NOT NULL,
+    on_hand INTEGER NOT NULL,
+    reserved INTEGER NOT NULL,
+    inbound INTEGER NOT NULL,
+    safety_stock INTEGER NOT NULL,
+    suggested_quantity INTEGER NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (hub_id, sku_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_demand_forecasts_tenant ON demand_forecasts (tenant_id, hub_id);
+CREATE INDEX IF NOT EXISTS idx_demand_forecasts_date ON demand_forecasts (forecast_date);
+
+-- Demand is read per tenant over the lookback window
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_demand
+    ON inventory_movements (tenant_id, created_at)
+    WHERE reason IN ('order_consumption', 'reservation_commit', 'serial_dispatch');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS uq_sku_barcodes_gtin;
+
+ALTER TABLE sku_barcodes DROP COLUMN IF EXISTS gtin;
@@ -0,0 +1,8 @@
+-- GS1 barcodes stored as GTIN-14 (zero-padded), so scans of any length hit an index
+ALTER TABLE sku_barcodes ADD COLUMN IF NOT EXISTS gtin TEXT;
+
+UPDATE sku_barcodes SET gtin = LPAD(barcode, 14, '0') WHERE type <> 'internal';
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_sku_barcodes_gtin
+    ON sku_barcodes (tenant_id, gtin)
+    WHERE gtin IS NOT NULL;
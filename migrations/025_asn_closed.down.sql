@@ -0,0 +1,6 @@
+UPDATE advance_shipping_notices SET status = 'received' WHERE status = 'closed';
+
+ALTER TABLE advance_shipping_notices DROP CONSTRAINT IF EXISTS advance_shipping_notices_status_check;
+
+ALTER TABLE advance_shipping_notices ADD CONSTRAINT advance_shipping_notices_status_check
+    CHECK (status IN ('in_transit', 'received'));
@@ -0,0 +1,5 @@
+-- Notices still in transit when their purchase order closes are closed with it
+ALTER TABLE advance_shipping_notices DROP CONSTRAINT IF EXISTS advance_shipping_notices_status_check;
+
+ALTER TABLE advance_shipping_notices ADD CONSTRAINT advance_shipping_notices_status_check
+    CHECK (status IN ('in_transit', 'received', 'closed'));
@@ -0,0 +1 @@
+ALTER TABLE stocktake_lines DROP COLUMN IF EXISTS adjustment_id;
@@ -0,0 +1,3 @@
+-- Variances of an approved stocktake are booked as inventory adjustments
+ALTER TABLE stocktake_lines ADD COLUMN IF NOT EXISTS adjustment_id UUID
+    REFERENCES inventory_adjustments(id) ON DELETE SET NULL;
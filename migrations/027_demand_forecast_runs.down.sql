@@ -0,0 +1 @@
+DROP TABLE IF EXISTS demand_forecast_runs;
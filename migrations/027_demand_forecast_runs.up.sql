@@ -0,0 +1,9 @@
+-- One row per tenant and day claims that day's forecast refresh
+CREATE TABLE IF NOT EXISTS demand_forecast_runs (
+    tenant_id UUID NOT NULL,
+    forecast_date DATE NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL,
+    row_count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, forecast_date),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
@@ -0,0 +1,19 @@
+package configs
+
+import (
+	"context"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/constants"
+	"github.com/omniful/go_commons/config"
+)
+
+// GetReservationTTL returns how long an uncommitted reservation holds stock,
+// falling back to the default when reservation.ttl is unset or malformed.
+func GetReservationTTL(ctx context.Context) time.Duration {
+	ttl, err := time.ParseDuration(config.GetString(ctx, "reservation.ttl"))
+	if err != nil || ttl <= 0 {
+		return constants.DefaultReservationTTL
+	}
+	return ttl
+}
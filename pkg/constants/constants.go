@@ -3,4 +3,36 @@ package constants
 import "time"
 
 const SkuCacheTTL = 5 * time.Minute
-const RedisCacheTTL = time.Hour
\ No newline at end of file
+const RedisCacheTTL = time.Hour
+
+// Reservations
+const DefaultReservationTTL = 15 * time.Minute
+const ReservationExpiryInterval = time.Minute
+
+// Pagination
+const DefaultPageSize = 50
+const MaxPageSize = 500
+
+// Lots
+const DefaultLotExpiryWindowDays = 30
+
+// Stock alerts
+const StockAlertQueueSize = 1024
+const StockAlertSweepInterval = 10 * time.Minute
+const StockAlertReopenWindow = time.Hour
+
+// Available-to-promise
+const DefaultATPHorizonDays = 7
+const MaxATPHorizonDays = 90
+const MaxATPBatchSize = 200
+
+// Inventory snapshots
+const InventorySnapshotInterval = time.Hour // each run takes the day's snapshot if it is still missing
+
+// Demand forecasting
+const ForecastLookbackDays = 28
+const ForecastMovingAverageDays = 7
+const ForecastSmoothingAlpha = 0.3
+const DefaultLeadTimeDays = 7
+const DefaultTargetDaysOfCover = 14
+const DemandForecastInterval = time.Hour // each run rebuilds the forecasts once per day
@@ -0,0 +1,331 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+var adjustmentStatuses = map[string]bool{
+	models.AdjustmentPending:  true,
+	models.AdjustmentApplied:  true,
+	models.AdjustmentRejected: true,
+}
+
+var reasonCodeDirections = map[string]bool{
+	models.AdjustmentIncrease: true,
+	models.AdjustmentDecrease: true,
+	models.AdjustmentAny:      true,
+}
+
+type CreateAdjustmentRequest struct {
+	InventoryID uuid.UUID `json:"inventory_id" binding:"required"`
+	Delta       int       `json:"delta"`
+	ReasonCode  string    `json:"reason_code" binding:"required"`
+	Note        string    `json:"note"`
+}
+
+type CreateReasonCodeRequest struct {
+	Code        string `json:"code" binding:"required"`
+	Description string `json:"description"`
+	Direction   string `json:"direction"`
+}
+
+// adjustmentError maps model errors shared by the adjustment endpoints.
+func adjustmentError(err error, fallback string) (int, error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return int(http.StatusNotFound), errors.New("adjustment not found")
+	case errors.Is(err, models.ErrSelfApproval), errors.Is(err, models.ErrAdjustmentState), errors.Is(err, models.ErrInsufficientStock):
+		return int(http.StatusBadRequest), err
+	default:
+		return int(http.StatusInternalServerError), errors.New(fallback)
+	}
+}
+
+// isQuantityAdjustmentError reports errors from routing a write's quantity
+// change through the adjustment flow that the caller can fix.
+func isQuantityAdjustmentError(err error) bool {
+	return errors.Is(err, models.ErrQuantityNeedsReason) || errors.Is(err, models.ErrInvalidReasonCode) ||
+		errors.Is(err, models.ErrInvalidAdjustment) || errors.Is(err, models.ErrInsufficientStock)
+}
+
+// CreateAdjustment
+
+type AdjustmentCreator interface {
+	CreateAdjustment(ctx context.Context, adj *models.InventoryAdjustment) error
+}
+
+func createAdjustmentLogic(service AdjustmentCreator, tenantIDStr string, req CreateAdjustmentRequest, meta models.MovementMeta) (*models.InventoryAdjustment, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+	if meta.Actor == "" {
+		return nil, int(http.StatusBadRequest), errors.New("X-User-ID header is required")
+	}
+	if req.Delta == 0 {
+		return nil, int(http.StatusBadRequest), errors.New("delta must not be zero")
+	}
+
+	adj := &models.InventoryAdjustment{
+		TenantID:    tenantID,
+		InventoryID: req.InventoryID,
+		Delta:       req.Delta,
+		ReasonCode:  req.ReasonCode,
+		Note:        req.Note,
+		RequestedBy: meta.Actor,
+	}
+	if err := service.CreateAdjustment(models.WithMovementMeta(context.Background(), meta), adj); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, int(http.StatusNotFound), errors.New("inventory not found")
+		case errors.Is(err, models.ErrInvalidReasonCode), errors.Is(err, models.ErrInvalidAdjustment), errors.Is(err, models.ErrSerializedSku):
+			return nil, int(http.StatusBadRequest), err
+		}
+		status, err := adjustmentError(err, "failed to create adjustment")
+		return nil, status, err
+	}
+
+	return adj, int(http.StatusCreated), nil
+}
+
+// CreateAdjustment godoc
+// @Summary Adjust an inventory row with a reason code; adjustments above the tenant's thresholds are left pending for approval
+// @Tags Adjustments
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param X-User-ID header string true "Requesting user"
+// @Param payload body CreateAdjustmentRequest true "Adjustment"
+// @Success 201 {object} models.InventoryAdjustment
+// @Router /adjustments [post]
+func CreateAdjustment(c *gin.Context) {
+	var req CreateAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	adj, status, err := createAdjustmentLogic(models.AdjustmentModel{}, c.GetHeader("X-Tenant-ID"), req, movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, adj)
+}
+
+// GetAdjustments
+
+type AdjustmentFetcher interface {
+	GetAdjustments(ctx context.Context, tenantID uuid.UUID, status string) ([]models.InventoryAdjustment, error)
+}
+
+func getAdjustmentsLogic(service AdjustmentFetcher, tenantIDStr, status string) ([]models.InventoryAdjustment, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+	if status != "" && !adjustmentStatuses[status] {
+		return nil, int(http.StatusBadRequest), errors.New("invalid status")
+	}
+
+	adjustments, err := service.GetAdjustments(context.Background(), tenantID, status)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch adjustments")
+	}
+
+	return adjustments, int(http.StatusOK), nil
+}
+
+// GetAdjustments godoc
+// @Summary List the tenant's adjustments, newest first; filter by status to build the approval queue
+// @Tags Adjustments
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param status query string false "pending, applied or rejected"
+// @Success 200 {array} models.InventoryAdjustment
+// @Router /adjustments [get]
+func GetAdjustments(c *gin.Context) {
+	adjustments, status, err := getAdjustmentsLogic(models.AdjustmentModel{}, c.GetHeader("X-Tenant-ID"), c.Query("status"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, adjustments)
+}
+
+// ReviewAdjustment
+
+type AdjustmentReviewer interface {
+	ApproveAdjustment(ctx context.Context, tenantID, id uuid.UUID, approver string) (*models.InventoryAdjustment, error)
+	RejectAdjustment(ctx context.Context, tenantID, id uuid.UUID, reviewer string) (*models.InventoryAdjustment, error)
+}
+
+func reviewAdjustmentLogic(service AdjustmentReviewer, tenantIDStr, idStr string, approve bool, meta models.MovementMeta) (*models.InventoryAdjustment, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+	if meta.Actor == "" {
+		return nil, int(http.StatusBadRequest), errors.New("X-User-ID header is required")
+	}
+
+	ctx := models.WithMovementMeta(context.Background(), meta)
+	var adj *models.InventoryAdjustment
+	if approve {
+		adj, err = service.ApproveAdjustment(ctx, tenantID, id, meta.Actor)
+	} else {
+		adj, err = service.RejectAdjustment(ctx, tenantID, id, meta.Actor)
+	}
+	if err != nil {
+		status, err := adjustmentError(err, "failed to review adjustment")
+		return nil, status, err
+	}
+
+	return adj, int(http.StatusOK), nil
+}
+
+// ApproveAdjustment godoc
+// @Summary Approve a pending adjustment and apply it; the approver must not be the requester
+// @Tags Adjustments
+// @Produce json
+// @Param id path string true "Adjustment ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param X-User-ID header string true "Approving user"
+// @Success 200 {object} models.InventoryAdjustment
+// @Router /adjustments/{id}/approve [post]
+func ApproveAdjustment(c *gin.Context) {
+	adj, status, err := reviewAdjustmentLogic(models.AdjustmentModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"), true, movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, adj)
+}
+
+// RejectAdjustment godoc
+// @Summary Reject a pending adjustment without changing stock
+// @Tags Adjustments
+// @Produce json
+// @Param id path string true "Adjustment ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param X-User-ID header string true "Reviewing user"
+// @Success 200 {object} models.InventoryAdjustment
+// @Router /adjustments/{id}/reject [post]
+func RejectAdjustment(c *gin.Context) {
+	adj, status, err := reviewAdjustmentLogic(models.AdjustmentModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"), false, movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, adj)
+}
+
+// GetAdjustmentReasonCodes
+
+type ReasonCodeFetcher interface {
+	GetAdjustmentReasonCodes(ctx context.Context, tenantID uuid.UUID) ([]models.AdjustmentReasonCode, error)
+}
+
+func getReasonCodesLogic(service ReasonCodeFetcher, tenantIDStr string) ([]models.AdjustmentReasonCode, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	codes, err := service.GetAdjustmentReasonCodes(context.Background(), tenantID)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch reason codes")
+	}
+
+	return codes, int(http.StatusOK), nil
+}
+
+// GetAdjustmentReasonCodes godoc
+// @Summary List the tenant's adjustment reason codes, or the built-in set if it has none
+// @Tags Adjustments
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {array} models.AdjustmentReasonCode
+// @Router /adjustments/reason-codes [get]
+func GetAdjustmentReasonCodes(c *gin.Context) {
+	codes, status, err := getReasonCodesLogic(models.AdjustmentModel{}, c.GetHeader("X-Tenant-ID"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, codes)
+}
+
+// CreateAdjustmentReasonCode
+
+type ReasonCodeCreator interface {
+	CreateAdjustmentReasonCode(ctx context.Context, code *models.AdjustmentReasonCode) error
+}
+
+func createReasonCodeLogic(service ReasonCodeCreator, tenantIDStr string, req CreateReasonCodeRequest) (*models.AdjustmentReasonCode, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	code := strings.ToLower(strings.TrimSpace(req.Code))
+	if code == "" {
+		return nil, int(http.StatusBadRequest), errors.New("code is required")
+	}
+	direction := req.Direction
+	if direction == "" {
+		direction = models.AdjustmentAny
+	}
+	if !reasonCodeDirections[direction] {
+		return nil, int(http.StatusBadRequest), errors.New("invalid direction")
+	}
+
+	reasonCode := &models.AdjustmentReasonCode{TenantID: tenantID, Code: code, Description: req.Description, Direction: direction}
+	if err := service.CreateAdjustmentReasonCode(context.Background(), reasonCode); err != nil {
+		if errors.Is(err, models.ErrReasonCodeExists) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to create reason code")
+	}
+
+	return reasonCode, int(http.StatusCreated), nil
+}
+
+// CreateAdjustmentReasonCode godoc
+// @Summary Add an adjustment reason code for the tenant; once it has one, the built-in set no longer applies
+// @Tags Adjustments
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body CreateReasonCodeRequest true "Reason code"
+// @Success 201 {object} models.AdjustmentReasonCode
+// @Router /adjustments/reason-codes [post]
+func CreateAdjustmentReasonCode(c *gin.Context) {
+	var req CreateReasonCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	code, status, err := createReasonCodeLogic(models.AdjustmentModel{}, c.GetHeader("X-Tenant-ID"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, code)
+}
@@ -0,0 +1,393 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockAdjustmentService struct {
+	CreateAdjustmentFunc           func(ctx context.Context, adj *models.InventoryAdjustment) error
+	GetAdjustmentsFunc             func(ctx context.Context, tenantID uuid.UUID, status string) ([]models.InventoryAdjustment, error)
+	ApproveAdjustmentFunc          func(ctx context.Context, tenantID, id uuid.UUID, approver string) (*models.InventoryAdjustment, error)
+	RejectAdjustmentFunc           func(ctx context.Context, tenantID, id uuid.UUID, reviewer string) (*models.InventoryAdjustment, error)
+	GetAdjustmentReasonCodesFunc   func(ctx context.Context, tenantID uuid.UUID) ([]models.AdjustmentReasonCode, error)
+	CreateAdjustmentReasonCodeFunc func(ctx context.Context, code *models.AdjustmentReasonCode) error
+}
+
+func (m *mockAdjustmentService) CreateAdjustment(ctx context.Context, adj *models.InventoryAdjustment) error {
+	return m.CreateAdjustmentFunc(ctx, adj)
+}
+
+func (m *mockAdjustmentService) GetAdjustments(ctx context.Context, tenantID uuid.UUID, status string) ([]models.InventoryAdjustment, error) {
+	return m.GetAdjustmentsFunc(ctx, tenantID, status)
+}
+
+func (m *mockAdjustmentService) ApproveAdjustment(ctx context.Context, tenantID, id uuid.UUID, approver string) (*models.InventoryAdjustment, error) {
+	return m.ApproveAdjustmentFunc(ctx, tenantID, id, approver)
+}
+
+func (m *mockAdjustmentService) RejectAdjustment(ctx context.Context, tenantID, id uuid.UUID, reviewer string) (*models.InventoryAdjustment, error) {
+	return m.RejectAdjustmentFunc(ctx, tenantID, id, reviewer)
+}
+
+func (m *mockAdjustmentService) GetAdjustmentReasonCodes(ctx context.Context, tenantID uuid.UUID) ([]models.AdjustmentReasonCode, error) {
+	return m.GetAdjustmentReasonCodesFunc(ctx, tenantID)
+}
+
+func (m *mockAdjustmentService) CreateAdjustmentReasonCode(ctx context.Context, code *models.AdjustmentReasonCode) error {
+	return m.CreateAdjustmentReasonCodeFunc(ctx, code)
+}
+
+func TestCreateAdjustmentLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	req := CreateAdjustmentRequest{InventoryID: uuid.New(), Delta: -3, ReasonCode: "damage"}
+	meta := models.MovementMeta{Actor: "alice"}
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		req            CreateAdjustmentRequest
+		meta           models.MovementMeta
+		mockFunc       func(ctx context.Context, adj *models.InventoryAdjustment) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad",
+			req:            req,
+			meta:           meta,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "missing user",
+			tenantID:       tenantID,
+			req:            req,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "zero delta",
+			tenantID:       tenantID,
+			req:            CreateAdjustmentRequest{InventoryID: req.InventoryID, ReasonCode: "damage"},
+			meta:           meta,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "inventory not found",
+			tenantID: tenantID,
+			req:      req,
+			meta:     meta,
+			mockFunc: func(ctx context.Context, adj *models.InventoryAdjustment) error {
+				return gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:     "unknown reason code",
+			tenantID: tenantID,
+			req:      req,
+			meta:     meta,
+			mockFunc: func(ctx context.Context, adj *models.InventoryAdjustment) error {
+				return models.ErrInvalidReasonCode
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "reason code in the wrong direction",
+			tenantID: tenantID,
+			req:      req,
+			meta:     meta,
+			mockFunc: func(ctx context.Context, adj *models.InventoryAdjustment) error {
+				return fmt.Errorf("%w: found only allows an increase", models.ErrInvalidAdjustment)
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "insufficient stock",
+			tenantID: tenantID,
+			req:      req,
+			meta:     meta,
+			mockFunc: func(ctx context.Context, adj *models.InventoryAdjustment) error {
+				return models.ErrInsufficientStock
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "applied straight away",
+			tenantID: tenantID,
+			req:      req,
+			meta:     meta,
+			mockFunc: func(ctx context.Context, adj *models.InventoryAdjustment) error {
+				if adj.RequestedBy != "alice" {
+					return errors.New("requester not set")
+				}
+				adj.Status = models.AdjustmentApplied
+				return nil
+			},
+			expectedStatus: int(http.StatusCreated),
+		},
+		{
+			name:     "held for approval",
+			tenantID: tenantID,
+			req:      req,
+			meta:     meta,
+			mockFunc: func(ctx context.Context, adj *models.InventoryAdjustment) error {
+				adj.Status = models.AdjustmentPending
+				return nil
+			},
+			expectedStatus: int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockAdjustmentService{CreateAdjustmentFunc: tt.mockFunc}
+			adj, status, err := createAdjustmentLogic(mock, tt.tenantID, tt.req, tt.meta)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, adj)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, adj)
+			}
+		})
+	}
+}
+
+func TestGetAdjustmentsLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		status         string
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, status string) ([]models.InventoryAdjustment, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid status",
+			tenantID:       tenantID,
+			status:         "approved",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "db error",
+			tenantID: tenantID,
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, status string) ([]models.InventoryAdjustment, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:     "pending queue",
+			tenantID: tenantID,
+			status:   models.AdjustmentPending,
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, status string) ([]models.InventoryAdjustment, error) {
+				return []models.InventoryAdjustment{{Status: status}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockAdjustmentService{GetAdjustmentsFunc: tt.mockFunc}
+			adjustments, status, err := getAdjustmentsLogic(mock, tt.tenantID, tt.status)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, adjustments, 1)
+			}
+		})
+	}
+}
+
+func TestReviewAdjustmentLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	id := uuid.New().String()
+	meta := models.MovementMeta{Actor: "bob"}
+
+	tests := []struct {
+		name           string
+		idStr          string
+		approve        bool
+		meta           models.MovementMeta
+		approveFunc    func(ctx context.Context, tenantID, id uuid.UUID, approver string) (*models.InventoryAdjustment, error)
+		rejectFunc     func(ctx context.Context, tenantID, id uuid.UUID, reviewer string) (*models.InventoryAdjustment, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid id",
+			idStr:          "bad",
+			approve:        true,
+			meta:           meta,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "missing user",
+			idStr:          id,
+			approve:        true,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:    "not found",
+			idStr:   id,
+			approve: true,
+			meta:    meta,
+			approveFunc: func(ctx context.Context, tenantID, id uuid.UUID, approver string) (*models.InventoryAdjustment, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:    "self approval",
+			idStr:   id,
+			approve: true,
+			meta:    meta,
+			approveFunc: func(ctx context.Context, tenantID, id uuid.UUID, approver string) (*models.InventoryAdjustment, error) {
+				return nil, models.ErrSelfApproval
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:    "already reviewed",
+			idStr:   id,
+			approve: false,
+			meta:    meta,
+			rejectFunc: func(ctx context.Context, tenantID, id uuid.UUID, reviewer string) (*models.InventoryAdjustment, error) {
+				return nil, models.ErrAdjustmentState
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:    "approved",
+			idStr:   id,
+			approve: true,
+			meta:    meta,
+			approveFunc: func(ctx context.Context, tenantID, id uuid.UUID, approver string) (*models.InventoryAdjustment, error) {
+				return &models.InventoryAdjustment{Status: models.AdjustmentApplied, ReviewedBy: approver}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:    "rejected",
+			idStr:   id,
+			approve: false,
+			meta:    meta,
+			rejectFunc: func(ctx context.Context, tenantID, id uuid.UUID, reviewer string) (*models.InventoryAdjustment, error) {
+				return &models.InventoryAdjustment{Status: models.AdjustmentRejected, ReviewedBy: reviewer}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockAdjustmentService{ApproveAdjustmentFunc: tt.approveFunc, RejectAdjustmentFunc: tt.rejectFunc}
+			adj, status, err := reviewAdjustmentLogic(mock, tenantID, tt.idStr, tt.approve, tt.meta)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "bob", adj.ReviewedBy)
+			}
+		})
+	}
+}
+
+func TestCreateReasonCodeLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		req            CreateReasonCodeRequest
+		mockFunc       func(ctx context.Context, code *models.AdjustmentReasonCode) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "blank code",
+			req:            CreateReasonCodeRequest{Code: "  "},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid direction",
+			req:            CreateReasonCodeRequest{Code: "expired", Direction: "down"},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "duplicate code",
+			req:  CreateReasonCodeRequest{Code: "damage"},
+			mockFunc: func(ctx context.Context, code *models.AdjustmentReasonCode) error {
+				return models.ErrReasonCodeExists
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "created with default direction",
+			req:  CreateReasonCodeRequest{Code: " Expired "},
+			mockFunc: func(ctx context.Context, code *models.AdjustmentReasonCode) error {
+				if code.Code != "expired" || code.Direction != models.AdjustmentAny {
+					return errors.New("code not normalised")
+				}
+				return nil
+			},
+			expectedStatus: int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockAdjustmentService{CreateAdjustmentReasonCodeFunc: tt.mockFunc}
+			code, status, err := createReasonCodeLogic(mock, tenantID, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, code)
+			}
+		})
+	}
+}
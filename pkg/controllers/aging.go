@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+)
+
+var agingCSVHeader = []string{
+	"hub_id", "hub_name", "sku_id", "sku_code", "sku_name", "on_hand",
+	"days_0_30", "days_31_60", "days_61_90", "days_90_plus", "oldest_age_days",
+}
+
+// agingCSV renders the aging report with one row per hub and SKU.
+func agingCSV(rows []models.InventoryAging) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(agingCSVHeader); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		record := []string{
+			row.HubID.String(), row.HubName, row.SkuID.String(), row.SkuCode, row.SkuName,
+			strconv.Itoa(row.OnHand),
+			strconv.Itoa(row.Days0To30), strconv.Itoa(row.Days31To60),
+			strconv.Itoa(row.Days61To90), strconv.Itoa(row.Days90Plus),
+			strconv.Itoa(row.OldestAgeDays),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// GetInventoryAging
+
+type InventoryAgingFetcher interface {
+	GetInventoryAging(ctx context.Context, tenantID uuid.UUID, hubID *uuid.UUID) ([]models.InventoryAging, error)
+}
+
+func getInventoryAgingLogic(service InventoryAgingFetcher, tenantIDStr, hubIDStr, format string) ([]models.InventoryAging, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	if format != "" && format != "json" && format != "csv" {
+		return nil, int(http.StatusBadRequest), errors.New("invalid format, expected json or csv")
+	}
+
+	var hubID *uuid.UUID
+	if hubIDStr != "" {
+		id, err := uuid.Parse(hubIDStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid hub_id")
+		}
+		hubID = &id
+	}
+
+	rows, err := service.GetInventoryAging(context.Background(), tenantID, hubID)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to age inventory")
+	}
+
+	return rows, int(http.StatusOK), nil
+}
+
+// GetInventoryAging godoc
+// @Summary Age on-hand stock per hub and SKU into 0-30, 31-60, 61-90 and 90+ day buckets
+// @Tags Inventories
+// @Produce json
+// @Produce text/csv
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param hub_id query string false "Only age this hub"
+// @Param format query string false "json (default) or csv to download"
+// @Success 200 {array} models.InventoryAging
+// @Router /inventories/aging [get]
+func GetInventoryAging(c *gin.Context) {
+	format := c.Query("format")
+	rows, status, err := getInventoryAgingLogic(models.InventoryModel{}, c.GetHeader("X-Tenant-ID"), c.Query("hub_id"), format)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	if format != "csv" {
+		c.JSON(status, rows)
+		return
+	}
+
+	body, err := agingCSV(rows)
+	if err != nil {
+		c.JSON(int(http.StatusInternalServerError), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "failed to write csv")})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="inventory-aging-%s.csv"`, time.Now().UTC().Format(time.DateOnly)))
+	c.Data(status, "text/csv", body)
+}
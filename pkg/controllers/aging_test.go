@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockInventoryAgingFetcher struct {
+	GetInventoryAgingFunc func(ctx context.Context, tenantID uuid.UUID, hubID *uuid.UUID) ([]models.InventoryAging, error)
+}
+
+func (m *mockInventoryAgingFetcher) GetInventoryAging(ctx context.Context, tenantID uuid.UUID, hubID *uuid.UUID) ([]models.InventoryAging, error) {
+	return m.GetInventoryAgingFunc(ctx, tenantID, hubID)
+}
+
+func TestGetInventoryAgingLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	hubID := uuid.New()
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		hubID          string
+		format         string
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, hubID *uuid.UUID) ([]models.InventoryAging, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "unknown format",
+			tenantID:       tenantID,
+			format:         "xlsx",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid hub id",
+			tenantID:       tenantID,
+			hubID:          "bad",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "db error",
+			tenantID: tenantID,
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, hubID *uuid.UUID) ([]models.InventoryAging, error) {
+				return nil, errors.New("db down")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:     "one hub as csv",
+			tenantID: tenantID,
+			hubID:    hubID.String(),
+			format:   "csv",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, hub *uuid.UUID) ([]models.InventoryAging, error) {
+				if hub == nil || *hub != hubID {
+					return nil, errors.New("expected the hub filter")
+				}
+				return []models.InventoryAging{{HubID: hubID, OnHand: 5, Days0To30: 5}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:     "all hubs",
+			tenantID: tenantID,
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, hub *uuid.UUID) ([]models.InventoryAging, error) {
+				if hub != nil {
+					return nil, errors.New("expected no hub filter")
+				}
+				return []models.InventoryAging{}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockInventoryAgingFetcher{GetInventoryAgingFunc: tt.mockFunc}
+			_, status, err := getInventoryAgingLogic(mock, tt.tenantID, tt.hubID, tt.format)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAgingCSV(t *testing.T) {
+	hubID := uuid.New()
+	skuID := uuid.New()
+
+	body, err := agingCSV([]models.InventoryAging{{
+		HubID:         hubID,
+		HubName:       "Main, North",
+		SkuID:         skuID,
+		SkuCode:       "TSHIRT-M",
+		SkuName:       "T-shirt",
+		OnHand:        100,
+		Days0To30:     40,
+		Days31To60:    30,
+		Days61To90:    20,
+		Days90Plus:    10,
+		OldestAgeDays: 120,
+	}})
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, strings.Join(agingCSVHeader, ","), lines[0])
+	assert.Equal(t, hubID.String()+`,"Main, North",`+skuID.String()+",TSHIRT-M,T-shirt,100,40,30,20,10,120", lines[1])
+}
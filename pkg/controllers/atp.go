@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/constants"
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+)
+
+type BatchATPRequest struct {
+	Items       []models.ATPQuery `json:"items" binding:"required"`
+	HorizonDays *int              `json:"horizon_days"`
+}
+
+type ATPFetcher interface {
+	GetATP(ctx context.Context, tenantID uuid.UUID, queries []models.ATPQuery, until time.Time) ([]models.ATPResult, error)
+}
+
+// atpHorizon turns a horizon in days into the latest expected arrival that
+// still counts as inbound.
+func atpHorizon(days *int) (time.Time, error) {
+	horizon := constants.DefaultATPHorizonDays
+	if days != nil {
+		if *days < 0 || *days > constants.MaxATPHorizonDays {
+			return time.Time{}, errors.New("horizon_days must be between 0 and " + strconv.Itoa(constants.MaxATPHorizonDays))
+		}
+		horizon = *days
+	}
+	return time.Now().AddDate(0, 0, horizon), nil
+}
+
+func atpError(err error) (int, error) {
+	if errors.Is(err, models.ErrATPNotFound) {
+		return int(http.StatusNotFound), err
+	}
+	return int(http.StatusInternalServerError), errors.New("failed to compute available-to-promise")
+}
+
+// GetATP
+
+func getATPLogic(service ATPFetcher, tenantIDStr, skuIDStr, hubIDStr, horizonStr string) (*models.ATPResult, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	skuID, err := uuid.Parse(skuIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid sku_id")
+	}
+
+	query := models.ATPQuery{SkuID: skuID}
+	if hubIDStr != "" {
+		hubID, err := uuid.Parse(hubIDStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid hub_id")
+		}
+		query.HubID = &hubID
+	}
+
+	var days *int
+	if horizonStr != "" {
+		d, err := strconv.Atoi(horizonStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid horizon_days")
+		}
+		days = &d
+	}
+	until, err := atpHorizon(days)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	results, err := service.GetATP(context.Background(), tenantID, []models.ATPQuery{query}, until)
+	if err != nil {
+		status, err := atpError(err)
+		return nil, status, err
+	}
+
+	return &results[0], int(http.StatusOK), nil
+}
+
+// GetATP godoc
+// @Summary Available-to-promise of a SKU at a hub, or per hub and in total across the tenant
+// @Tags ATP
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param sku_id query string true "SKU ID"
+// @Param hub_id query string false "Hub ID; omit for every hub of the tenant"
+// @Param horizon_days query int false "Count inbound stock expected within this many days (default 7)"
+// @Success 200 {object} models.ATPResult
+// @Router /atp [get]
+func GetATP(c *gin.Context) {
+	result, status, err := getATPLogic(models.InventoryModel{}, c.GetHeader("X-Tenant-ID"), c.Query("sku_id"), c.Query("hub_id"), c.Query("horizon_days"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, result)
+}
+
+// GetBatchATP
+
+func getBatchATPLogic(service ATPFetcher, tenantIDStr string, req BatchATPRequest) ([]models.ATPResult, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	if len(req.Items) == 0 {
+		return nil, int(http.StatusBadRequest), errors.New("at least one item is required")
+	}
+	if len(req.Items) > constants.MaxATPBatchSize {
+		return nil, int(http.StatusBadRequest), errors.New("at most " + strconv.Itoa(constants.MaxATPBatchSize) + " items per batch")
+	}
+	for _, item := range req.Items {
+		if item.SkuID == uuid.Nil {
+			return nil, int(http.StatusBadRequest), errors.New("every item needs sku_id")
+		}
+	}
+
+	until, err := atpHorizon(req.HorizonDays)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	results, err := service.GetATP(context.Background(), tenantID, req.Items, until)
+	if err != nil {
+		status, err := atpError(err)
+		return nil, status, err
+	}
+
+	return results, int(http.StatusOK), nil
+}
+
+// GetBatchATP godoc
+// @Summary Available-to-promise for several SKU/hub pairs in one call, in request order
+// @Tags ATP
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body BatchATPRequest true "SKUs with optional hubs"
+// @Success 200 {array} models.ATPResult
+// @Router /atp/batch [post]
+func GetBatchATP(c *gin.Context) {
+	var req BatchATPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	results, status, err := getBatchATPLogic(models.InventoryModel{}, c.GetHeader("X-Tenant-ID"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, results)
+}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockATPFetcher struct {
+	GetATPFunc func(ctx context.Context, tenantID uuid.UUID, queries []models.ATPQuery, until time.Time) ([]models.ATPResult, error)
+}
+
+func (m *mockATPFetcher) GetATP(ctx context.Context, tenantID uuid.UUID, queries []models.ATPQuery, until time.Time) ([]models.ATPResult, error) {
+	return m.GetATPFunc(ctx, tenantID, queries, until)
+}
+
+// GetATP
+
+func TestGetATPLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	skuID := uuid.New()
+	hubID := uuid.New()
+
+	tests := []struct {
+		name           string
+		skuID          string
+		hubID          string
+		horizon        string
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, queries []models.ATPQuery, until time.Time) ([]models.ATPResult, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid sku id",
+			skuID:          "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid hub id",
+			skuID:          skuID.String(),
+			hubID:          "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "horizon out of range",
+			skuID:          skuID.String(),
+			horizon:        "1000",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "unknown sku",
+			skuID: skuID.String(),
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, queries []models.ATPQuery, until time.Time) ([]models.ATPResult, error) {
+				return nil, models.ErrATPNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:    "single hub",
+			skuID:   skuID.String(),
+			hubID:   hubID.String(),
+			horizon: "3",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, queries []models.ATPQuery, until time.Time) ([]models.ATPResult, error) {
+				assert.Len(t, queries, 1)
+				assert.Equal(t, hubID, *queries[0].HubID)
+				assert.WithinDuration(t, time.Now().AddDate(0, 0, 3), until, time.Minute)
+				return []models.ATPResult{{SkuID: skuID, Total: models.ATPLine{ATP: 4}}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:  "tenant aggregate",
+			skuID: skuID.String(),
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, queries []models.ATPQuery, until time.Time) ([]models.ATPResult, error) {
+				assert.Nil(t, queries[0].HubID)
+				return []models.ATPResult{{SkuID: skuID, Total: models.ATPLine{ATP: 4}}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockATPFetcher{GetATPFunc: tt.mockFunc}
+			result, status, err := getATPLogic(mock, tenantID, tt.skuID, tt.hubID, tt.horizon)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 4, result.Total.ATP)
+			}
+		})
+	}
+}
+
+// GetBatchATP
+
+func TestGetBatchATPLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	skuID := uuid.New()
+	negative := -1
+
+	tests := []struct {
+		name           string
+		req            BatchATPRequest
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, queries []models.ATPQuery, until time.Time) ([]models.ATPResult, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "no items",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "missing sku id",
+			req:            BatchATPRequest{Items: []models.ATPQuery{{}}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "negative horizon",
+			req:            BatchATPRequest{Items: []models.ATPQuery{{SkuID: skuID}}, HorizonDays: &negative},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "db error",
+			req:  BatchATPRequest{Items: []models.ATPQuery{{SkuID: skuID}}},
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, queries []models.ATPQuery, until time.Time) ([]models.ATPResult, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name: "success",
+			req:  BatchATPRequest{Items: []models.ATPQuery{{SkuID: skuID}, {SkuID: uuid.New()}}},
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, queries []models.ATPQuery, until time.Time) ([]models.ATPResult, error) {
+				return make([]models.ATPResult, len(queries)), nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockATPFetcher{GetATPFunc: tt.mockFunc}
+			results, status, err := getBatchATPLogic(mock, tenantID, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, results, 2)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type AddBarcodeRequest struct {
+	Barcode string `json:"barcode" binding:"required" example:"4006381333931"`
+	Type    string `json:"type" binding:"required" example:"ean13"` // ean13, upca, gtin14 or internal
+}
+
+// isBarcodeError reports whether err is a barcode rejected on create.
+func isBarcodeError(err error) bool {
+	return errors.Is(err, models.ErrInvalidBarcode) || errors.Is(err, models.ErrBarcodeExists)
+}
+
+// AddSkuBarcode
+
+type SkuBarcodeAdder interface {
+	AddSkuBarcode(ctx context.Context, barcode *models.SkuBarcode) error
+}
+
+func addSkuBarcodeLogic(service SkuBarcodeAdder, idStr string, req AddBarcodeRequest) (*models.SkuBarcode, int, error) {
+	skuID, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid sku id")
+	}
+
+	barcode := &models.SkuBarcode{
+		SkuID:   skuID,
+		Barcode: strings.TrimSpace(req.Barcode),
+		Type:    strings.ToLower(req.Type),
+	}
+	if err := models.ValidateBarcode(barcode.Type, barcode.Barcode); err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	if err := service.AddSkuBarcode(context.Background(), barcode); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("sku not found")
+		}
+		if isBarcodeError(err) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to add barcode")
+	}
+
+	return barcode, int(http.StatusCreated), nil
+}
+
+// AddSkuBarcode godoc
+// @Summary Add a barcode to a SKU; GS1 codes are checked for length and check digit
+// @Tags SKUs
+// @Accept json
+// @Produce json
+// @Param id path string true "SKU ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body AddBarcodeRequest true "Barcode"
+// @Success 201 {object} models.SkuBarcode
+// @Router /skus/{id}/barcodes [post]
+func AddSkuBarcode(c *gin.Context) {
+	var req AddBarcodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request body")})
+		return
+	}
+
+	barcode, status, err := addSkuBarcodeLogic(models.SKUModel{}, c.Param("id"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, barcode)
+}
+
+// LookupBarcode
+
+type BarcodeLookerUp interface {
+	LookupBarcode(ctx context.Context, tenantID uuid.UUID, code string, hubID *uuid.UUID) (*models.BarcodeLookup, error)
+}
+
+func lookupBarcodeLogic(service BarcodeLookerUp, tenantIDStr, code, hubIDStr string) (*models.BarcodeLookup, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, int(http.StatusBadRequest), errors.New("barcode is required")
+	}
+
+	var hubID *uuid.UUID
+	if hubIDStr != "" {
+		id, err := uuid.Parse(hubIDStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid X-Hub-ID header")
+		}
+		hubID = &id
+	}
+
+	lookup, err := service.LookupBarcode(context.Background(), tenantID, code, hubID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("barcode not found")
+		}
+		if errors.Is(err, models.ErrHubNotFound) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to look up barcode")
+	}
+
+	return lookup, int(http.StatusOK), nil
+}
+
+// LookupBarcode godoc
+// @Summary Resolve a scanned barcode to its SKU, with stock at the caller's hub
+// @Tags SKUs
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param X-Hub-ID header string false "Hub of the scanner; adds the SKU's stock there"
+// @Param barcode query string true "Scanned code"
+// @Success 200 {object} models.BarcodeLookup
+// @Router /skus/lookup [get]
+func LookupBarcode(c *gin.Context) {
+	lookup, status, err := lookupBarcodeLogic(models.SKUModel{}, c.GetHeader("X-Tenant-ID"), c.Query("barcode"), c.GetHeader("X-Hub-ID"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, lookup)
+}
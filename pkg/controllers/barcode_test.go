@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockBarcodeService struct {
+	AddSkuBarcodeFunc func(ctx context.Context, barcode *models.SkuBarcode) error
+	LookupBarcodeFunc func(ctx context.Context, tenantID uuid.UUID, code string, hubID *uuid.UUID) (*models.BarcodeLookup, error)
+}
+
+func (m *mockBarcodeService) AddSkuBarcode(ctx context.Context, barcode *models.SkuBarcode) error {
+	return m.AddSkuBarcodeFunc(ctx, barcode)
+}
+
+func (m *mockBarcodeService) LookupBarcode(ctx context.Context, tenantID uuid.UUID, code string, hubID *uuid.UUID) (*models.BarcodeLookup, error) {
+	return m.LookupBarcodeFunc(ctx, tenantID, code, hubID)
+}
+
+func TestAddSkuBarcodeLogic(t *testing.T) {
+	skuID := uuid.New().String()
+	stored := func(ctx context.Context, barcode *models.SkuBarcode) error {
+		barcode.ID = uuid.New()
+		return nil
+	}
+
+	tests := []struct {
+		name           string
+		skuID          string
+		req            AddBarcodeRequest
+		mockFunc       func(ctx context.Context, barcode *models.SkuBarcode) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid sku id",
+			skuID:          "bad",
+			req:            AddBarcodeRequest{Barcode: "4006381333931", Type: "ean13"},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "unknown type",
+			skuID:          skuID,
+			req:            AddBarcodeRequest{Barcode: "4006381333931", Type: "qr"},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "ean13 with wrong check digit",
+			skuID:          skuID,
+			req:            AddBarcodeRequest{Barcode: "4006381333932", Type: "ean13"},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "upca of wrong length",
+			skuID:          skuID,
+			req:            AddBarcodeRequest{Barcode: "03600029145", Type: "upca"},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "gtin14 with letters",
+			skuID:          skuID,
+			req:            AddBarcodeRequest{Barcode: "1001234567890A", Type: "gtin14"},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "internal code with spaces",
+			skuID:          skuID,
+			req:            AddBarcodeRequest{Barcode: "BIN 42", Type: "internal"},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "already assigned",
+			skuID: skuID,
+			req:   AddBarcodeRequest{Barcode: "4006381333931", Type: "ean13"},
+			mockFunc: func(ctx context.Context, barcode *models.SkuBarcode) error {
+				return fmt.Errorf("%w: %s", models.ErrBarcodeExists, barcode.Barcode)
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "sku not found",
+			skuID: skuID,
+			req:   AddBarcodeRequest{Barcode: "4006381333931", Type: "ean13"},
+			mockFunc: func(ctx context.Context, barcode *models.SkuBarcode) error {
+				return gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:           "valid ean13",
+			skuID:          skuID,
+			req:            AddBarcodeRequest{Barcode: "4006381333931", Type: "EAN13"},
+			mockFunc:       stored,
+			expectedStatus: int(http.StatusCreated),
+		},
+		{
+			name:           "valid upca",
+			skuID:          skuID,
+			req:            AddBarcodeRequest{Barcode: "036000291452", Type: "upca"},
+			mockFunc:       stored,
+			expectedStatus: int(http.StatusCreated),
+		},
+		{
+			name:           "valid gtin14",
+			skuID:          skuID,
+			req:            AddBarcodeRequest{Barcode: "10012345678902", Type: "gtin14"},
+			mockFunc:       stored,
+			expectedStatus: int(http.StatusCreated),
+		},
+		{
+			name:           "internal code",
+			skuID:          skuID,
+			req:            AddBarcodeRequest{Barcode: "WH-000123", Type: "internal"},
+			mockFunc:       stored,
+			expectedStatus: int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockBarcodeService{AddSkuBarcodeFunc: tt.mockFunc}
+			barcode, status, err := addSkuBarcodeLogic(mock, tt.skuID, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEqual(t, uuid.Nil, barcode.ID)
+				assert.Equal(t, tt.req.Barcode, barcode.Barcode)
+			}
+		})
+	}
+}
+
+func TestLookupBarcodeLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	hubID := uuid.New()
+	skuID := uuid.New()
+
+	tests := []struct {
+		name           string
+		code           string
+		hubID          string
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, code string, hubID *uuid.UUID) (*models.BarcodeLookup, error)
+		expectedStatus int
+		expectErr      bool
+		expectStock    bool
+	}{
+		{
+			name:           "missing barcode",
+			code:           " ",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid hub header",
+			code:           "4006381333931",
+			hubID:          "bad",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "unknown barcode",
+			code: "4006381333931",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, code string, hubID *uuid.UUID) (*models.BarcodeLookup, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:  "hub of another tenant",
+			code:  "4006381333931",
+			hubID: hubID.String(),
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, code string, hubID *uuid.UUID) (*models.BarcodeLookup, error) {
+				return nil, models.ErrHubNotFound
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "db error",
+			code: "4006381333931",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, code string, hubID *uuid.UUID) (*models.BarcodeLookup, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name: "without hub",
+			code: "4006381333931",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, code string, hubID *uuid.UUID) (*models.BarcodeLookup, error) {
+				assert.Nil(t, hubID)
+				return &models.BarcodeLookup{Sku: models.Sku{ID: skuID}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:  "with stock at the caller's hub",
+			code:  " 4006381333931 ",
+			hubID: hubID.String(),
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, code string, hub *uuid.UUID) (*models.BarcodeLookup, error) {
+				assert.Equal(t, "4006381333931", code)
+				assert.Equal(t, hubID, *hub)
+				return &models.BarcodeLookup{
+					Sku:   models.Sku{ID: skuID},
+					Stock: &models.InventoryView{SkuID: skuID, OnHand: 5, Quantity: 5},
+				}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+			expectStock:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockBarcodeService{LookupBarcodeFunc: tt.mockFunc}
+			lookup, status, err := lookupBarcodeLogic(mock, tenantID, tt.code, tt.hubID)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, skuID, lookup.Sku.ID)
+				assert.Equal(t, tt.expectStock, lookup.Stock != nil)
+			}
+		})
+	}
+}
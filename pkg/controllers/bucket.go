@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type MoveInventoryStatusRequest struct {
+	FromStatus string `json:"from_status" binding:"required" example:"sellable"`
+	ToStatus   string `json:"to_status" binding:"required" example:"damaged"`
+	Quantity   int    `json:"quantity" binding:"required"`
+}
+
+// MoveInventoryStatus
+
+type InventoryStatusMover interface {
+	MoveInventoryStatus(ctx context.Context, move models.StatusMove) (*models.InventoryBuckets, error)
+}
+
+func moveInventoryStatusLogic(service InventoryStatusMover, idStr string, req MoveInventoryStatusRequest, meta models.MovementMeta) (*models.InventoryBuckets, int, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid inventory id")
+	}
+
+	if req.Quantity <= 0 {
+		return nil, int(http.StatusBadRequest), errors.New("quantity must be positive")
+	}
+
+	if !models.IsInventoryStatus(req.FromStatus) || !models.IsInventoryStatus(req.ToStatus) {
+		return nil, int(http.StatusBadRequest), errors.New("status must be sellable, damaged, quarantined, in_transit or disposal")
+	}
+	if req.FromStatus == req.ToStatus {
+		return nil, int(http.StatusBadRequest), errors.New("from_status and to_status must differ")
+	}
+
+	move := models.StatusMove{
+		InventoryID: id,
+		FromStatus:  req.FromStatus,
+		ToStatus:    req.ToStatus,
+		Quantity:    req.Quantity,
+	}
+
+	buckets, err := service.MoveInventoryStatus(models.WithMovementMeta(context.Background(), meta), move)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("inventory not found")
+		}
+		if errors.Is(err, models.ErrInsufficientStock) || errors.Is(err, models.ErrSerializedSku) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to move inventory status")
+	}
+
+	return buckets, int(http.StatusOK), nil
+}
+
+// MoveInventoryStatus godoc
+// @Summary Move quantity between the sellable, damaged, quarantined, in_transit and disposal buckets of an inventory row
+// @Tags Inventories
+// @Accept json
+// @Produce json
+// @Param id path string true "Inventory ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body MoveInventoryStatusRequest true "Status move"
+// @Success 200 {object} models.InventoryBuckets
+// @Router /inventories/{id}/status-moves [post]
+func MoveInventoryStatus(c *gin.Context) {
+	var req MoveInventoryStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	buckets, status, err := moveInventoryStatusLogic(models.InventoryModel{}, c.Param("id"), req, movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, buckets)
+}
+
+// ViewInventoryDetail
+
+type InventoryDetailViewer interface {
+	GetInventoryDetail(ctx context.Context, tenantID, hubID uuid.UUID) ([]models.InventoryDetailView, error)
+}
+
+func viewInventoryDetailLogic(service InventoryDetailViewer, tenantIDStr, hubIDStr string) ([]models.InventoryDetailView, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id")
+	}
+
+	hubID, err := uuid.Parse(hubIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid hub_id")
+	}
+
+	result, err := service.GetInventoryDetail(context.Background(), tenantID, hubID)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), err
+	}
+
+	return result, int(http.StatusOK), nil
+}
+
+// ViewInventoryDetail godoc
+// @Summary View inventory of a hub broken down by status bucket
+// @Tags Inventories
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param hub_id query string true "Hub ID"
+// @Success 200 {array} models.InventoryDetailView
+// @Router /inventories/view/detailed [get]
+func ViewInventoryDetail(c *gin.Context) {
+	tenantIDStr := c.GetHeader("X-Tenant-ID")
+	hubIDStr := c.Query("hub_id")
+
+	if tenantIDStr == "" || hubIDStr == "" {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Missing tenant_id header or hub_id query param")})
+		return
+	}
+
+	view, status, err := viewInventoryDetailLogic(models.InventoryModel{}, tenantIDStr, hubIDStr)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, view)
+}
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// MoveInventoryStatus
+
+type mockInventoryStatusMover struct {
+	MoveInventoryStatusFunc func(ctx context.Context, move models.StatusMove) (*models.InventoryBuckets, error)
+}
+
+func (m *mockInventoryStatusMover) MoveInventoryStatus(ctx context.Context, move models.StatusMove) (*models.InventoryBuckets, error) {
+	return m.MoveInventoryStatusFunc(ctx, move)
+}
+
+func TestMoveInventoryStatusLogic(t *testing.T) {
+	id := uuid.New()
+	valid := MoveInventoryStatusRequest{FromStatus: models.StatusSellable, ToStatus: models.StatusDamaged, Quantity: 3}
+
+	tests := []struct {
+		name           string
+		id             string
+		req            MoveInventoryStatusRequest
+		mockFunc       func(ctx context.Context, move models.StatusMove) (*models.InventoryBuckets, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid id",
+			id:             "bad-uuid",
+			req:            valid,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "non-positive quantity",
+			id:             id.String(),
+			req:            MoveInventoryStatusRequest{FromStatus: models.StatusSellable, ToStatus: models.StatusDamaged},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "unknown status",
+			id:             id.String(),
+			req:            MoveInventoryStatusRequest{FromStatus: models.StatusSellable, ToStatus: "lost", Quantity: 1},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "same status",
+			id:             id.String(),
+			req:            MoveInventoryStatusRequest{FromStatus: models.StatusDamaged, ToStatus: models.StatusDamaged, Quantity: 1},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "inventory not found",
+			id:   id.String(),
+			req:  valid,
+			mockFunc: func(ctx context.Context, move models.StatusMove) (*models.InventoryBuckets, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name: "source bucket short",
+			id:   id.String(),
+			req:  valid,
+			mockFunc: func(ctx context.Context, move models.StatusMove) (*models.InventoryBuckets, error) {
+				return nil, models.ErrInsufficientStock
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "db error",
+			id:   id.String(),
+			req:  valid,
+			mockFunc: func(ctx context.Context, move models.StatusMove) (*models.InventoryBuckets, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name: "success",
+			id:   id.String(),
+			req:  valid,
+			mockFunc: func(ctx context.Context, move models.StatusMove) (*models.InventoryBuckets, error) {
+				assert.Equal(t, id, move.InventoryID)
+				assert.Equal(t, 3, move.Quantity)
+				return &models.InventoryBuckets{Sellable: 7, Damaged: 3}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockInventoryStatusMover{MoveInventoryStatusFunc: tt.mockFunc}
+			buckets, status, err := moveInventoryStatusLogic(mock, tt.id, tt.req, models.MovementMeta{})
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 3, buckets.Damaged)
+			}
+		})
+	}
+}
+
+// ViewInventoryDetail
+
+type mockInventoryDetailViewer struct {
+	GetInventoryDetailFunc func(ctx context.Context, tenantID, hubID uuid.UUID) ([]models.InventoryDetailView, error)
+}
+
+func (m *mockInventoryDetailViewer) GetInventoryDetail(ctx context.Context, tenantID, hubID uuid.UUID) ([]models.InventoryDetailView, error) {
+	return m.GetInventoryDetailFunc(ctx, tenantID, hubID)
+}
+
+func TestViewInventoryDetailLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	hubID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		hubID          string
+		mockFunc       func(ctx context.Context, tenantID, hubID uuid.UUID) ([]models.InventoryDetailView, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad-uuid",
+			hubID:          hubID,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid hub id",
+			tenantID:       tenantID,
+			hubID:          "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "db error",
+			tenantID: tenantID,
+			hubID:    hubID,
+			mockFunc: func(ctx context.Context, tenantID, hubID uuid.UUID) ([]models.InventoryDetailView, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:     "success",
+			tenantID: tenantID,
+			hubID:    hubID,
+			mockFunc: func(ctx context.Context, tenantID, hubID uuid.UUID) ([]models.InventoryDetailView, error) {
+				return []models.InventoryDetailView{{SkuCode: "SKU-1", InventoryBuckets: models.InventoryBuckets{Sellable: 5, Damaged: 2}, Total: 7}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockInventoryDetailViewer{GetInventoryDetailFunc: tt.mockFunc}
+			_, status, err := viewInventoryDetailLogic(mock, tt.tenantID, tt.hubID)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+)
+
+// GetDemandForecasts
+
+type DemandForecastFetcher interface {
+	GetDemandForecasts(ctx context.Context, tenantID uuid.UUID, filter models.DemandForecastFilter) ([]models.DemandForecast, error)
+}
+
+func getDemandForecastsLogic(service DemandForecastFetcher, tenantIDStr, hubIDStr, skuIDStr, reorderOnlyStr string) ([]models.DemandForecast, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	var filter models.DemandForecastFilter
+	if hubIDStr != "" {
+		hubID, err := uuid.Parse(hubIDStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid hub_id")
+		}
+		filter.HubID = &hubID
+	}
+	if skuIDStr != "" {
+		skuID, err := uuid.Parse(skuIDStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid sku_id")
+		}
+		filter.SkuID = &skuID
+	}
+	if reorderOnlyStr != "" {
+		filter.ReorderOnly, err = strconv.ParseBool(reorderOnlyStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid reorder_only, expected true or false")
+		}
+	}
+
+	forecasts, err := service.GetDemandForecasts(context.Background(), tenantID, filter)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch demand forecasts")
+	}
+
+	return forecasts, int(http.StatusOK), nil
+}
+
+// GetDemandForecasts godoc
+// @Summary List the nightly daily demand forecasts and suggested reorder quantities per hub and SKU
+// @Tags Forecasts
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param hub_id query string false "Only this hub"
+// @Param sku_id query string false "Only this SKU"
+// @Param reorder_only query bool false "Only rows with a suggested reorder"
+// @Success 200 {array} models.DemandForecast
+// @Router /forecasts [get]
+func GetDemandForecasts(c *gin.Context) {
+	forecasts, status, err := getDemandForecastsLogic(models.DemandForecastModel{}, c.GetHeader("X-Tenant-ID"), c.Query("hub_id"), c.Query("sku_id"), c.Query("reorder_only"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, forecasts)
+}
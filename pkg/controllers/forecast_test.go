@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDemandForecastFetcher struct {
+	GetDemandForecastsFunc func(ctx context.Context, tenantID uuid.UUID, filter models.DemandForecastFilter) ([]models.DemandForecast, error)
+}
+
+func (m *mockDemandForecastFetcher) GetDemandForecasts(ctx context.Context, tenantID uuid.UUID, filter models.DemandForecastFilter) ([]models.DemandForecast, error) {
+	return m.GetDemandForecastsFunc(ctx, tenantID, filter)
+}
+
+func TestGetDemandForecastsLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	hubID := uuid.New()
+	skuID := uuid.New()
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		hubID          string
+		skuID          string
+		reorderOnly    string
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, filter models.DemandForecastFilter) ([]models.DemandForecast, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid hub id",
+			tenantID:       tenantID,
+			hubID:          "bad",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid sku id",
+			tenantID:       tenantID,
+			skuID:          "bad",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid reorder_only",
+			tenantID:       tenantID,
+			reorderOnly:    "maybe",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "db error",
+			tenantID: tenantID,
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, filter models.DemandForecastFilter) ([]models.DemandForecast, error) {
+				return nil, errors.New("db down")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:        "filtered reorders",
+			tenantID:    tenantID,
+			hubID:       hubID.String(),
+			skuID:       skuID.String(),
+			reorderOnly: "true",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, filter models.DemandForecastFilter) ([]models.DemandForecast, error) {
+				if filter.HubID == nil || *filter.HubID != hubID || filter.SkuID == nil || *filter.SkuID != skuID || !filter.ReorderOnly {
+					return nil, errors.New("unexpected filter")
+				}
+				return []models.DemandForecast{{HubID: hubID, SkuID: skuID, SuggestedQuantity: 42}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:     "all forecasts",
+			tenantID: tenantID,
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, filter models.DemandForecastFilter) ([]models.DemandForecast, error) {
+				if filter.HubID != nil || filter.SkuID != nil || filter.ReorderOnly {
+					return nil, errors.New("expected no filter")
+				}
+				return []models.DemandForecast{}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockDemandForecastFetcher{GetDemandForecastsFunc: tt.mockFunc}
+			_, status, err := getDemandForecastsLogic(mock, tt.tenantID, tt.hubID, tt.skuID, tt.reorderOnly)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/aditya-goyal-omniful/ims/pkg/models"
 	"github.com/gin-gonic/gin"
@@ -13,9 +14,12 @@ import (
 )
 
 type CheckInventoryRequest struct {
-	SKUID    uuid.UUID `json:"sku_id" binding:"required"`
-	HubID    uuid.UUID `json:"hub_id" binding:"required"`
-	Quantity int       `json:"quantity" binding:"required"`
+	SKUID          uuid.UUID `json:"sku_id" binding:"required"`
+	HubID          uuid.UUID `json:"hub_id" binding:"required"`
+	Quantity       int       `json:"quantity" binding:"required"`
+	Unit           string    `json:"unit"` // defaults to the SKU's base unit
+	OrderReference string    `json:"order_reference"`
+	AllowPartial   bool      `json:"allow_partial"`
 }
 
 // GetInventories
@@ -94,7 +98,7 @@ type InventoryCreator interface {
 	CreateInventory(ctx context.Context, inv *models.Inventory) error
 }
 
-func createInventoryLogic(service InventoryCreator, tenantIDStr string, inventory *models.Inventory) (int, error) {
+func createInventoryLogic(service InventoryCreator, tenantIDStr string, inventory *models.Inventory, meta models.MovementMeta) (int, error) {
 	// Validate tenant ID
 	if tenantIDStr != "" {
 		tenantID, err := uuid.Parse(tenantIDStr)
@@ -105,10 +109,15 @@ func createInventoryLogic(service InventoryCreator, tenantIDStr string, inventor
 	}
 
 	// Save to DB
-	if err := service.CreateInventory(context.Background(), inventory); err != nil {
+	ctx := models.WithMovementMeta(context.Background(), meta)
+	if err := service.CreateInventory(ctx, inventory); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return int(http.StatusBadRequest), errors.New("tenant not found")
 		}
+		if errors.Is(err, models.ErrSerializedSku) || errors.Is(err, models.ErrBundleSku) || isUnitError(err) ||
+			errors.Is(err, models.ErrInvalidUnitCost) || isQuantityAdjustmentError(err) {
+			return int(http.StatusBadRequest), err
+		}
 		return int(http.StatusInternalServerError), errors.New("failed to create inventory")
 	}
 
@@ -116,11 +125,12 @@ func createInventoryLogic(service InventoryCreator, tenantIDStr string, inventor
 }
 
 // CreateInventory godoc
-// @Summary Create new inventory
+// @Summary Create new inventory; a non-zero quantity needs a reason_code and becomes an adjustment, pending above the tenant's thresholds
 // @Tags Inventories
 // @Accept json
 // @Produce json
 // @Param X-Tenant-ID header string true "Tenant ID"
+// @Param X-User-ID header string false "Requesting user, required for a non-zero quantity"
 // @Param inventory body models.Inventory true "Inventory to create"
 // @Success 201 {object} models.Inventory
 // @Router /inventories [post]
@@ -132,7 +142,7 @@ func CreateInventory(c *gin.Context) {
 		return
 	}
 
-	status, err := createInventoryLogic(models.InventoryModel{}, c.GetHeader("X-Tenant-ID"), &inventory)
+	status, err := createInventoryLogic(models.InventoryModel{}, c.GetHeader("X-Tenant-ID"), &inventory, movementMetaFromRequest(c))
 	if err != nil {
 		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
 		return
@@ -147,17 +157,20 @@ type InventoryDeleter interface {
 	DeleteInventory(ctx context.Context, id uuid.UUID) (*models.Inventory, error)
 }
 
-func deleteInventoryLogic(service InventoryDeleter, idStr string) (*models.Inventory, int, error) {
+func deleteInventoryLogic(service InventoryDeleter, idStr string, meta models.MovementMeta) (*models.Inventory, int, error) {
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		return nil, int(http.StatusBadRequest), errors.New("invalid inventory id")
 	}
 
-	inv, err := service.DeleteInventory(context.Background(), id)
+	inv, err := service.DeleteInventory(models.WithMovementMeta(context.Background(), meta), id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, int(http.StatusNotFound), errors.New("inventory not found")
 		}
+		if errors.Is(err, models.ErrInventoryNotEmpty) {
+			return nil, int(http.StatusBadRequest), err
+		}
 		return nil, int(http.StatusInternalServerError), err
 	}
 
@@ -165,7 +178,7 @@ func deleteInventoryLogic(service InventoryDeleter, idStr string) (*models.Inven
 }
 
 // DeleteInventory godoc
-// @Summary Delete inventory by ID
+// @Summary Delete an empty inventory row by ID; adjust stocked rows to zero first
 // @Tags Inventories
 // @Produce json
 // @Param id path string true "Inventory ID"
@@ -175,7 +188,7 @@ func deleteInventoryLogic(service InventoryDeleter, idStr string) (*models.Inven
 func DeleteInventory(c *gin.Context) {
 	idStr := c.Param("id")
 
-	inv, status, err := deleteInventoryLogic(models.InventoryModel{}, idStr)
+	inv, status, err := deleteInventoryLogic(models.InventoryModel{}, idStr, movementMetaFromRequest(c))
 	if err != nil {
 		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
 		return
@@ -186,8 +199,15 @@ func DeleteInventory(c *gin.Context) {
 
 // UpdateInventory
 
+// UpdateInventoryRequest is an inventory with an optional quantity, so that
+// leaving it out keeps the quantity while 0 empties the row.
+type UpdateInventoryRequest struct {
+	models.Inventory
+	Quantity *int `json:"quantity"`
+}
+
 type InventoryUpdater interface {
-	UpdateInventory(ctx context.Context, id uuid.UUID, updated *models.Inventory) error
+	UpdateInventory(ctx context.Context, id uuid.UUID, updated *models.Inventory, quantity *int) error
 	GetInventory(ctx context.Context, id uuid.UUID) (*models.Inventory, error)
 }
 
@@ -199,8 +219,11 @@ func updateInventoryLogic(
 	service InventoryUpdater,
 	tenantService TenantValidator,
 	idStr string,
-	inventory *models.Inventory,
+	req *UpdateInventoryRequest,
+	meta models.MovementMeta,
 ) (*models.Inventory, int, error) {
+	inventory := &req.Inventory
+
 	// Parse UUID
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -219,7 +242,11 @@ func updateInventoryLogic(
 	}
 
 	// Update inventory
-	if err := service.UpdateInventory(context.Background(), id, inventory); err != nil {
+	if err := service.UpdateInventory(models.WithMovementMeta(context.Background(), meta), id, inventory, req.Quantity); err != nil {
+		if errors.Is(err, models.ErrSerializedSku) || isUnitError(err) || errors.Is(err, models.ErrInvalidUnitCost) ||
+			isQuantityAdjustmentError(err) || errors.Is(err, models.ErrInventoryKeyChange) {
+			return nil, int(http.StatusBadRequest), err
+		}
 		return nil, int(http.StatusInternalServerError), err
 	}
 
@@ -228,25 +255,27 @@ func updateInventoryLogic(
 	if err != nil {
 		return nil, int(http.StatusInternalServerError), err
 	}
+	updated.Adjustment = inventory.Adjustment
 
 	return updated, int(http.StatusOK), nil
 }
 
 // UpdateInventory godoc
-// @Summary Update inventory by ID
+// @Summary Update inventory by ID; a quantity change needs a reason_code and becomes an adjustment, pending above the tenant's thresholds
 // @Tags Inventories
 // @Accept json
 // @Produce json
 // @Param id path string true "Inventory ID"
 // @Param X-Tenant-ID header string true "Tenant ID"
-// @Param inventory body models.Inventory true "Updated inventory"
+// @Param X-User-ID header string false "Requesting user, required to change quantity"
+// @Param inventory body UpdateInventoryRequest true "Updated inventory"
 // @Success 200 {object} models.Inventory
 // @Router /inventories/{id} [put]
 func UpdateInventory(c *gin.Context) {
 	idStr := c.Param("id")
 
-	var inventory models.Inventory
-	if err := c.Bind(&inventory); err != nil {
+	var req UpdateInventoryRequest
+	if err := c.Bind(&req); err != nil {
 		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request body")})
 		return
 	}
@@ -255,7 +284,8 @@ func UpdateInventory(c *gin.Context) {
 		models.InventoryModel{},
 		models.TenantModel{},
 		idStr,
-		&inventory,
+		&req,
+		movementMetaFromRequest(c),
 	)
 
 	if err != nil {
@@ -272,7 +302,7 @@ type InventoryUpserter interface {
 	UpsertInventory(ctx context.Context, inv *models.Inventory) error
 }
 
-func upsertInventoryLogic(service InventoryUpserter, tenantIDStr string, inv *models.Inventory) (int, error) {
+func upsertInventoryLogic(service InventoryUpserter, tenantIDStr string, inv *models.Inventory, meta models.MovementMeta) (int, error) {
 	// Parse tenant ID
 	tenantID, err := uuid.Parse(tenantIDStr)
 	if err != nil {
@@ -281,10 +311,14 @@ func upsertInventoryLogic(service InventoryUpserter, tenantIDStr string, inv *mo
 	inv.TenantID = tenantID
 
 	// Call DB upsert
-	if err := service.UpsertInventory(context.Background(), inv); err != nil {
+	if err := service.UpsertInventory(models.WithMovementMeta(context.Background(), meta), inv); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return int(http.StatusBadRequest), errors.New("tenant not found")
 		}
+		if errors.Is(err, models.ErrSerializedSku) || errors.Is(err, models.ErrBundleSku) || isUnitError(err) ||
+			errors.Is(err, models.ErrInvalidUnitCost) || isQuantityAdjustmentError(err) {
+			return int(http.StatusBadRequest), err
+		}
 		return int(http.StatusInternalServerError), errors.New("failed to upsert inventory")
 	}
 
@@ -292,11 +326,12 @@ func upsertInventoryLogic(service InventoryUpserter, tenantIDStr string, inv *mo
 }
 
 // UpsertInventory godoc
-// @Summary Upsert (create or update) inventory
+// @Summary Upsert (create or update) inventory; a quantity change needs a reason_code and becomes an adjustment, pending above the tenant's thresholds
 // @Tags Inventories
 // @Accept json
 // @Produce json
 // @Param X-Tenant-ID header string true "Tenant ID"
+// @Param X-User-ID header string false "Requesting user, required to change quantity"
 // @Param inventory body models.Inventory true "Inventory object"
 // @Success 200 {object} map[string]string
 // @Router /inventories/upsert [post]
@@ -308,7 +343,7 @@ func UpsertInventory(c *gin.Context) {
 		return
 	}
 
-	status, err := upsertInventoryLogic(models.InventoryModel{}, c.GetHeader("X-Tenant-ID"), &inventory)
+	status, err := upsertInventoryLogic(models.InventoryModel{}, c.GetHeader("X-Tenant-ID"), &inventory, movementMetaFromRequest(c))
 	if err != nil {
 		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
 		return
@@ -323,10 +358,10 @@ func UpsertInventory(c *gin.Context) {
 // ViewInventoryWithDefaults
 
 type InventoryViewer interface {
-	GetInventoryWithDefaults(ctx context.Context, tenantID, hubID uuid.UUID) ([]models.InventoryView, error)
+	GetInventoryWithDefaults(ctx context.Context, tenantID, hubID uuid.UUID, asOf *time.Time) ([]models.InventoryView, error)
 }
 
-func viewInventoryWithDefaultsLogic(service InventoryViewer, tenantIDStr, hubIDStr string) ([]models.InventoryView, int, error) {
+func viewInventoryWithDefaultsLogic(service InventoryViewer, tenantIDStr, hubIDStr, asOfStr string) ([]models.InventoryView, int, error) {
 	// Validate tenant UUID
 	tenantID, err := uuid.Parse(tenantIDStr)
 	if err != nil {
@@ -339,8 +374,17 @@ func viewInventoryWithDefaultsLogic(service InventoryViewer, tenantIDStr, hubIDS
 		return nil, int(http.StatusBadRequest), errors.New("invalid hub_id")
 	}
 
+	// Past dates are rebuilt from snapshots; today or later is the live view
+	asOf, err := parseAsOf(asOfStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+	if asOf != nil && asOf.After(time.Now()) {
+		asOf = nil
+	}
+
 	// Fetch inventory view
-	result, err := service.GetInventoryWithDefaults(context.Background(), tenantID, hubID)
+	result, err := service.GetInventoryWithDefaults(context.Background(), tenantID, hubID, asOf)
 	if err != nil {
 		return nil, int(http.StatusInternalServerError), err
 	}
@@ -354,6 +398,7 @@ func viewInventoryWithDefaultsLogic(service InventoryViewer, tenantIDStr, hubIDS
 // @Produce json
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param hub_id query string true "Hub ID"
+// @Param as_of query string false "Show the hub as it stood at the end of this day, YYYY-MM-DD"
 // @Success 200 {array} models.InventoryView
 // @Router /inventories/view [get]
 func ViewInventoryWithDefaults(c *gin.Context) {
@@ -365,7 +410,7 @@ func ViewInventoryWithDefaults(c *gin.Context) {
 		return
 	}
 
-	view, status, err := viewInventoryWithDefaultsLogic(models.InventoryModel{}, tenantIDStr, hubIDStr)
+	view, status, err := viewInventoryWithDefaultsLogic(models.InventoryModel{}, tenantIDStr, hubIDStr, c.Query("as_of"))
 	if err != nil {
 		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
 		return
@@ -376,41 +421,57 @@ func ViewInventoryWithDefaults(c *gin.Context) {
 
 // CheckAndUpdateInventory
 
+type CheckInventoryResponse struct {
+	Available bool `json:"available"` // the full requested quantity was allocated
+	models.StockAllocation
+}
+
 type InventoryChecker interface {
-	GetInventoryBySkuHub(ctx context.Context, skuID, hubID uuid.UUID) (*models.Inventory, error)
-	UpdateInventoryQuantity(ctx context.Context, invID uuid.UUID, newQty int) error
+	AllocateInventory(ctx context.Context, skuID, hubID uuid.UUID, quantity int, allowPartial bool) (*models.StockAllocation, error)
+	ToBaseQuantity(ctx context.Context, skuID uuid.UUID, unit string, quantity int) (int, error)
 }
 
-func checkAndUpdateInventoryLogic(service InventoryChecker, req CheckInventoryRequest) (bool, int, error) {
-	inv, err := service.GetInventoryBySkuHub(context.Background(), req.SKUID, req.HubID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return false, int(http.StatusOK), nil // not available, but valid
-		}
-		return false, int(http.StatusInternalServerError), errors.New("failed to fetch inventory")
+func checkAndUpdateInventoryLogic(service InventoryChecker, req CheckInventoryRequest, meta models.MovementMeta) (*CheckInventoryResponse, int, error) {
+	if req.Quantity <= 0 {
+		return nil, int(http.StatusBadRequest), errors.New("quantity must be positive")
 	}
 
-	if inv.Quantity < req.Quantity {
-		return false, int(http.StatusOK), nil
+	if req.OrderReference != "" {
+		meta.ReferenceID = req.OrderReference
 	}
+	ctx := models.WithMovementMeta(context.Background(), meta)
 
-	newQty := inv.Quantity - req.Quantity
-	if err := service.UpdateInventoryQuantity(context.Background(), inv.ID, newQty); err != nil {
-		return false, int(http.StatusInternalServerError), errors.New("failed to update inventory")
+	quantity, err := service.ToBaseQuantity(ctx, req.SKUID, req.Unit, req.Quantity)
+	if err != nil {
+		if isUnitError(err) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to update inventory")
 	}
 
-	return true, int(http.StatusOK), nil
-}
+	// Check and decrement happen together in the model so concurrent orders cannot oversell
+	allocation, err := service.AllocateInventory(ctx, req.SKUID, req.HubID, quantity, req.AllowPartial)
+	if err != nil {
+		if errors.Is(err, models.ErrSerializedSku) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to update inventory")
+	}
 
+	return &CheckInventoryResponse{
+		Available:       allocation.Allocated == allocation.Requested,
+		StockAllocation: *allocation,
+	}, int(http.StatusOK), nil
+}
 
 // CheckAndUpdateInventory godoc
-// @Summary Check and update inventory if sufficient
+// @Summary Check and update inventory if sufficient, or allocate what is available when allow_partial is set
 // @Tags Inventories
 // @Accept json
 // @Produce json
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param payload body CheckInventoryRequest true "Inventory check payload"
-// @Success 200 {object} map[string]bool
+// @Success 200 {object} CheckInventoryResponse
 // @Router /inventory/check-and-update [post]
 func CheckAndUpdateInventory(c *gin.Context) {
 	var req CheckInventoryRequest
@@ -419,11 +480,84 @@ func CheckAndUpdateInventory(c *gin.Context) {
 		return
 	}
 
-	available, status, err := checkAndUpdateInventoryLogic(models.InventoryModel{}, req)
+	result, status, err := checkAndUpdateInventoryLogic(models.InventoryModel{}, req, movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, result)
+}
+
+// BatchCheckAndUpdateInventory
+
+type BatchCheckInventoryRequest struct {
+	OrderReference string             `json:"order_reference"`
+	Lines          []models.StockLine `json:"lines" binding:"required"`
+}
+
+type BatchCheckInventoryResponse struct {
+	Available bool                   `json:"available"`
+	Shortages []models.StockShortage `json:"shortages"`
+}
+
+type InventoryBatchChecker interface {
+	DecrementInventoryLines(ctx context.Context, lines []models.StockLine) ([]models.StockShortage, error)
+}
+
+func batchCheckAndUpdateInventoryLogic(service InventoryBatchChecker, req BatchCheckInventoryRequest, meta models.MovementMeta) (*BatchCheckInventoryResponse, int, error) {
+	if len(req.Lines) == 0 {
+		return nil, int(http.StatusBadRequest), errors.New("at least one line is required")
+	}
+
+	for _, line := range req.Lines {
+		if line.SkuID == uuid.Nil || line.HubID == uuid.Nil {
+			return nil, int(http.StatusBadRequest), errors.New("every line needs sku_id and hub_id")
+		}
+		if line.Quantity <= 0 {
+			return nil, int(http.StatusBadRequest), errors.New("quantity must be positive")
+		}
+	}
+
+	if req.OrderReference != "" {
+		meta.ReferenceID = req.OrderReference
+	}
+	ctx := models.WithMovementMeta(context.Background(), meta)
+
+	shortages, err := service.DecrementInventoryLines(ctx, req.Lines)
+	if err != nil {
+		if errors.Is(err, models.ErrSerializedSku) || isUnitError(err) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to update inventory")
+	}
+
+	return &BatchCheckInventoryResponse{
+		Available: len(shortages) == 0,
+		Shortages: shortages,
+	}, int(http.StatusOK), nil
+}
+
+// BatchCheckAndUpdateInventory godoc
+// @Summary Check and decrement all order lines in one transaction, or none of them
+// @Tags Inventories
+// @Accept json
+// @Produce json
+// @Param payload body BatchCheckInventoryRequest true "Order lines"
+// @Success 200 {object} BatchCheckInventoryResponse
+// @Router /inventory/check-and-update/batch [post]
+func BatchCheckAndUpdateInventory(c *gin.Context) {
+	var req BatchCheckInventoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	result, status, err := batchCheckAndUpdateInventoryLogic(models.InventoryModel{}, req, movementMetaFromRequest(c))
 	if err != nil {
 		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
 		return
 	}
 
-	c.JSON(status, gin.H{i18n.Translate(c, "available"): available})
+	c.JSON(status, result)
 }
@@ -3,7 +3,9 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aditya-goyal-omniful/ims/pkg/models"
 	"github.com/google/uuid"
@@ -175,6 +177,26 @@ func TestCreateInventoryLogic(t *testing.T) {
 			expectedStatus: int(http.StatusInternalServerError),
 			expectErr:      true,
 		},
+		{
+			name:     "non-integral unit",
+			tenantID: validTenantID,
+			inv:      &models.Inventory{Quantity: 5, Unit: "each"},
+			mockFunc: func(ctx context.Context, inv *models.Inventory) error {
+				return fmt.Errorf("%w: 5 each", models.ErrNonIntegralConversion)
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "starting quantity without reason code",
+			tenantID: validTenantID,
+			inv:      &models.Inventory{Quantity: 5},
+			mockFunc: func(ctx context.Context, inv *models.Inventory) error {
+				return models.ErrQuantityNeedsReason
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
 		{
 			name:     "success",
 			tenantID: validTenantID,
@@ -190,7 +212,7 @@ func TestCreateInventoryLogic(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := &mockInventoryCreator{CreateInventoryFunc: tt.mockFunc}
-			status, err := createInventoryLogic(mock, tt.tenantID, tt.inv)
+			status, err := createInventoryLogic(mock, tt.tenantID, tt.inv, models.MovementMeta{})
 
 			assert.Equal(t, tt.expectedStatus, status)
 			if tt.expectErr {
@@ -204,13 +226,82 @@ func TestCreateInventoryLogic(t *testing.T) {
 
 // DeleteInventory
 
+type mockInventoryDeleter struct {
+	DeleteInventoryFunc func(ctx context.Context, id uuid.UUID) (*models.Inventory, error)
+}
+
+func (m *mockInventoryDeleter) DeleteInventory(ctx context.Context, id uuid.UUID) (*models.Inventory, error) {
+	return m.DeleteInventoryFunc(ctx, id)
+}
+
+func TestDeleteInventoryLogic(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name           string
+		idStr          string
+		mockFunc       func(ctx context.Context, id uuid.UUID) (*models.Inventory, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid id",
+			idStr:          "invalid-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "not found",
+			idStr: id.String(),
+			mockFunc: func(ctx context.Context, id uuid.UUID) (*models.Inventory, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:  "stock left",
+			idStr: id.String(),
+			mockFunc: func(ctx context.Context, id uuid.UUID) (*models.Inventory, error) {
+				return nil, models.ErrInventoryNotEmpty
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "success",
+			idStr: id.String(),
+			mockFunc: func(ctx context.Context, id uuid.UUID) (*models.Inventory, error) {
+				return &models.Inventory{ID: id}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockInventoryDeleter{DeleteInventoryFunc: tt.mockFunc}
+			_, status, err := deleteInventoryLogic(mock, tt.idStr, models.MovementMeta{})
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// UpdateInventory
+
 type mockInventoryUpdater struct {
-	UpdateInventoryFunc func(ctx context.Context, id uuid.UUID, inv *models.Inventory) error
+	UpdateInventoryFunc func(ctx context.Context, id uuid.UUID, inv *models.Inventory, quantity *int) error
 	GetInventoryFunc    func(ctx context.Context, id uuid.UUID) (*models.Inventory, error)
 }
 
-func (m *mockInventoryUpdater) UpdateInventory(ctx context.Context, id uuid.UUID, inv *models.Inventory) error {
-	return m.UpdateInventoryFunc(ctx, id, inv)
+func (m *mockInventoryUpdater) UpdateInventory(ctx context.Context, id uuid.UUID, inv *models.Inventory, quantity *int) error {
+	return m.UpdateInventoryFunc(ctx, id, inv, quantity)
 }
 func (m *mockInventoryUpdater) GetInventory(ctx context.Context, id uuid.UUID) (*models.Inventory, error) {
 	return m.GetInventoryFunc(ctx, id)
@@ -231,8 +322,8 @@ func TestUpdateInventoryLogic(t *testing.T) {
 	tests := []struct {
 		name           string
 		idStr          string
-		inv            *models.Inventory
-		updateFunc     func(ctx context.Context, id uuid.UUID, inv *models.Inventory) error
+		req            *UpdateInventoryRequest
+		updateFunc     func(ctx context.Context, id uuid.UUID, inv *models.Inventory, quantity *int) error
 		getFunc        func(ctx context.Context, id uuid.UUID) (*models.Inventory, error)
 		tenantFunc     func(ctx context.Context, id uuid.UUID) (*models.Tenant, error)
 		expectedStatus int
@@ -241,14 +332,14 @@ func TestUpdateInventoryLogic(t *testing.T) {
 		{
 			name:           "invalid id",
 			idStr:          "invalid-uuid",
-			inv:            &models.Inventory{},
+			req:            &UpdateInventoryRequest{},
 			expectedStatus: int(http.StatusBadRequest),
 			expectErr:      true,
 		},
 		{
 			name:  "tenant not found",
 			idStr: id.String(),
-			inv:   &models.Inventory{TenantID: validTenantID},
+			req:   &UpdateInventoryRequest{Inventory: models.Inventory{TenantID: validTenantID}},
 			tenantFunc: func(ctx context.Context, id uuid.UUID) (*models.Tenant, error) {
 				return nil, gorm.ErrRecordNotFound
 			},
@@ -258,24 +349,84 @@ func TestUpdateInventoryLogic(t *testing.T) {
 		{
 			name:  "update error",
 			idStr: id.String(),
-			inv:   &models.Inventory{},
+			req:   &UpdateInventoryRequest{},
 			tenantFunc: func(ctx context.Context, id uuid.UUID) (*models.Tenant, error) {
 				return &models.Tenant{}, nil
 			},
-			updateFunc: func(ctx context.Context, id uuid.UUID, inv *models.Inventory) error {
+			updateFunc: func(ctx context.Context, id uuid.UUID, inv *models.Inventory, quantity *int) error {
 				return errors.New("db update error")
 			},
 			expectedStatus: int(http.StatusInternalServerError),
 			expectErr:      true,
 		},
+		{
+			name:  "moving the row to another sku",
+			idStr: id.String(),
+			req:   &UpdateInventoryRequest{Inventory: models.Inventory{SkuID: uuid.New()}},
+			tenantFunc: func(ctx context.Context, id uuid.UUID) (*models.Tenant, error) {
+				return &models.Tenant{}, nil
+			},
+			updateFunc: func(ctx context.Context, id uuid.UUID, inv *models.Inventory, quantity *int) error {
+				return models.ErrInventoryKeyChange
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "quantity change without reason code",
+			idStr: id.String(),
+			req:   &UpdateInventoryRequest{Quantity: intPtr(10)},
+			tenantFunc: func(ctx context.Context, id uuid.UUID) (*models.Tenant, error) {
+				return &models.Tenant{}, nil
+			},
+			updateFunc: func(ctx context.Context, id uuid.UUID, inv *models.Inventory, quantity *int) error {
+				return models.ErrQuantityNeedsReason
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "unknown reason code",
+			idStr: id.String(),
+			req:   &UpdateInventoryRequest{Inventory: models.Inventory{ReasonCode: "nope"}, Quantity: intPtr(10)},
+			tenantFunc: func(ctx context.Context, id uuid.UUID) (*models.Tenant, error) {
+				return &models.Tenant{}, nil
+			},
+			updateFunc: func(ctx context.Context, id uuid.UUID, inv *models.Inventory, quantity *int) error {
+				return models.ErrInvalidReasonCode
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "adjust to zero",
+			idStr: id.String(),
+			req:   &UpdateInventoryRequest{Inventory: models.Inventory{ReasonCode: "correction"}, Quantity: intPtr(0)},
+			tenantFunc: func(ctx context.Context, id uuid.UUID) (*models.Tenant, error) {
+				return &models.Tenant{}, nil
+			},
+			updateFunc: func(ctx context.Context, id uuid.UUID, inv *models.Inventory, quantity *int) error {
+				if quantity == nil || *quantity != 0 {
+					return errors.New("expected quantity 0")
+				}
+				inv.Adjustment = &models.InventoryAdjustment{Delta: -10, ReasonCode: inv.ReasonCode, Status: models.AdjustmentApplied}
+				return nil
+			},
+			getFunc: func(ctx context.Context, id uuid.UUID) (*models.Inventory, error) {
+				return &models.Inventory{ID: id, Quantity: 10}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+			expectErr:      false,
+		},
 		{
 			name:  "success",
 			idStr: id.String(),
-			inv:   &models.Inventory{Quantity: 10},
+			req:   &UpdateInventoryRequest{Inventory: models.Inventory{ReasonCode: "correction"}, Quantity: intPtr(10)},
 			tenantFunc: func(ctx context.Context, id uuid.UUID) (*models.Tenant, error) {
 				return &models.Tenant{}, nil
 			},
-			updateFunc: func(ctx context.Context, id uuid.UUID, inv *models.Inventory) error {
+			updateFunc: func(ctx context.Context, id uuid.UUID, inv *models.Inventory, quantity *int) error {
+				inv.Adjustment = &models.InventoryAdjustment{Delta: 4, ReasonCode: inv.ReasonCode, Status: models.AdjustmentApplied}
 				return nil
 			},
 			getFunc: func(ctx context.Context, id uuid.UUID) (*models.Inventory, error) {
@@ -296,7 +447,7 @@ func TestUpdateInventoryLogic(t *testing.T) {
 				GetTenantFunc: tt.tenantFunc,
 			}
 
-			result, status, err := updateInventoryLogic(updater, tenantValidator, tt.idStr, tt.inv)
+			result, status, err := updateInventoryLogic(updater, tenantValidator, tt.idStr, tt.req, models.MovementMeta{})
 
 			assert.Equal(t, tt.expectedStatus, status)
 			if tt.expectErr {
@@ -304,6 +455,7 @@ func TestUpdateInventoryLogic(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, 10, result.Quantity)
+				assert.NotNil(t, result.Adjustment)
 			}
 		})
 	}
@@ -356,6 +508,26 @@ func TestUpsertInventoryLogic(t *testing.T) {
 			expectedStatus: int(http.StatusInternalServerError),
 			expectErr:      true,
 		},
+		{
+			name:        "quantity change without reason code",
+			tenantIDStr: validTenant.String(),
+			input:       &models.Inventory{Quantity: 5},
+			mockFunc: func(ctx context.Context, inv *models.Inventory) error {
+				return models.ErrQuantityNeedsReason
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:        "decrease below zero",
+			tenantIDStr: validTenant.String(),
+			input:       &models.Inventory{Quantity: 5, ReasonCode: "correction"},
+			mockFunc: func(ctx context.Context, inv *models.Inventory) error {
+				return models.ErrInsufficientStock
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
 		{
 			name:        "success",
 			tenantIDStr: validTenant.String(),
@@ -373,7 +545,7 @@ func TestUpsertInventoryLogic(t *testing.T) {
 			mock := &mockInventoryUpserter{
 				UpsertInventoryFunc: tt.mockFunc,
 			}
-			status, err := upsertInventoryLogic(mock, tt.tenantIDStr, tt.input)
+			status, err := upsertInventoryLogic(mock, tt.tenantIDStr, tt.input, models.MovementMeta{})
 
 			assert.Equal(t, tt.expectedStatus, status)
 			if tt.expectErr {
@@ -388,11 +560,11 @@ func TestUpsertInventoryLogic(t *testing.T) {
 // ViewInventoryWithDefault
 
 type mockInventoryViewer struct {
-	GetInventoryWithDefaultsFunc func(ctx context.Context, tenantID, hubID uuid.UUID) ([]models.InventoryView, error)
+	GetInventoryWithDefaultsFunc func(ctx context.Context, tenantID, hubID uuid.UUID, asOf *time.Time) ([]models.InventoryView, error)
 }
 
-func (m *mockInventoryViewer) GetInventoryWithDefaults(ctx context.Context, tenantID, hubID uuid.UUID) ([]models.InventoryView, error) {
-	return m.GetInventoryWithDefaultsFunc(ctx, tenantID, hubID)
+func (m *mockInventoryViewer) GetInventoryWithDefaults(ctx context.Context, tenantID, hubID uuid.UUID, asOf *time.Time) ([]models.InventoryView, error) {
+	return m.GetInventoryWithDefaultsFunc(ctx, tenantID, hubID, asOf)
 }
 
 func TestViewInventoryWithDefaultsLogic(t *testing.T) {
@@ -403,7 +575,8 @@ func TestViewInventoryWithDefaultsLogic(t *testing.T) {
 		name           string
 		tenantIDStr    string
 		hubIDStr       string
-		mockFunc       func(ctx context.Context, tenantID, hubID uuid.UUID) ([]models.InventoryView, error)
+		asOfStr        string
+		mockFunc       func(ctx context.Context, tenantID, hubID uuid.UUID, asOf *time.Time) ([]models.InventoryView, error)
 		expectedStatus int
 		expectErr      bool
 	}{
@@ -421,11 +594,19 @@ func TestViewInventoryWithDefaultsLogic(t *testing.T) {
 			expectedStatus: int(http.StatusBadRequest),
 			expectErr:      true,
 		},
+		{
+			name:           "invalid as_of",
+			tenantIDStr:    validTenantID.String(),
+			hubIDStr:       validHubID.String(),
+			asOfStr:        "March 31",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
 		{
 			name:        "DB error",
 			tenantIDStr: validTenantID.String(),
 			hubIDStr:    validHubID.String(),
-			mockFunc: func(ctx context.Context, tenantID, hubID uuid.UUID) ([]models.InventoryView, error) {
+			mockFunc: func(ctx context.Context, tenantID, hubID uuid.UUID, asOf *time.Time) ([]models.InventoryView, error) {
 				return nil, errors.New("DB error")
 			},
 			expectedStatus: int(http.StatusInternalServerError),
@@ -435,12 +616,38 @@ func TestViewInventoryWithDefaultsLogic(t *testing.T) {
 			name:        "success",
 			tenantIDStr: validTenantID.String(),
 			hubIDStr:    validHubID.String(),
-			mockFunc: func(ctx context.Context, tenantID, hubID uuid.UUID) ([]models.InventoryView, error) {
+			mockFunc: func(ctx context.Context, tenantID, hubID uuid.UUID, asOf *time.Time) ([]models.InventoryView, error) {
 				return []models.InventoryView{{SkuCode: "sku-123", Quantity: 0}}, nil
 			},
 			expectedStatus: int(http.StatusOK),
 			expectErr:      false,
 		},
+		{
+			name:        "as of a past date",
+			tenantIDStr: validTenantID.String(),
+			hubIDStr:    validHubID.String(),
+			asOfStr:     "2026-03-31",
+			mockFunc: func(ctx context.Context, tenantID, hubID uuid.UUID, asOf *time.Time) ([]models.InventoryView, error) {
+				if asOf == nil || !asOf.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) {
+					return nil, errors.New("expected the end of March 31")
+				}
+				return []models.InventoryView{{SkuCode: "sku-123", OnHand: 4, Quantity: 4}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:        "as of today is the live view",
+			tenantIDStr: validTenantID.String(),
+			hubIDStr:    validHubID.String(),
+			asOfStr:     time.Now().UTC().Format(time.DateOnly),
+			mockFunc: func(ctx context.Context, tenantID, hubID uuid.UUID, asOf *time.Time) ([]models.InventoryView, error) {
+				if asOf != nil {
+					return nil, errors.New("expected the live view")
+				}
+				return []models.InventoryView{{SkuCode: "sku-123"}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
 	}
 
 	for _, tt := range tests {
@@ -448,7 +655,7 @@ func TestViewInventoryWithDefaultsLogic(t *testing.T) {
 			mock := &mockInventoryViewer{
 				GetInventoryWithDefaultsFunc: tt.mockFunc,
 			}
-			result, status, err := viewInventoryWithDefaultsLogic(mock, tt.tenantIDStr, tt.hubIDStr)
+			result, status, err := viewInventoryWithDefaultsLogic(mock, tt.tenantIDStr, tt.hubIDStr, tt.asOfStr)
 
 			assert.Equal(t, tt.expectedStatus, status)
 			if tt.expectErr {
@@ -464,111 +671,245 @@ func TestViewInventoryWithDefaultsLogic(t *testing.T) {
 // CheckAndUpdateInventory
 
 type mockInventoryChecker struct {
-	GetInventoryBySkuHubFunc    func(ctx context.Context, skuID, hubID uuid.UUID) (*models.Inventory, error)
-	UpdateInventoryQuantityFunc func(ctx context.Context, invID uuid.UUID, newQty int) error
+	AllocateInventoryFunc func(ctx context.Context, skuID, hubID uuid.UUID, quantity int, allowPartial bool) (*models.StockAllocation, error)
+	ToBaseQuantityFunc    func(ctx context.Context, skuID uuid.UUID, unit string, quantity int) (int, error)
 }
 
-func (m *mockInventoryChecker) GetInventoryBySkuHub(ctx context.Context, skuID, hubID uuid.UUID) (*models.Inventory, error) {
-	return m.GetInventoryBySkuHubFunc(ctx, skuID, hubID)
+func (m *mockInventoryChecker) AllocateInventory(ctx context.Context, skuID, hubID uuid.UUID, quantity int, allowPartial bool) (*models.StockAllocation, error) {
+	return m.AllocateInventoryFunc(ctx, skuID, hubID, quantity, allowPartial)
 }
 
-func (m *mockInventoryChecker) UpdateInventoryQuantity(ctx context.Context, invID uuid.UUID, newQty int) error {
-	return m.UpdateInventoryQuantityFunc(ctx, invID, newQty)
+func (m *mockInventoryChecker) ToBaseQuantity(ctx context.Context, skuID uuid.UUID, unit string, quantity int) (int, error) {
+	if m.ToBaseQuantityFunc == nil {
+		return quantity, nil
+	}
+	return m.ToBaseQuantityFunc(ctx, skuID, unit, quantity)
 }
 
 func TestCheckAndUpdateInventoryLogic(t *testing.T) {
 	skuID := uuid.New()
 	hubID := uuid.New()
-	invID := uuid.New()
 
 	tests := []struct {
-		name           string
-		req            CheckInventoryRequest
-		mockFetch      func(ctx context.Context, skuID, hubID uuid.UUID) (*models.Inventory, error)
-		mockUpdate     func(ctx context.Context, invID uuid.UUID, newQty int) error
-		expectedAvail  bool
-		expectedStatus int
-		expectErr      bool
+		name              string
+		req               CheckInventoryRequest
+		mockAllocate      func(ctx context.Context, skuID, hubID uuid.UUID, quantity int, allowPartial bool) (*models.StockAllocation, error)
+		mockConvert       func(ctx context.Context, skuID uuid.UUID, unit string, quantity int) (int, error)
+		expectedAvail     bool
+		expectedAllocated int
+		expectedRemaining int
+		expectedStatus    int
+		expectErr         bool
 	}{
 		{
-			name: "inventory not found",
-			req: CheckInventoryRequest{
-				SKUID:    skuID,
-				HubID:    hubID,
-				Quantity: 5,
-			},
-			mockFetch: func(ctx context.Context, skuID, hubID uuid.UUID) (*models.Inventory, error) {
-				return nil, gorm.ErrRecordNotFound
+			name:           "non-positive quantity",
+			req:            CheckInventoryRequest{SKUID: skuID, HubID: hubID, Quantity: -5},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "insufficient or missing inventory",
+			req:  CheckInventoryRequest{SKUID: skuID, HubID: hubID, Quantity: 10},
+			mockAllocate: func(ctx context.Context, skuID, hubID uuid.UUID, quantity int, allowPartial bool) (*models.StockAllocation, error) {
+				assert.False(t, allowPartial)
+				return &models.StockAllocation{Requested: 10, Remaining: 10, CurrentStock: 5}, nil
 			},
-			mockUpdate:     nil,
-			expectedAvail:  false,
-			expectedStatus: int(http.StatusOK),
-			expectErr:      false,
+			expectedAvail:     false,
+			expectedRemaining: 10,
+			expectedStatus:    int(http.StatusOK),
 		},
 		{
-			name: "inventory fetch error",
+			name: "update error",
 			req:  CheckInventoryRequest{SKUID: skuID, HubID: hubID, Quantity: 5},
-			mockFetch: func(ctx context.Context, skuID, hubID uuid.UUID) (*models.Inventory, error) {
-				return nil, errors.New("DB error")
+			mockAllocate: func(ctx context.Context, skuID, hubID uuid.UUID, quantity int, allowPartial bool) (*models.StockAllocation, error) {
+				return nil, errors.New("update fail")
 			},
-			mockUpdate:     nil,
-			expectedAvail:  false,
 			expectedStatus: int(http.StatusInternalServerError),
 			expectErr:      true,
 		},
 		{
-			name: "insufficient inventory",
-			req:  CheckInventoryRequest{SKUID: skuID, HubID: hubID, Quantity: 10},
-			mockFetch: func(ctx context.Context, skuID, hubID uuid.UUID) (*models.Inventory, error) {
-				return &models.Inventory{ID: invID, Quantity: 5}, nil
+			name: "success",
+			req:  CheckInventoryRequest{SKUID: skuID, HubID: hubID, Quantity: 3},
+			mockAllocate: func(ctx context.Context, s, h uuid.UUID, quantity int, allowPartial bool) (*models.StockAllocation, error) {
+				assert.Equal(t, skuID, s)
+				assert.Equal(t, hubID, h)
+				assert.Equal(t, 3, quantity)
+				return &models.StockAllocation{Requested: 3, Allocated: 3, CurrentStock: 7}, nil
 			},
-			mockUpdate:     nil,
-			expectedAvail:  false,
-			expectedStatus: int(http.StatusOK),
-			expectErr:      false,
+			expectedAvail:     true,
+			expectedAllocated: 3,
+			expectedStatus:    int(http.StatusOK),
 		},
 		{
-			name: "update error",
-			req:  CheckInventoryRequest{SKUID: skuID, HubID: hubID, Quantity: 5},
-			mockFetch: func(ctx context.Context, skuID, hubID uuid.UUID) (*models.Inventory, error) {
-				return &models.Inventory{ID: invID, Quantity: 10}, nil
+			name: "unknown unit",
+			req:  CheckInventoryRequest{SKUID: skuID, HubID: hubID, Quantity: 1, Unit: "pallet"},
+			mockConvert: func(ctx context.Context, skuID uuid.UUID, unit string, quantity int) (int, error) {
+				return 0, fmt.Errorf("%w: %s", models.ErrUnknownUnit, unit)
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "non-integral conversion",
+			req:  CheckInventoryRequest{SKUID: skuID, HubID: hubID, Quantity: 5, Unit: "each"},
+			mockConvert: func(ctx context.Context, skuID uuid.UUID, unit string, quantity int) (int, error) {
+				return 0, models.ErrNonIntegralConversion
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "cases converted to base units",
+			req:  CheckInventoryRequest{SKUID: skuID, HubID: hubID, Quantity: 2, Unit: "case"},
+			mockConvert: func(ctx context.Context, skuID uuid.UUID, unit string, quantity int) (int, error) {
+				assert.Equal(t, "case", unit)
+				return quantity * 24, nil
 			},
-			mockUpdate: func(ctx context.Context, invID uuid.UUID, newQty int) error {
-				return errors.New("update fail")
+			mockAllocate: func(ctx context.Context, skuID, hubID uuid.UUID, quantity int, allowPartial bool) (*models.StockAllocation, error) {
+				assert.Equal(t, 48, quantity)
+				return &models.StockAllocation{Requested: 48, Allocated: 48, CurrentStock: 2}, nil
+			},
+			expectedAvail:     true,
+			expectedAllocated: 48,
+			expectedStatus:    int(http.StatusOK),
+		},
+		{
+			name: "partial allocation",
+			req:  CheckInventoryRequest{SKUID: skuID, HubID: hubID, Quantity: 10, AllowPartial: true},
+			mockAllocate: func(ctx context.Context, skuID, hubID uuid.UUID, quantity int, allowPartial bool) (*models.StockAllocation, error) {
+				assert.True(t, allowPartial)
+				return &models.StockAllocation{Requested: 10, Allocated: 4, Remaining: 6, CurrentStock: 0}, nil
+			},
+			expectedAvail:     false,
+			expectedAllocated: 4,
+			expectedRemaining: 6,
+			expectedStatus:    int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockInventoryChecker{AllocateInventoryFunc: tt.mockAllocate, ToBaseQuantityFunc: tt.mockConvert}
+			result, status, err := checkAndUpdateInventoryLogic(mock, tt.req, models.MovementMeta{})
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedAvail, result.Available)
+				assert.Equal(t, tt.expectedAllocated, result.Allocated)
+				assert.Equal(t, tt.expectedRemaining, result.Remaining)
+			}
+		})
+	}
+}
+
+// BatchCheckAndUpdateInventory
+
+type mockInventoryBatchChecker struct {
+	DecrementInventoryLinesFunc func(ctx context.Context, lines []models.StockLine) ([]models.StockShortage, error)
+}
+
+func (m *mockInventoryBatchChecker) DecrementInventoryLines(ctx context.Context, lines []models.StockLine) ([]models.StockShortage, error) {
+	return m.DecrementInventoryLinesFunc(ctx, lines)
+}
+
+func TestBatchCheckAndUpdateInventoryLogic(t *testing.T) {
+	hubID := uuid.New()
+	skuA := uuid.New()
+	skuB := uuid.New()
+	lines := []models.StockLine{
+		{SkuID: skuA, HubID: hubID, Quantity: 2},
+		{SkuID: skuB, HubID: hubID, Quantity: 5},
+	}
+
+	tests := []struct {
+		name              string
+		req               BatchCheckInventoryRequest
+		mockFunc          func(ctx context.Context, lines []models.StockLine) ([]models.StockShortage, error)
+		expectedAvail     bool
+		expectedShortages int
+		expectedStatus    int
+		expectErr         bool
+	}{
+		{
+			name:           "no lines",
+			req:            BatchCheckInventoryRequest{},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "line without hub",
+			req: BatchCheckInventoryRequest{Lines: []models.StockLine{
+				{SkuID: skuA, Quantity: 1},
+			}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "non-positive quantity",
+			req: BatchCheckInventoryRequest{Lines: []models.StockLine{
+				{SkuID: skuA, HubID: hubID, Quantity: 0},
+			}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "db error",
+			req:  BatchCheckInventoryRequest{Lines: lines},
+			mockFunc: func(ctx context.Context, lines []models.StockLine) ([]models.StockShortage, error) {
+				return nil, errors.New("db error")
 			},
-			expectedAvail:  false,
 			expectedStatus: int(http.StatusInternalServerError),
 			expectErr:      true,
 		},
 		{
-			name: "success",
-			req:  CheckInventoryRequest{SKUID: skuID, HubID: hubID, Quantity: 3},
-			mockFetch: func(ctx context.Context, skuID, hubID uuid.UUID) (*models.Inventory, error) {
-				return &models.Inventory{ID: invID, Quantity: 10}, nil
+			name: "line in unknown unit",
+			req: BatchCheckInventoryRequest{Lines: []models.StockLine{
+				{SkuID: skuA, HubID: hubID, Quantity: 1, Unit: "pallet"},
+			}},
+			mockFunc: func(ctx context.Context, lines []models.StockLine) ([]models.StockShortage, error) {
+				return nil, fmt.Errorf("%w: pallet", models.ErrUnknownUnit)
 			},
-			mockUpdate: func(ctx context.Context, invID uuid.UUID, newQty int) error {
-				assert.Equal(t, 7, newQty)
-				return nil
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "one line short rolls back the order",
+			req:  BatchCheckInventoryRequest{Lines: lines},
+			mockFunc: func(ctx context.Context, lines []models.StockLine) ([]models.StockShortage, error) {
+				return []models.StockShortage{
+					{SkuID: skuB, HubID: hubID, Requested: 5, Available: 3, ShortBy: 2},
+				}, nil
+			},
+			expectedAvail:     false,
+			expectedShortages: 1,
+			expectedStatus:    int(http.StatusOK),
+		},
+		{
+			name: "success",
+			req:  BatchCheckInventoryRequest{OrderReference: "ORD-9", Lines: lines},
+			mockFunc: func(ctx context.Context, got []models.StockLine) ([]models.StockShortage, error) {
+				assert.Equal(t, lines, got)
+				return nil, nil
 			},
 			expectedAvail:  true,
 			expectedStatus: int(http.StatusOK),
-			expectErr:      false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mock := &mockInventoryChecker{
-				GetInventoryBySkuHubFunc:    tt.mockFetch,
-				UpdateInventoryQuantityFunc: tt.mockUpdate,
-			}
-			ok, status, err := checkAndUpdateInventoryLogic(mock, tt.req)
-			assert.Equal(t, tt.expectedAvail, ok)
+			mock := &mockInventoryBatchChecker{DecrementInventoryLinesFunc: tt.mockFunc}
+			result, status, err := batchCheckAndUpdateInventoryLogic(mock, tt.req, models.MovementMeta{})
+
 			assert.Equal(t, tt.expectedStatus, status)
 			if tt.expectErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedAvail, result.Available)
+				assert.Len(t, result.Shortages, tt.expectedShortages)
 			}
 		})
 	}
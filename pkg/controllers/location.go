@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type MoveBinStockRequest struct {
+	SkuID     uuid.UUID  `json:"sku_id" binding:"required"`
+	FromBinID *uuid.UUID `json:"from_bin_id"`
+	ToBinID   *uuid.UUID `json:"to_bin_id"`
+	Quantity  int        `json:"quantity" binding:"required"`
+}
+
+// GetLocations
+
+type LocationFetcher interface {
+	GetLocations(ctx context.Context, hubID uuid.UUID) ([]models.Location, error)
+}
+
+func getLocationsLogic(service LocationFetcher, hubIDStr string) ([]models.Location, int, error) {
+	hubID, err := uuid.Parse(hubIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid hub_id")
+	}
+
+	locations, err := service.GetLocations(context.Background(), hubID)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), err
+	}
+
+	return locations, int(http.StatusOK), nil
+}
+
+// GetLocations godoc
+// @Summary List the zones, aisles and bins of a hub
+// @Tags Locations
+// @Produce json
+// @Param id path string true "Hub ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {array} models.Location
+// @Router /hubs/{id}/locations [get]
+func GetLocations(c *gin.Context) {
+	locations, status, err := getLocationsLogic(models.LocationModel{}, c.Param("id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, locations)
+}
+
+// CreateLocation
+
+type LocationCreator interface {
+	CreateLocation(ctx context.Context, location *models.Location) error
+}
+
+func createLocationLogic(service LocationCreator, hubIDStr string, location *models.Location) (int, error) {
+	hubID, err := uuid.Parse(hubIDStr)
+	if err != nil {
+		return int(http.StatusBadRequest), errors.New("invalid hub_id")
+	}
+	location.HubID = hubID
+
+	if location.Code == "" {
+		return int(http.StatusBadRequest), errors.New("missing location code")
+	}
+
+	if err := service.CreateLocation(context.Background(), location); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return int(http.StatusNotFound), errors.New("hub not found")
+		}
+		if errors.Is(err, models.ErrInvalidLocation) {
+			return int(http.StatusBadRequest), errors.New("invalid location type or parent")
+		}
+		return int(http.StatusInternalServerError), errors.New("failed to create location")
+	}
+
+	return int(http.StatusCreated), nil
+}
+
+// CreateLocation godoc
+// @Summary Create a zone, aisle (parent: zone) or bin (parent: aisle) in a hub
+// @Tags Locations
+// @Accept json
+// @Produce json
+// @Param id path string true "Hub ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param location body models.Location true "Location to create"
+// @Success 201 {object} models.Location
+// @Router /hubs/{id}/locations [post]
+func CreateLocation(c *gin.Context) {
+	var location models.Location
+	if err := c.Bind(&location); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request body")})
+		return
+	}
+
+	status, err := createLocationLogic(models.LocationModel{}, c.Param("id"), &location)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, location)
+}
+
+// GetBinStock
+
+type BinStockFetcher interface {
+	GetBinStock(ctx context.Context, hubID, skuID uuid.UUID) ([]models.BinStockView, error)
+}
+
+func getBinStockLogic(service BinStockFetcher, hubIDStr, skuIDStr string) ([]models.BinStockView, int, error) {
+	hubID, err := uuid.Parse(hubIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid hub_id")
+	}
+
+	var skuID uuid.UUID
+	if skuIDStr != "" {
+		skuID, err = uuid.Parse(skuIDStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid sku_id")
+		}
+	}
+
+	stock, err := service.GetBinStock(context.Background(), hubID, skuID)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), err
+	}
+
+	return stock, int(http.StatusOK), nil
+}
+
+// GetBinStock godoc
+// @Summary List per-bin stock in a hub
+// @Tags Locations
+// @Produce json
+// @Param id path string true "Hub ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param sku_id query string false "Filter by SKU ID"
+// @Success 200 {array} models.BinStockView
+// @Router /hubs/{id}/bins/inventory [get]
+func GetBinStock(c *gin.Context) {
+	stock, status, err := getBinStockLogic(models.LocationModel{}, c.Param("id"), c.Query("sku_id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, stock)
+}
+
+// MoveBinStock
+
+type BinStockMover interface {
+	MoveBinStock(ctx context.Context, move models.BinMove) error
+}
+
+func moveBinStockLogic(service BinStockMover, hubIDStr string, req MoveBinStockRequest) (int, error) {
+	hubID, err := uuid.Parse(hubIDStr)
+	if err != nil {
+		return int(http.StatusBadRequest), errors.New("invalid hub_id")
+	}
+
+	if req.Quantity <= 0 {
+		return int(http.StatusBadRequest), errors.New("quantity must be positive")
+	}
+
+	if req.FromBinID == nil && req.ToBinID == nil {
+		return int(http.StatusBadRequest), errors.New("from_bin_id or to_bin_id is required")
+	}
+	if req.FromBinID != nil && req.ToBinID != nil && *req.FromBinID == *req.ToBinID {
+		return int(http.StatusBadRequest), errors.New("from_bin_id and to_bin_id must differ")
+	}
+
+	move := models.BinMove{
+		HubID:     hubID,
+		SkuID:     req.SkuID,
+		FromBinID: req.FromBinID,
+		ToBinID:   req.ToBinID,
+		Quantity:  req.Quantity,
+	}
+
+	if err := service.MoveBinStock(context.Background(), move); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return int(http.StatusNotFound), errors.New("inventory not found")
+		}
+		if errors.Is(err, models.ErrInvalidLocation) {
+			return int(http.StatusBadRequest), errors.New("bin not found in hub")
+		}
+		if errors.Is(err, models.ErrInsufficientStock) {
+			return int(http.StatusBadRequest), err
+		}
+		return int(http.StatusInternalServerError), errors.New("failed to move stock")
+	}
+
+	return int(http.StatusOK), nil
+}
+
+// MoveBinStock godoc
+// @Summary Move stock between bins; omit from_bin_id to put away unbinned stock, omit to_bin_id to unbin it
+// @Tags Locations
+// @Accept json
+// @Produce json
+// @Param id path string true "Hub ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body MoveBinStockRequest true "Bin move"
+// @Success 200 {object} map[string]string
+// @Router /hubs/{id}/bins/move [post]
+func MoveBinStock(c *gin.Context) {
+	var req MoveBinStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	status, err := moveBinStockLogic(models.LocationModel{}, c.Param("id"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, gin.H{i18n.Translate(c, "message"): i18n.Translate(c, "Stock moved")})
+}
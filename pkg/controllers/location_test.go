@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// CreateLocation
+
+type mockLocationCreator struct {
+	CreateLocationFunc func(ctx context.Context, location *models.Location) error
+}
+
+func (m *mockLocationCreator) CreateLocation(ctx context.Context, location *models.Location) error {
+	return m.CreateLocationFunc(ctx, location)
+}
+
+func TestCreateLocationLogic(t *testing.T) {
+	hubID := uuid.New()
+
+	tests := []struct {
+		name           string
+		hubID          string
+		location       models.Location
+		mockFunc       func(ctx context.Context, location *models.Location) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid hub id",
+			hubID:          "bad-uuid",
+			location:       models.Location{Type: models.LocationZone, Code: "Z1"},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "missing code",
+			hubID:          hubID.String(),
+			location:       models.Location{Type: models.LocationZone},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "hub not found",
+			hubID:    hubID.String(),
+			location: models.Location{Type: models.LocationZone, Code: "Z1"},
+			mockFunc: func(ctx context.Context, location *models.Location) error {
+				return gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:     "bin without aisle parent",
+			hubID:    hubID.String(),
+			location: models.Location{Type: models.LocationBin, Code: "B1"},
+			mockFunc: func(ctx context.Context, location *models.Location) error {
+				return models.ErrInvalidLocation
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "db error",
+			hubID:    hubID.String(),
+			location: models.Location{Type: models.LocationZone, Code: "Z1"},
+			mockFunc: func(ctx context.Context, location *models.Location) error {
+				return errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:     "success",
+			hubID:    hubID.String(),
+			location: models.Location{Type: models.LocationZone, Code: "Z1"},
+			mockFunc: func(ctx context.Context, location *models.Location) error {
+				assert.Equal(t, hubID, location.HubID)
+				return nil
+			},
+			expectedStatus: int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockLocationCreator{CreateLocationFunc: tt.mockFunc}
+			status, err := createLocationLogic(mock, tt.hubID, &tt.location)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// GetBinStock
+
+type mockBinStockFetcher struct {
+	GetBinStockFunc func(ctx context.Context, hubID, skuID uuid.UUID) ([]models.BinStockView, error)
+}
+
+func (m *mockBinStockFetcher) GetBinStock(ctx context.Context, hubID, skuID uuid.UUID) ([]models.BinStockView, error) {
+	return m.GetBinStockFunc(ctx, hubID, skuID)
+}
+
+func TestGetBinStockLogic(t *testing.T) {
+	hubID := uuid.New()
+	skuID := uuid.New()
+
+	tests := []struct {
+		name           string
+		hubID          string
+		skuID          string
+		mockFunc       func(ctx context.Context, hubID, skuID uuid.UUID) ([]models.BinStockView, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid hub id",
+			hubID:          "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid sku id",
+			hubID:          hubID.String(),
+			skuID:          "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "all skus",
+			hubID: hubID.String(),
+			mockFunc: func(ctx context.Context, h, s uuid.UUID) ([]models.BinStockView, error) {
+				assert.Equal(t, uuid.Nil, s)
+				return []models.BinStockView{{SkuID: skuID, Quantity: 3}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:  "one sku",
+			hubID: hubID.String(),
+			skuID: skuID.String(),
+			mockFunc: func(ctx context.Context, h, s uuid.UUID) ([]models.BinStockView, error) {
+				assert.Equal(t, skuID, s)
+				return []models.BinStockView{{SkuID: skuID, Quantity: 3}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockBinStockFetcher{GetBinStockFunc: tt.mockFunc}
+			_, status, err := getBinStockLogic(mock, tt.hubID, tt.skuID)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// MoveBinStock
+
+type mockBinStockMover struct {
+	MoveBinStockFunc func(ctx context.Context, move models.BinMove) error
+}
+
+func (m *mockBinStockMover) MoveBinStock(ctx context.Context, move models.BinMove) error {
+	return m.MoveBinStockFunc(ctx, move)
+}
+
+func TestMoveBinStockLogic(t *testing.T) {
+	hubID := uuid.New()
+	binA := uuid.New()
+	binB := uuid.New()
+	valid := MoveBinStockRequest{SkuID: uuid.New(), FromBinID: &binA, ToBinID: &binB, Quantity: 2}
+
+	tests := []struct {
+		name           string
+		hubID          string
+		req            MoveBinStockRequest
+		mockFunc       func(ctx context.Context, move models.BinMove) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid hub id",
+			hubID:          "bad-uuid",
+			req:            valid,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "non-positive quantity",
+			hubID:          hubID.String(),
+			req:            MoveBinStockRequest{SkuID: valid.SkuID, ToBinID: &binB, Quantity: 0},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "no bins",
+			hubID:          hubID.String(),
+			req:            MoveBinStockRequest{SkuID: valid.SkuID, Quantity: 2},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "same bin",
+			hubID:          hubID.String(),
+			req:            MoveBinStockRequest{SkuID: valid.SkuID, FromBinID: &binA, ToBinID: &binA, Quantity: 2},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "inventory not found",
+			hubID: hubID.String(),
+			req:   valid,
+			mockFunc: func(ctx context.Context, move models.BinMove) error {
+				return gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:  "bin from another hub",
+			hubID: hubID.String(),
+			req:   valid,
+			mockFunc: func(ctx context.Context, move models.BinMove) error {
+				return models.ErrInvalidLocation
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "source bin short",
+			hubID: hubID.String(),
+			req:   valid,
+			mockFunc: func(ctx context.Context, move models.BinMove) error {
+				return models.ErrInsufficientStock
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "put away unbinned stock",
+			hubID: hubID.String(),
+			req:   MoveBinStockRequest{SkuID: valid.SkuID, ToBinID: &binB, Quantity: 2},
+			mockFunc: func(ctx context.Context, move models.BinMove) error {
+				assert.Equal(t, hubID, move.HubID)
+				assert.Nil(t, move.FromBinID)
+				return nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:  "success",
+			hubID: hubID.String(),
+			req:   valid,
+			mockFunc: func(ctx context.Context, move models.BinMove) error {
+				return nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockBinStockMover{MoveBinStockFunc: tt.mockFunc}
+			status, err := moveBinStockLogic(mock, tt.hubID, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/constants"
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type ReceiveLotRequest struct {
+	LotNumber      string   `json:"lot_number" binding:"required"`
+	ManufacturedAt string   `json:"manufactured_at" example:"2026-01-31"`
+	ExpiresAt      string   `json:"expires_at" example:"2026-07-31"`
+	Quantity       int      `json:"quantity" binding:"required"`
+	UnitCost       *float64 `json:"unit_cost"` // per unit; defaults to the row's current average cost
+}
+
+// GetInventoryLots
+
+type InventoryLotFetcher interface {
+	GetInventoryLots(ctx context.Context, inventoryID uuid.UUID) ([]models.InventoryLot, error)
+}
+
+func getInventoryLotsLogic(service InventoryLotFetcher, idStr string) ([]models.InventoryLot, int, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid inventory id")
+	}
+
+	lots, err := service.GetInventoryLots(context.Background(), id)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch lots")
+	}
+
+	return lots, int(http.StatusOK), nil
+}
+
+// GetInventoryLots godoc
+// @Summary List the lots holding stock of an inventory row, first expiry first
+// @Tags Lots
+// @Produce json
+// @Param id path string true "Inventory ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {array} models.InventoryLot
+// @Router /inventories/{id}/lots [get]
+func GetInventoryLots(c *gin.Context) {
+	lots, status, err := getInventoryLotsLogic(models.LotModel{}, c.Param("id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, lots)
+}
+
+// ReceiveLot
+
+type LotReceiver interface {
+	ReceiveLot(ctx context.Context, inventoryID uuid.UUID, lot *models.InventoryLot) error
+}
+
+func receiveLotLogic(service LotReceiver, idStr string, req ReceiveLotRequest, meta models.MovementMeta) (*models.InventoryLot, int, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid inventory id")
+	}
+
+	if req.Quantity <= 0 {
+		return nil, int(http.StatusBadRequest), errors.New("quantity must be positive")
+	}
+
+	manufacturedAt, err := parseOptionalDate(req.ManufacturedAt, "manufactured_at")
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	expiresAt, err := parseOptionalDate(req.ExpiresAt, "expires_at")
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	if manufacturedAt != nil && expiresAt != nil && expiresAt.Before(*manufacturedAt) {
+		return nil, int(http.StatusBadRequest), errors.New("expires_at must not be before manufactured_at")
+	}
+
+	lot := &models.InventoryLot{
+		LotNumber:      req.LotNumber,
+		ManufacturedAt: manufacturedAt,
+		ExpiresAt:      expiresAt,
+		Quantity:       req.Quantity,
+		UnitCost:       req.UnitCost,
+	}
+
+	if err := service.ReceiveLot(models.WithMovementMeta(context.Background(), meta), id, lot); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("inventory not found")
+		}
+		if errors.Is(err, models.ErrLotMismatch) || errors.Is(err, models.ErrSerializedSku) || errors.Is(err, models.ErrInvalidUnitCost) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to receive lot")
+	}
+
+	return lot, int(http.StatusCreated), nil
+}
+
+// ReceiveLot godoc
+// @Summary Receive stock into a lot of an inventory row, adding to its quantity
+// @Tags Lots
+// @Accept json
+// @Produce json
+// @Param id path string true "Inventory ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body ReceiveLotRequest true "Lot receipt"
+// @Success 201 {object} models.InventoryLot
+// @Router /inventories/{id}/lots [post]
+func ReceiveLot(c *gin.Context) {
+	var req ReceiveLotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	lot, status, err := receiveLotLogic(models.LotModel{}, c.Param("id"), req, movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, lot)
+}
+
+// GetExpiringLots
+
+type ExpiringLotFetcher interface {
+	GetExpiringLots(ctx context.Context, hubID uuid.UUID, before time.Time) ([]models.ExpiringLotView, error)
+}
+
+func getExpiringLotsLogic(service ExpiringLotFetcher, hubIDStr, daysStr string) ([]models.ExpiringLotView, int, error) {
+	hubID, err := uuid.Parse(hubIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid hub_id")
+	}
+
+	days := constants.DefaultLotExpiryWindowDays
+	if daysStr != "" {
+		days, err = strconv.Atoi(daysStr)
+		if err != nil || days < 0 {
+			return nil, int(http.StatusBadRequest), errors.New("invalid days")
+		}
+	}
+
+	lots, err := service.GetExpiringLots(context.Background(), hubID, time.Now().AddDate(0, 0, days))
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch expiring lots")
+	}
+
+	return lots, int(http.StatusOK), nil
+}
+
+// GetExpiringLots godoc
+// @Summary List lots in a hub expiring within N days, already expired included
+// @Tags Lots
+// @Produce json
+// @Param id path string true "Hub ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param days query int false "Window in days (default 30)"
+// @Success 200 {array} models.ExpiringLotView
+// @Router /hubs/{id}/lots/expiring [get]
+func GetExpiringLots(c *gin.Context) {
+	lots, status, err := getExpiringLotsLogic(models.LotModel{}, c.Param("id"), c.Query("days"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, lots)
+}
@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// ReceiveLot
+
+type mockLotReceiver struct {
+	ReceiveLotFunc func(ctx context.Context, inventoryID uuid.UUID, lot *models.InventoryLot) error
+}
+
+func (m *mockLotReceiver) ReceiveLot(ctx context.Context, inventoryID uuid.UUID, lot *models.InventoryLot) error {
+	return m.ReceiveLotFunc(ctx, inventoryID, lot)
+}
+
+func TestReceiveLotLogic(t *testing.T) {
+	id := uuid.New()
+	valid := ReceiveLotRequest{LotNumber: "L-1", ManufacturedAt: "2026-01-01", ExpiresAt: "2026-06-30", Quantity: 10}
+
+	tests := []struct {
+		name           string
+		id             string
+		req            ReceiveLotRequest
+		mockFunc       func(ctx context.Context, inventoryID uuid.UUID, lot *models.InventoryLot) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid id",
+			id:             "bad-uuid",
+			req:            valid,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "non-positive quantity",
+			id:             id.String(),
+			req:            ReceiveLotRequest{LotNumber: "L-1", Quantity: -2},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "bad expiry date",
+			id:             id.String(),
+			req:            ReceiveLotRequest{LotNumber: "L-1", ExpiresAt: "30/06/2026", Quantity: 1},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "expires before manufacture",
+			id:             id.String(),
+			req:            ReceiveLotRequest{LotNumber: "L-1", ManufacturedAt: "2026-06-30", ExpiresAt: "2026-01-01", Quantity: 1},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "inventory not found",
+			id:   id.String(),
+			req:  valid,
+			mockFunc: func(ctx context.Context, inventoryID uuid.UUID, lot *models.InventoryLot) error {
+				return gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name: "lot dates differ",
+			id:   id.String(),
+			req:  valid,
+			mockFunc: func(ctx context.Context, inventoryID uuid.UUID, lot *models.InventoryLot) error {
+				return models.ErrLotMismatch
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "db error",
+			id:   id.String(),
+			req:  valid,
+			mockFunc: func(ctx context.Context, inventoryID uuid.UUID, lot *models.InventoryLot) error {
+				return errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name: "success",
+			id:   id.String(),
+			req:  valid,
+			mockFunc: func(ctx context.Context, inventoryID uuid.UUID, lot *models.InventoryLot) error {
+				assert.Equal(t, id, inventoryID)
+				assert.Equal(t, "2026-06-30", lot.ExpiresAt.Format(time.DateOnly))
+				assert.Equal(t, 10, lot.Quantity)
+				return nil
+			},
+			expectedStatus: int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockLotReceiver{ReceiveLotFunc: tt.mockFunc}
+			lot, status, err := receiveLotLogic(mock, tt.id, tt.req, models.MovementMeta{})
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, lot)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, lot)
+			}
+		})
+	}
+}
+
+// GetExpiringLots
+
+type mockExpiringLotFetcher struct {
+	GetExpiringLotsFunc func(ctx context.Context, hubID uuid.UUID, before time.Time) ([]models.ExpiringLotView, error)
+}
+
+func (m *mockExpiringLotFetcher) GetExpiringLots(ctx context.Context, hubID uuid.UUID, before time.Time) ([]models.ExpiringLotView, error) {
+	return m.GetExpiringLotsFunc(ctx, hubID, before)
+}
+
+func TestGetExpiringLotsLogic(t *testing.T) {
+	hubID := uuid.New()
+
+	tests := []struct {
+		name           string
+		hubID          string
+		days           string
+		mockFunc       func(ctx context.Context, hubID uuid.UUID, before time.Time) ([]models.ExpiringLotView, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid hub id",
+			hubID:          "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "negative days",
+			hubID:          hubID.String(),
+			days:           "-1",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "default window",
+			hubID: hubID.String(),
+			mockFunc: func(ctx context.Context, h uuid.UUID, before time.Time) ([]models.ExpiringLotView, error) {
+				assert.WithinDuration(t, time.Now().AddDate(0, 0, 30), before, time.Minute)
+				return []models.ExpiringLotView{{LotNumber: "L-1"}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:  "custom window",
+			hubID: hubID.String(),
+			days:  "7",
+			mockFunc: func(ctx context.Context, h uuid.UUID, before time.Time) ([]models.ExpiringLotView, error) {
+				assert.WithinDuration(t, time.Now().AddDate(0, 0, 7), before, time.Minute)
+				return nil, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:  "db error",
+			hubID: hubID.String(),
+			mockFunc: func(ctx context.Context, h uuid.UUID, before time.Time) ([]models.ExpiringLotView, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockExpiringLotFetcher{GetExpiringLotsFunc: tt.mockFunc}
+			_, status, err := getExpiringLotsLogic(mock, tt.hubID, tt.days)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+)
+
+type MovementHistory struct {
+	Movements []models.InventoryMovement `json:"movements"`
+	Page      int                        `json:"page"`
+	PageSize  int                        `json:"page_size"`
+	Total     int64                      `json:"total"`
+}
+
+// movementMetaFromRequest captures who is changing stock and through which
+// endpoint, for the inventory movements ledger.
+func movementMetaFromRequest(c *gin.Context) models.MovementMeta {
+	return models.MovementMeta{
+		Source:      c.Request.Method + " " + c.FullPath(),
+		Actor:       c.GetHeader("X-User-ID"),
+		ReferenceID: c.GetHeader("X-Reference-ID"),
+	}
+}
+
+// GetInventoryMovements
+
+type InventoryMovementFetcher interface {
+	GetInventoryMovements(ctx context.Context, inventoryID uuid.UUID, filter models.MovementFilter) ([]models.InventoryMovement, int64, error)
+}
+
+func getInventoryMovementsLogic(service InventoryMovementFetcher, idStr, fromStr, toStr, pageStr, pageSizeStr string) (*MovementHistory, int, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid inventory id")
+	}
+
+	from, err := parseOptionalTime(fromStr, "from")
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	to, err := parseOptionalTime(toStr, "to")
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, int(http.StatusBadRequest), errors.New("from must be before to")
+	}
+
+	page, pageSize, err := parsePagination(pageStr, pageSizeStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	filter := models.MovementFilter{From: from, To: to, Page: page, PageSize: pageSize}
+	movements, total, err := service.GetInventoryMovements(context.Background(), id, filter)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch inventory movements")
+	}
+
+	return &MovementHistory{
+		Movements: movements,
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+	}, int(http.StatusOK), nil
+}
+
+// GetInventoryMovements godoc
+// @Summary List the quantity movements of an inventory row, newest first
+// @Tags Inventories
+// @Produce json
+// @Param id path string true "Inventory ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param from query string false "Start of time range (RFC3339, inclusive)"
+// @Param to query string false "End of time range (RFC3339, exclusive)"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 50, max 500)"
+// @Success 200 {object} MovementHistory
+// @Router /inventories/{id}/movements [get]
+func GetInventoryMovements(c *gin.Context) {
+	history, status, err := getInventoryMovementsLogic(
+		models.InventoryModel{},
+		c.Param("id"),
+		c.Query("from"),
+		c.Query("to"),
+		c.Query("page"),
+		c.Query("page_size"),
+	)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, history)
+}
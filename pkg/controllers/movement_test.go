@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// GetInventoryMovements
+
+type mockInventoryMovementFetcher struct {
+	GetInventoryMovementsFunc func(ctx context.Context, inventoryID uuid.UUID, filter models.MovementFilter) ([]models.InventoryMovement, int64, error)
+}
+
+func (m *mockInventoryMovementFetcher) GetInventoryMovements(ctx context.Context, inventoryID uuid.UUID, filter models.MovementFilter) ([]models.InventoryMovement, int64, error) {
+	return m.GetInventoryMovementsFunc(ctx, inventoryID, filter)
+}
+
+func TestGetInventoryMovementsLogic(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name             string
+		idStr            string
+		from             string
+		to               string
+		page             string
+		pageSize         string
+		mockFunc         func(ctx context.Context, inventoryID uuid.UUID, filter models.MovementFilter) ([]models.InventoryMovement, int64, error)
+		expectedStatus   int
+		expectedPageSize int
+		expectErr        bool
+	}{
+		{
+			name:           "invalid inventory id",
+			idStr:          "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid from",
+			idStr:          id.String(),
+			from:           "yesterday",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "from after to",
+			idStr:          id.String(),
+			from:           "2025-03-02T00:00:00Z",
+			to:             "2025-03-01T00:00:00Z",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid page",
+			idStr:          id.String(),
+			page:           "0",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "db error",
+			idStr: id.String(),
+			mockFunc: func(ctx context.Context, inventoryID uuid.UUID, filter models.MovementFilter) ([]models.InventoryMovement, int64, error) {
+				return nil, 0, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:     "success with filters",
+			idStr:    id.String(),
+			from:     "2025-03-01T00:00:00Z",
+			to:       "2025-04-01T00:00:00Z",
+			page:     "2",
+			pageSize: "10",
+			mockFunc: func(ctx context.Context, inventoryID uuid.UUID, filter models.MovementFilter) ([]models.InventoryMovement, int64, error) {
+				assert.Equal(t, id, inventoryID)
+				assert.NotNil(t, filter.From)
+				assert.NotNil(t, filter.To)
+				assert.Equal(t, 2, filter.Page)
+				return []models.InventoryMovement{{InventoryID: inventoryID, Delta: -40}}, 11, nil
+			},
+			expectedStatus:   int(http.StatusOK),
+			expectedPageSize: 10,
+		},
+		{
+			name:     "page size is capped",
+			idStr:    id.String(),
+			pageSize: "100000",
+			mockFunc: func(ctx context.Context, inventoryID uuid.UUID, filter models.MovementFilter) ([]models.InventoryMovement, int64, error) {
+				assert.Nil(t, filter.From)
+				return []models.InventoryMovement{{InventoryID: inventoryID}}, 1, nil
+			},
+			expectedStatus:   int(http.StatusOK),
+			expectedPageSize: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockInventoryMovementFetcher{GetInventoryMovementsFunc: tt.mockFunc}
+			history, status, err := getInventoryMovementsLogic(mock, tt.idStr, tt.from, tt.to, tt.page, tt.pageSize)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, history.Movements, 1)
+				assert.Equal(t, tt.expectedPageSize, history.PageSize)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type CreateProductRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// CreateProduct
+
+type ProductCreator interface {
+	CreateProduct(ctx context.Context, product *models.Product) error
+}
+
+func createProductLogic(service ProductCreator, tenantIDStr string, req CreateProductRequest) (*models.Product, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, int(http.StatusBadRequest), errors.New("name is required")
+	}
+
+	product := &models.Product{TenantID: tenantID, Name: name, Description: req.Description}
+	if err := service.CreateProduct(context.Background(), product); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusBadRequest), errors.New("tenant not found")
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to create product")
+	}
+
+	return product, int(http.StatusCreated), nil
+}
+
+// CreateProduct godoc
+// @Summary Create a product to group SKU variants under
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body CreateProductRequest true "Product"
+// @Success 201 {object} models.Product
+// @Router /products [post]
+func CreateProduct(c *gin.Context) {
+	var req CreateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request body")})
+		return
+	}
+
+	product, status, err := createProductLogic(models.ProductModel{}, c.GetHeader("X-Tenant-ID"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, product)
+}
+
+// GetProducts
+
+type ProductFetcher interface {
+	GetProducts(ctx context.Context, tenantID uuid.UUID) ([]models.Product, error)
+	GetProduct(ctx context.Context, tenantID, id uuid.UUID) (*models.Product, error)
+}
+
+func getProductsLogic(service ProductFetcher, tenantIDStr string) ([]models.Product, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	products, err := service.GetProducts(context.Background(), tenantID)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch products")
+	}
+
+	return products, int(http.StatusOK), nil
+}
+
+// GetProducts godoc
+// @Summary List the tenant's products
+// @Tags Products
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {array} models.Product
+// @Router /products [get]
+func GetProducts(c *gin.Context) {
+	products, status, err := getProductsLogic(models.ProductModel{}, c.GetHeader("X-Tenant-ID"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, products)
+}
+
+// GetProductByID
+
+func getProductByIDLogic(service ProductFetcher, tenantIDStr, idStr string) (*models.Product, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	product, err := service.GetProduct(context.Background(), tenantID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("product not found")
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch product")
+	}
+
+	return product, int(http.StatusOK), nil
+}
+
+// GetProductByID godoc
+// @Summary Get a product
+// @Tags Products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {object} models.Product
+// @Router /products/{id} [get]
+func GetProductByID(c *gin.Context) {
+	product, status, err := getProductByIDLogic(models.ProductModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, product)
+}
+
+// GetProductVariants
+
+type ProductVariantFetcher interface {
+	GetProductVariants(ctx context.Context, tenantID, id uuid.UUID) ([]models.VariantStock, error)
+}
+
+func getProductVariantsLogic(service ProductVariantFetcher, tenantIDStr, idStr string) ([]models.VariantStock, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	variants, err := service.GetProductVariants(context.Background(), tenantID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("product not found")
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch variants")
+	}
+
+	return variants, int(http.StatusOK), nil
+}
+
+// GetProductVariants godoc
+// @Summary List the SKU variants of a product with stock summed across hubs
+// @Tags Products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {array} models.VariantStock
+// @Router /products/{id}/variants [get]
+func GetProductVariants(c *gin.Context) {
+	variants, status, err := getProductVariantsLogic(models.ProductModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, variants)
+}
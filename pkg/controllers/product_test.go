@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockProductService struct {
+	CreateProductFunc      func(ctx context.Context, product *models.Product) error
+	GetProductVariantsFunc func(ctx context.Context, tenantID, id uuid.UUID) ([]models.VariantStock, error)
+}
+
+func (m *mockProductService) CreateProduct(ctx context.Context, product *models.Product) error {
+	return m.CreateProductFunc(ctx, product)
+}
+
+func (m *mockProductService) GetProductVariants(ctx context.Context, tenantID, id uuid.UUID) ([]models.VariantStock, error) {
+	return m.GetProductVariantsFunc(ctx, tenantID, id)
+}
+
+func TestCreateProductLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		req            CreateProductRequest
+		mockFunc       func(ctx context.Context, product *models.Product) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad",
+			req:            CreateProductRequest{Name: "T-shirt"},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "blank name",
+			tenantID:       tenantID,
+			req:            CreateProductRequest{Name: " "},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "tenant not found",
+			tenantID: tenantID,
+			req:      CreateProductRequest{Name: "T-shirt"},
+			mockFunc: func(ctx context.Context, product *models.Product) error {
+				return gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "created",
+			tenantID: tenantID,
+			req:      CreateProductRequest{Name: "T-shirt"},
+			mockFunc: func(ctx context.Context, product *models.Product) error {
+				product.ID = uuid.New()
+				return nil
+			},
+			expectedStatus: int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockProductService{CreateProductFunc: tt.mockFunc}
+			product, status, err := createProductLogic(mock, tt.tenantID, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "T-shirt", product.Name)
+			}
+		})
+	}
+}
+
+func TestGetProductVariantsLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	id := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		idStr          string
+		mockFunc       func(ctx context.Context, tenantID, id uuid.UUID) ([]models.VariantStock, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid id",
+			idStr:          "bad",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "product not found",
+			idStr: id,
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) ([]models.VariantStock, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:  "db error",
+			idStr: id,
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) ([]models.VariantStock, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:  "variants with stock",
+			idStr: id,
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) ([]models.VariantStock, error) {
+				return []models.VariantStock{
+					{SkuCode: "TEE-M", Attributes: models.VariantAttributes{"size": "M"}, Hubs: 2, OnHand: 12, Quantity: 10, Reserved: 2},
+					{SkuCode: "TEE-L", Attributes: models.VariantAttributes{"size": "L"}},
+				}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockProductService{GetProductVariantsFunc: tt.mockFunc}
+			variants, status, err := getProductVariantsLogic(mock, tenantID, tt.idStr)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, variants, 2)
+				assert.Equal(t, "M", variants[0].Attributes["size"])
+			}
+		})
+	}
+}
@@ -0,0 +1,388 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type PurchaseOrderLineRequest struct {
+	SkuID    uuid.UUID `json:"sku_id" binding:"required"`
+	Quantity int       `json:"quantity" binding:"required"`
+	UnitCost *float64  `json:"unit_cost"` // cost per unit, taken on purchase order creation only
+}
+
+type CreatePurchaseOrderRequest struct {
+	HubID      uuid.UUID                  `json:"hub_id" binding:"required"`
+	Reference  string                     `json:"reference"`
+	Supplier   string                     `json:"supplier"`
+	ExpectedAt string                     `json:"expected_at" example:"2025-01-31T00:00:00Z"` // RFC3339, optional
+	Lines      []PurchaseOrderLineRequest `json:"lines" binding:"required"`
+}
+
+type CreateShippingNoticeRequest struct {
+	Reference  string                     `json:"reference"`
+	ExpectedAt string                     `json:"expected_at" example:"2025-01-31T00:00:00Z"` // RFC3339, optional
+	Lines      []PurchaseOrderLineRequest `json:"lines" binding:"required"`
+}
+
+type ReceivePurchaseOrderRequest struct {
+	ShippingNoticeID *uuid.UUID                 `json:"shipping_notice_id"`
+	Lines            []PurchaseOrderLineRequest `json:"lines"`
+}
+
+var purchaseOrderStatuses = map[string]bool{
+	models.PurchaseOrderOpen:              true,
+	models.PurchaseOrderPartiallyReceived: true,
+	models.PurchaseOrderClosed:            true,
+}
+
+// purchaseOrderError maps model errors shared by the purchase order endpoints.
+func purchaseOrderError(err error, fallback string) (int, error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return int(http.StatusNotFound), errors.New("purchase order not found")
+	case errors.Is(err, models.ErrInvalidPurchaseOrder),
+		errors.Is(err, models.ErrPurchaseOrderClosed),
+		errors.Is(err, models.ErrASNReceived),
+		errors.Is(err, models.ErrSerializedSku),
+		errors.Is(err, models.ErrBundleSku):
+		return int(http.StatusBadRequest), err
+	default:
+		return int(http.StatusInternalServerError), errors.New(fallback)
+	}
+}
+
+// purchaseOrderQuantities checks request lines for positive quantities and
+// unique SKUs and returns them keyed by SKU.
+func purchaseOrderQuantities(lines []PurchaseOrderLineRequest) (map[uuid.UUID]int, error) {
+	quantities := make(map[uuid.UUID]int, len(lines))
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return nil, errors.New("quantity must be positive")
+		}
+		if _, dup := quantities[line.SkuID]; dup {
+			return nil, errors.New("duplicate sku_id in lines")
+		}
+		quantities[line.SkuID] = line.Quantity
+	}
+	return quantities, nil
+}
+
+// CreatePurchaseOrder
+
+type PurchaseOrderCreator interface {
+	CreatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error
+}
+
+func createPurchaseOrderLogic(service PurchaseOrderCreator, tenantIDStr string, req CreatePurchaseOrderRequest) (*models.PurchaseOrder, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	if len(req.Lines) == 0 {
+		return nil, int(http.StatusBadRequest), errors.New("at least one line is required")
+	}
+	if _, err := purchaseOrderQuantities(req.Lines); err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	expectedAt, err := parseOptionalTime(req.ExpectedAt, "expected_at")
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	lines := make([]models.PurchaseOrderLine, len(req.Lines))
+	for i, line := range req.Lines {
+		if line.UnitCost != nil && *line.UnitCost < 0 {
+			return nil, int(http.StatusBadRequest), models.ErrInvalidUnitCost
+		}
+		lines[i] = models.PurchaseOrderLine{SkuID: line.SkuID, ExpectedQuantity: line.Quantity, UnitCost: line.UnitCost}
+	}
+
+	order := &models.PurchaseOrder{
+		TenantID:   tenantID,
+		HubID:      req.HubID,
+		Reference:  req.Reference,
+		Supplier:   req.Supplier,
+		ExpectedAt: expectedAt,
+		Lines:      lines,
+	}
+
+	if err := service.CreatePurchaseOrder(context.Background(), order); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusBadRequest), errors.New("hub not found")
+		}
+		if errors.Is(err, models.ErrInvalidPurchaseOrder) {
+			return nil, int(http.StatusBadRequest), errors.New("hub and skus must belong to the tenant")
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to create purchase order")
+	}
+
+	return order, int(http.StatusCreated), nil
+}
+
+// CreatePurchaseOrder godoc
+// @Summary Create a purchase order of expected SKUs and quantities for a hub
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body CreatePurchaseOrderRequest true "Purchase order"
+// @Success 201 {object} models.PurchaseOrder
+// @Router /purchase-orders [post]
+func CreatePurchaseOrder(c *gin.Context) {
+	var req CreatePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	order, status, err := createPurchaseOrderLogic(models.PurchaseOrderModel{}, c.GetHeader("X-Tenant-ID"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, order)
+}
+
+// GetPurchaseOrders
+
+type PurchaseOrderFetcher interface {
+	GetPurchaseOrders(ctx context.Context, tenantID uuid.UUID, status string) ([]models.PurchaseOrder, error)
+	GetPurchaseOrder(ctx context.Context, tenantID, id uuid.UUID) (*models.PurchaseOrder, error)
+}
+
+func getPurchaseOrdersLogic(service PurchaseOrderFetcher, tenantIDStr, status string) ([]models.PurchaseOrder, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	if status != "" && !purchaseOrderStatuses[status] {
+		return nil, int(http.StatusBadRequest), errors.New("status must be open, partially_received or closed")
+	}
+
+	orders, err := service.GetPurchaseOrders(context.Background(), tenantID, status)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch purchase orders")
+	}
+
+	return orders, int(http.StatusOK), nil
+}
+
+// GetPurchaseOrders godoc
+// @Summary List the tenant's purchase orders, newest first
+// @Tags Purchase Orders
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param status query string false "open, partially_received or closed"
+// @Success 200 {array} models.PurchaseOrder
+// @Router /purchase-orders [get]
+func GetPurchaseOrders(c *gin.Context) {
+	orders, status, err := getPurchaseOrdersLogic(models.PurchaseOrderModel{}, c.GetHeader("X-Tenant-ID"), c.Query("status"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, orders)
+}
+
+// GetPurchaseOrderByID
+
+func getPurchaseOrderByIDLogic(service PurchaseOrderFetcher, tenantIDStr, idStr string) (*models.PurchaseOrder, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	order, err := service.GetPurchaseOrder(context.Background(), tenantID, id)
+	if err != nil {
+		status, err := purchaseOrderError(err, "failed to fetch purchase order")
+		return nil, status, err
+	}
+
+	return order, int(http.StatusOK), nil
+}
+
+// GetPurchaseOrderByID godoc
+// @Summary Get a purchase order with its lines and shipping notices
+// @Tags Purchase Orders
+// @Produce json
+// @Param id path string true "Purchase order ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Router /purchase-orders/{id} [get]
+func GetPurchaseOrderByID(c *gin.Context) {
+	order, status, err := getPurchaseOrderByIDLogic(models.PurchaseOrderModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, order)
+}
+
+// CreateShippingNotice
+
+type ShippingNoticeCreator interface {
+	CreateShippingNotice(ctx context.Context, tenantID, orderID uuid.UUID, asn *models.AdvanceShippingNotice) error
+}
+
+func createShippingNoticeLogic(service ShippingNoticeCreator, tenantIDStr, idStr string, req CreateShippingNoticeRequest) (*models.AdvanceShippingNotice, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	if len(req.Lines) == 0 {
+		return nil, int(http.StatusBadRequest), errors.New("at least one line is required")
+	}
+	if _, err := purchaseOrderQuantities(req.Lines); err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	expectedAt, err := parseOptionalTime(req.ExpectedAt, "expected_at")
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	lines := make([]models.AdvanceShippingNoticeLine, len(req.Lines))
+	for i, line := range req.Lines {
+		lines[i] = models.AdvanceShippingNoticeLine{SkuID: line.SkuID, Quantity: line.Quantity}
+	}
+	asn := &models.AdvanceShippingNotice{Reference: req.Reference, ExpectedAt: expectedAt, Lines: lines}
+
+	if err := service.CreateShippingNotice(context.Background(), tenantID, id, asn); err != nil {
+		status, err := purchaseOrderError(err, "failed to create shipping notice")
+		return nil, status, err
+	}
+
+	return asn, int(http.StatusCreated), nil
+}
+
+// CreateShippingNotice godoc
+// @Summary Record an advance shipping notice against an open purchase order
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Purchase order ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body CreateShippingNoticeRequest true "Shipped quantities per SKU"
+// @Success 201 {object} models.AdvanceShippingNotice
+// @Router /purchase-orders/{id}/shipping-notices [post]
+func CreateShippingNotice(c *gin.Context) {
+	var req CreateShippingNoticeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	asn, status, err := createShippingNoticeLogic(models.PurchaseOrderModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, asn)
+}
+
+// ReceivePurchaseOrder
+
+type PurchaseOrderReceiver interface {
+	ReceivePurchaseOrder(ctx context.Context, tenantID, id uuid.UUID, asnID *uuid.UUID, received map[uuid.UUID]int) (*models.PurchaseOrder, error)
+	ClosePurchaseOrder(ctx context.Context, tenantID, id uuid.UUID) (*models.PurchaseOrder, error)
+}
+
+func receivePurchaseOrderLogic(service PurchaseOrderReceiver, tenantIDStr, idStr string, req ReceivePurchaseOrderRequest, meta models.MovementMeta) (*models.PurchaseOrder, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	if req.ShippingNoticeID == nil && len(req.Lines) == 0 {
+		return nil, int(http.StatusBadRequest), errors.New("lines are required when not receiving a shipping notice")
+	}
+	received, err := purchaseOrderQuantities(req.Lines)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	order, err := service.ReceivePurchaseOrder(models.WithMovementMeta(context.Background(), meta), tenantID, id, req.ShippingNoticeID, received)
+	if err != nil {
+		status, err := purchaseOrderError(err, "failed to receive purchase order")
+		return nil, status, err
+	}
+
+	return order, int(http.StatusOK), nil
+}
+
+// ReceivePurchaseOrder godoc
+// @Summary Receive stock against a purchase order, optionally for one shipping notice; the order closes once every line is received in full
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Purchase order ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body ReceivePurchaseOrderRequest true "Received quantities per SKU; omit with a shipping notice to receive it as shipped"
+// @Success 200 {object} models.PurchaseOrder
+// @Router /purchase-orders/{id}/receive [post]
+func ReceivePurchaseOrder(c *gin.Context) {
+	var req ReceivePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	order, status, err := receivePurchaseOrderLogic(models.PurchaseOrderModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"), req, movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, order)
+}
+
+// ClosePurchaseOrder
+
+func closePurchaseOrderLogic(service PurchaseOrderReceiver, tenantIDStr, idStr string) (*models.PurchaseOrder, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	order, err := service.ClosePurchaseOrder(context.Background(), tenantID, id)
+	if err != nil {
+		status, err := purchaseOrderError(err, "failed to close purchase order")
+		return nil, status, err
+	}
+
+	return order, int(http.StatusOK), nil
+}
+
+// ClosePurchaseOrder godoc
+// @Summary Close a purchase order that will not be received in full; outstanding quantities remain as under-receipts
+// @Tags Purchase Orders
+// @Produce json
+// @Param id path string true "Purchase order ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Router /purchase-orders/{id}/close [post]
+func ClosePurchaseOrder(c *gin.Context) {
+	order, status, err := closePurchaseOrderLogic(models.PurchaseOrderModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, order)
+}
@@ -0,0 +1,340 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockPurchaseOrderService struct {
+	CreatePurchaseOrderFunc  func(ctx context.Context, order *models.PurchaseOrder) error
+	CreateShippingNoticeFunc func(ctx context.Context, tenantID, orderID uuid.UUID, asn *models.AdvanceShippingNotice) error
+	ReceivePurchaseOrderFunc func(ctx context.Context, tenantID, id uuid.UUID, asnID *uuid.UUID, received map[uuid.UUID]int) (*models.PurchaseOrder, error)
+	ClosePurchaseOrderFunc   func(ctx context.Context, tenantID, id uuid.UUID) (*models.PurchaseOrder, error)
+}
+
+func (m *mockPurchaseOrderService) CreatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	return m.CreatePurchaseOrderFunc(ctx, order)
+}
+
+func (m *mockPurchaseOrderService) CreateShippingNotice(ctx context.Context, tenantID, orderID uuid.UUID, asn *models.AdvanceShippingNotice) error {
+	return m.CreateShippingNoticeFunc(ctx, tenantID, orderID, asn)
+}
+
+func (m *mockPurchaseOrderService) ReceivePurchaseOrder(ctx context.Context, tenantID, id uuid.UUID, asnID *uuid.UUID, received map[uuid.UUID]int) (*models.PurchaseOrder, error) {
+	return m.ReceivePurchaseOrderFunc(ctx, tenantID, id, asnID, received)
+}
+
+func (m *mockPurchaseOrderService) ClosePurchaseOrder(ctx context.Context, tenantID, id uuid.UUID) (*models.PurchaseOrder, error) {
+	return m.ClosePurchaseOrderFunc(ctx, tenantID, id)
+}
+
+func TestCreatePurchaseOrderLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	skuID := uuid.New()
+	lines := []PurchaseOrderLineRequest{{SkuID: skuID, Quantity: 10}}
+
+	tests := []struct {
+		name           string
+		req            CreatePurchaseOrderRequest
+		mockFunc       func(ctx context.Context, order *models.PurchaseOrder) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "no lines",
+			req:            CreatePurchaseOrderRequest{HubID: uuid.New()},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "duplicate sku",
+			req:            CreatePurchaseOrderRequest{HubID: uuid.New(), Lines: append(lines, lines[0])},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "non-positive quantity",
+			req:            CreatePurchaseOrderRequest{HubID: uuid.New(), Lines: []PurchaseOrderLineRequest{{SkuID: skuID, Quantity: -1}}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "negative unit cost",
+			req:            CreatePurchaseOrderRequest{HubID: uuid.New(), Lines: []PurchaseOrderLineRequest{{SkuID: skuID, Quantity: 10, UnitCost: floatPtr(-1.5)}}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid expected_at",
+			req:            CreatePurchaseOrderRequest{HubID: uuid.New(), Lines: lines, ExpectedAt: "tomorrow"},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "sku of another tenant",
+			req:  CreatePurchaseOrderRequest{HubID: uuid.New(), Lines: lines},
+			mockFunc: func(ctx context.Context, order *models.PurchaseOrder) error {
+				return models.ErrInvalidPurchaseOrder
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "hub not found",
+			req:  CreatePurchaseOrderRequest{HubID: uuid.New(), Lines: lines},
+			mockFunc: func(ctx context.Context, order *models.PurchaseOrder) error {
+				return gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "created",
+			req:  CreatePurchaseOrderRequest{HubID: uuid.New(), Lines: lines, Reference: "PO-1", ExpectedAt: "2025-01-31T00:00:00Z"},
+			mockFunc: func(ctx context.Context, order *models.PurchaseOrder) error {
+				if order.ExpectedAt == nil || order.Lines[0].ExpectedQuantity != 10 {
+					return errors.New("request not mapped")
+				}
+				order.Status = models.PurchaseOrderOpen
+				return nil
+			},
+			expectedStatus: int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockPurchaseOrderService{CreatePurchaseOrderFunc: tt.mockFunc}
+			order, status, err := createPurchaseOrderLogic(mock, tenantID, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.PurchaseOrderOpen, order.Status)
+			}
+		})
+	}
+}
+
+func TestCreateShippingNoticeLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	id := uuid.New().String()
+	lines := []PurchaseOrderLineRequest{{SkuID: uuid.New(), Quantity: 4}}
+
+	tests := []struct {
+		name           string
+		req            CreateShippingNoticeRequest
+		mockFunc       func(ctx context.Context, tenantID, orderID uuid.UUID, asn *models.AdvanceShippingNotice) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "no lines",
+			req:            CreateShippingNoticeRequest{},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "closed order",
+			req:  CreateShippingNoticeRequest{Lines: lines},
+			mockFunc: func(ctx context.Context, tenantID, orderID uuid.UUID, asn *models.AdvanceShippingNotice) error {
+				return models.ErrPurchaseOrderClosed
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "order not found",
+			req:  CreateShippingNoticeRequest{Lines: lines},
+			mockFunc: func(ctx context.Context, tenantID, orderID uuid.UUID, asn *models.AdvanceShippingNotice) error {
+				return gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name: "created",
+			req:  CreateShippingNoticeRequest{Lines: lines},
+			mockFunc: func(ctx context.Context, tenantID, orderID uuid.UUID, asn *models.AdvanceShippingNotice) error {
+				asn.Status = models.ASNInTransit
+				return nil
+			},
+			expectedStatus: int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockPurchaseOrderService{CreateShippingNoticeFunc: tt.mockFunc}
+			asn, status, err := createShippingNoticeLogic(mock, tenantID, id, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.ASNInTransit, asn.Status)
+			}
+		})
+	}
+}
+
+func TestReceivePurchaseOrderLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	id := uuid.New().String()
+	skuID := uuid.New()
+	asnID := uuid.New()
+
+	tests := []struct {
+		name           string
+		idStr          string
+		req            ReceivePurchaseOrderRequest
+		mockFunc       func(ctx context.Context, tenantID, id uuid.UUID, asnID *uuid.UUID, received map[uuid.UUID]int) (*models.PurchaseOrder, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid id",
+			idStr:          "bad",
+			req:            ReceivePurchaseOrderRequest{Lines: []PurchaseOrderLineRequest{{SkuID: skuID, Quantity: 1}}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "nothing to receive",
+			idStr:          id,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "zero quantity",
+			idStr:          id,
+			req:            ReceivePurchaseOrderRequest{Lines: []PurchaseOrderLineRequest{{SkuID: skuID}}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "sku not on the order",
+			idStr: id,
+			req:   ReceivePurchaseOrderRequest{Lines: []PurchaseOrderLineRequest{{SkuID: skuID, Quantity: 1}}},
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, asnID *uuid.UUID, received map[uuid.UUID]int) (*models.PurchaseOrder, error) {
+				return nil, fmt.Errorf("%w: sku %s is not on the purchase order", models.ErrInvalidPurchaseOrder, skuID)
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "notice already received",
+			idStr: id,
+			req:   ReceivePurchaseOrderRequest{ShippingNoticeID: &asnID},
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, asnID *uuid.UUID, received map[uuid.UUID]int) (*models.PurchaseOrder, error) {
+				return nil, models.ErrASNReceived
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "db error",
+			idStr: id,
+			req:   ReceivePurchaseOrderRequest{Lines: []PurchaseOrderLineRequest{{SkuID: skuID, Quantity: 1}}},
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, asnID *uuid.UUID, received map[uuid.UUID]int) (*models.PurchaseOrder, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:  "shipping notice as shipped",
+			idStr: id,
+			req:   ReceivePurchaseOrderRequest{ShippingNoticeID: &asnID},
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, asnID *uuid.UUID, received map[uuid.UUID]int) (*models.PurchaseOrder, error) {
+				if asnID == nil || len(received) != 0 {
+					return nil, errors.New("unexpected arguments")
+				}
+				return &models.PurchaseOrder{Status: models.PurchaseOrderPartiallyReceived}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:  "over-receipt closes the order",
+			idStr: id,
+			req:   ReceivePurchaseOrderRequest{Lines: []PurchaseOrderLineRequest{{SkuID: skuID, Quantity: 12}}},
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, asnID *uuid.UUID, received map[uuid.UUID]int) (*models.PurchaseOrder, error) {
+				return &models.PurchaseOrder{
+					Status:         models.PurchaseOrderClosed,
+					HasDiscrepancy: true,
+					Lines:          []models.PurchaseOrderLine{{SkuID: skuID, ExpectedQuantity: 10, ReceivedQuantity: received[skuID], Variance: received[skuID] - 10}},
+				}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockPurchaseOrderService{ReceivePurchaseOrderFunc: tt.mockFunc}
+			order, status, err := receivePurchaseOrderLogic(mock, tenantID, tt.idStr, tt.req, models.MovementMeta{})
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, order)
+			}
+		})
+	}
+}
+
+func TestClosePurchaseOrderLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	id := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		mockFunc       func(ctx context.Context, tenantID, id uuid.UUID) (*models.PurchaseOrder, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name: "already closed",
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.PurchaseOrder, error) {
+				return nil, models.ErrPurchaseOrderClosed
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "closed short",
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.PurchaseOrder, error) {
+				return &models.PurchaseOrder{Status: models.PurchaseOrderClosed, HasDiscrepancy: true}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockPurchaseOrderService{ClosePurchaseOrderFunc: tt.mockFunc}
+			order, status, err := closePurchaseOrderLogic(mock, tenantID, id)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.PurchaseOrderClosed, order.Status)
+			}
+		})
+	}
+}
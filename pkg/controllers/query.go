@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/constants"
+)
+
+// parsePagination reads 1-based page and page_size query values, applying
+// defaults and capping the page size.
+func parsePagination(pageStr, pageSizeStr string) (int, int, error) {
+	page, pageSize := 1, constants.DefaultPageSize
+
+	if pageStr != "" {
+		p, err := strconv.Atoi(pageStr)
+		if err != nil || p < 1 {
+			return 0, 0, errors.New("invalid page")
+		}
+		page = p
+	}
+
+	if pageSizeStr != "" {
+		ps, err := strconv.Atoi(pageSizeStr)
+		if err != nil || ps < 1 {
+			return 0, 0, errors.New("invalid page_size")
+		}
+		pageSize = min(ps, constants.MaxPageSize)
+	}
+
+	return page, pageSize, nil
+}
+
+// parseOptionalTime parses an RFC3339 timestamp, returning nil when empty.
+func parseOptionalTime(value, field string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New("invalid " + field + ", expected RFC3339")
+	}
+	return &t, nil
+}
+
+// parseOptionalDate parses a YYYY-MM-DD date, returning nil when empty.
+func parseOptionalDate(value, field string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, errors.New("invalid " + field + ", expected YYYY-MM-DD")
+	}
+	return &t, nil
+}
+
+// parseAsOf parses a YYYY-MM-DD as_of date into the end of that day (UTC),
+// the instant a report as of the date describes. It returns nil when empty.
+func parseAsOf(value string) (*time.Time, error) {
+	date, err := parseOptionalDate(value, "as_of")
+	if err != nil || date == nil {
+		return nil, err
+	}
+
+	end := date.AddDate(0, 0, 1)
+	return &end, nil
+}
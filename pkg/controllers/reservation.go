@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/configs"
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type ReserveInventoryRequest struct {
+	OrderReference string    `json:"order_reference" binding:"required"`
+	SKUID          uuid.UUID `json:"sku_id" binding:"required"`
+	HubID          uuid.UUID `json:"hub_id" binding:"required"`
+	Quantity       int       `json:"quantity" binding:"required"`
+}
+
+// ReserveInventory
+
+type InventoryReserver interface {
+	ReserveInventory(ctx context.Context, res *models.Reservation, ttl time.Duration) (bool, error)
+}
+
+func reserveInventoryLogic(service InventoryReserver, tenantIDStr string, req ReserveInventoryRequest, ttl time.Duration) (*models.Reservation, bool, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, false, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+	if req.Quantity <= 0 {
+		return nil, false, int(http.StatusBadRequest), errors.New("quantity must be positive")
+	}
+
+	res := &models.Reservation{
+		TenantID:       tenantID,
+		OrderReference: req.OrderReference,
+		SkuID:          req.SKUID,
+		HubID:          req.HubID,
+		Quantity:       req.Quantity,
+	}
+
+	reserved, err := service.ReserveInventory(context.Background(), res, ttl)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, int(http.StatusNotFound), errors.New("inventory not found")
+		}
+		if errors.Is(err, models.ErrReservationExists) {
+			return nil, false, int(http.StatusBadRequest), err
+		}
+		return nil, false, int(http.StatusInternalServerError), errors.New("failed to reserve inventory")
+	}
+
+	if !reserved {
+		return nil, false, int(http.StatusOK), nil // not enough stock, but valid
+	}
+
+	return res, true, int(http.StatusCreated), nil
+}
+
+// ReserveInventory godoc
+// @Summary Reserve stock for an order line until it is committed, released or expires
+// @Tags Reservations
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body ReserveInventoryRequest true "Reservation payload"
+// @Success 201 {object} models.Reservation
+// @Router /inventory/reservations [post]
+func ReserveInventory(c *gin.Context) {
+	var req ReserveInventoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	res, reserved, status, err := reserveInventoryLogic(models.ReservationModel{}, c.GetHeader("X-Tenant-ID"), req, configs.GetReservationTTL(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		i18n.Translate(c, "reserved"):    reserved,
+		i18n.Translate(c, "reservation"): res,
+	})
+}
+
+// CommitReservation
+
+type ReservationCommitter interface {
+	CommitReservations(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error)
+}
+
+func commitReservationLogic(service ReservationCommitter, tenantIDStr, orderReference string, meta models.MovementMeta) ([]models.Reservation, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+	if orderReference == "" {
+		return nil, int(http.StatusBadRequest), errors.New("missing order_reference")
+	}
+
+	reservations, err := service.CommitReservations(models.WithMovementMeta(context.Background(), meta), tenantID, orderReference)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("no active reservations for order")
+		}
+		if errors.Is(err, models.ErrInsufficientStock) || errors.Is(err, models.ErrSerializedSku) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to commit reservations")
+	}
+
+	return reservations, int(http.StatusOK), nil
+}
+
+// CommitReservation godoc
+// @Summary Commit an order's reservations into real stock decrements
+// @Tags Reservations
+// @Produce json
+// @Param order_reference path string true "Order reference"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {array} models.Reservation
+// @Router /inventory/reservations/{order_reference}/commit [post]
+func CommitReservation(c *gin.Context) {
+	reservations, status, err := commitReservationLogic(models.ReservationModel{}, c.GetHeader("X-Tenant-ID"), c.Param("order_reference"), movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, reservations)
+}
+
+// ReleaseReservation
+
+type ReservationReleaser interface {
+	ReleaseReservations(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error)
+}
+
+func releaseReservationLogic(service ReservationReleaser, tenantIDStr, orderReference string) ([]models.Reservation, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+	if orderReference == "" {
+		return nil, int(http.StatusBadRequest), errors.New("missing order_reference")
+	}
+
+	reservations, err := service.ReleaseReservations(context.Background(), tenantID, orderReference)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("no active reservations for order")
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to release reservations")
+	}
+
+	return reservations, int(http.StatusOK), nil
+}
+
+// ReleaseReservation godoc
+// @Summary Release an order's reservations back to available stock
+// @Tags Reservations
+// @Produce json
+// @Param order_reference path string true "Order reference"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {array} models.Reservation
+// @Router /inventory/reservations/{order_reference}/release [post]
+func ReleaseReservation(c *gin.Context) {
+	reservations, status, err := releaseReservationLogic(models.ReservationModel{}, c.GetHeader("X-Tenant-ID"), c.Param("order_reference"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, reservations)
+}
@@ -0,0 +1,303 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// ReserveInventory
+
+type mockInventoryReserver struct {
+	ReserveInventoryFunc func(ctx context.Context, res *models.Reservation, ttl time.Duration) (bool, error)
+}
+
+func (m *mockInventoryReserver) ReserveInventory(ctx context.Context, res *models.Reservation, ttl time.Duration) (bool, error) {
+	return m.ReserveInventoryFunc(ctx, res, ttl)
+}
+
+func TestReserveInventoryLogic(t *testing.T) {
+	tenantID := uuid.New()
+	req := ReserveInventoryRequest{
+		OrderReference: "ORD-1",
+		SKUID:          uuid.New(),
+		HubID:          uuid.New(),
+		Quantity:       4,
+	}
+
+	tests := []struct {
+		name             string
+		tenantID         string
+		req              ReserveInventoryRequest
+		mockFunc         func(ctx context.Context, res *models.Reservation, ttl time.Duration) (bool, error)
+		expectedReserved bool
+		expectedStatus   int
+		expectErr        bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad",
+			req:            req,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "non-positive quantity",
+			tenantID:       tenantID.String(),
+			req:            ReserveInventoryRequest{OrderReference: "ORD-1", Quantity: -1},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "already reserved",
+			tenantID: tenantID.String(),
+			req:      req,
+			mockFunc: func(ctx context.Context, res *models.Reservation, ttl time.Duration) (bool, error) {
+				return false, models.ErrReservationExists
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "other tenant's inventory",
+			tenantID: tenantID.String(),
+			req:      req,
+			mockFunc: func(ctx context.Context, res *models.Reservation, ttl time.Duration) (bool, error) {
+				return false, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:     "db error",
+			tenantID: tenantID.String(),
+			req:      req,
+			mockFunc: func(ctx context.Context, res *models.Reservation, ttl time.Duration) (bool, error) {
+				return false, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:     "insufficient stock",
+			tenantID: tenantID.String(),
+			req:      req,
+			mockFunc: func(ctx context.Context, res *models.Reservation, ttl time.Duration) (bool, error) {
+				return false, nil
+			},
+			expectedReserved: false,
+			expectedStatus:   int(http.StatusOK),
+		},
+		{
+			name:     "success",
+			tenantID: tenantID.String(),
+			req:      req,
+			mockFunc: func(ctx context.Context, res *models.Reservation, ttl time.Duration) (bool, error) {
+				assert.Equal(t, tenantID, res.TenantID)
+				assert.Equal(t, "ORD-1", res.OrderReference)
+				assert.Equal(t, 4, res.Quantity)
+				assert.Equal(t, 10*time.Minute, ttl)
+				return true, nil
+			},
+			expectedReserved: true,
+			expectedStatus:   int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockInventoryReserver{ReserveInventoryFunc: tt.mockFunc}
+			res, reserved, status, err := reserveInventoryLogic(mock, tt.tenantID, tt.req, 10*time.Minute)
+
+			assert.Equal(t, tt.expectedReserved, reserved)
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedReserved, res != nil)
+			}
+		})
+	}
+}
+
+// CommitReservation
+
+type mockReservationCommitter struct {
+	CommitReservationsFunc func(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error)
+}
+
+func (m *mockReservationCommitter) CommitReservations(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error) {
+	return m.CommitReservationsFunc(ctx, tenantID, orderReference)
+}
+
+func TestCommitReservationLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		orderRef       string
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad",
+			orderRef:       "ORD-1",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "missing order reference",
+			tenantID:       tenantID,
+			orderRef:       "",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "no active reservations",
+			tenantID: tenantID,
+			orderRef: "ORD-1",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:     "stock vanished",
+			tenantID: tenantID,
+			orderRef: "ORD-1",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error) {
+				return nil, models.ErrInsufficientStock
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "db error",
+			tenantID: tenantID,
+			orderRef: "ORD-1",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:     "success",
+			tenantID: tenantID,
+			orderRef: "ORD-1",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error) {
+				return []models.Reservation{{OrderReference: orderReference, Status: models.ReservationCommitted}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockReservationCommitter{CommitReservationsFunc: tt.mockFunc}
+			result, status, err := commitReservationLogic(mock, tt.tenantID, tt.orderRef, models.MovementMeta{})
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result, 1)
+			}
+		})
+	}
+}
+
+// ReleaseReservation
+
+type mockReservationReleaser struct {
+	ReleaseReservationsFunc func(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error)
+}
+
+func (m *mockReservationReleaser) ReleaseReservations(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error) {
+	return m.ReleaseReservationsFunc(ctx, tenantID, orderReference)
+}
+
+func TestReleaseReservationLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		orderRef       string
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad",
+			orderRef:       "ORD-1",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "missing order reference",
+			tenantID:       tenantID,
+			orderRef:       "",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "no active reservations",
+			tenantID: tenantID,
+			orderRef: "ORD-1",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:     "db error",
+			tenantID: tenantID,
+			orderRef: "ORD-1",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:     "success",
+			tenantID: tenantID,
+			orderRef: "ORD-1",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]models.Reservation, error) {
+				return []models.Reservation{{OrderReference: orderReference, Status: models.ReservationReleased}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockReservationReleaser{ReleaseReservationsFunc: tt.mockFunc}
+			result, status, err := releaseReservationLogic(mock, tt.tenantID, tt.orderRef)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result, 1)
+			}
+		})
+	}
+}
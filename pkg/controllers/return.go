@@ -0,0 +1,352 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type ReturnLineRequest struct {
+	SkuID    uuid.UUID `json:"sku_id" binding:"required"`
+	Quantity int       `json:"quantity" binding:"required"`
+}
+
+type CreateReturnRequest struct {
+	HubID          uuid.UUID           `json:"hub_id" binding:"required"`
+	OrderReference string              `json:"order_reference" binding:"required"`
+	Reason         string              `json:"reason"`
+	Lines          []ReturnLineRequest `json:"lines" binding:"required"`
+}
+
+type GradedLineRequest struct {
+	SkuID uuid.UUID `json:"sku_id" binding:"required"`
+	models.ReturnGrading
+}
+
+type ReceiveReturnRequest struct {
+	Lines []GradedLineRequest `json:"lines"`
+}
+
+type ReturnLedger struct {
+	Entries  []models.ReturnLedgerEntry `json:"entries"`
+	Page     int                        `json:"page"`
+	PageSize int                        `json:"page_size"`
+	Total    int64                      `json:"total"`
+}
+
+var returnStatuses = map[string]bool{
+	models.ReturnOpen:     true,
+	models.ReturnReceived: true,
+}
+
+// returnError maps model errors shared by the return endpoints.
+func returnError(err error, fallback string) (int, error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return int(http.StatusNotFound), errors.New("return not found")
+	case errors.Is(err, models.ErrInvalidReturn),
+		errors.Is(err, models.ErrReturnClosed),
+		errors.Is(err, models.ErrSerializedSku),
+		errors.Is(err, models.ErrBundleSku):
+		return int(http.StatusBadRequest), err
+	default:
+		return int(http.StatusInternalServerError), errors.New(fallback)
+	}
+}
+
+// CreateReturn
+
+type ReturnCreator interface {
+	CreateReturn(ctx context.Context, rma *models.ReturnAuthorization) error
+}
+
+func createReturnLogic(service ReturnCreator, tenantIDStr string, req CreateReturnRequest) (*models.ReturnAuthorization, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	if len(req.Lines) == 0 {
+		return nil, int(http.StatusBadRequest), errors.New("at least one line is required")
+	}
+
+	lines := make([]models.ReturnAuthorizationLine, 0, len(req.Lines))
+	seen := make(map[uuid.UUID]bool, len(req.Lines))
+	for _, line := range req.Lines {
+		if line.Quantity <= 0 {
+			return nil, int(http.StatusBadRequest), errors.New("quantity must be positive")
+		}
+		if seen[line.SkuID] {
+			return nil, int(http.StatusBadRequest), errors.New("duplicate sku_id in lines")
+		}
+		seen[line.SkuID] = true
+		lines = append(lines, models.ReturnAuthorizationLine{SkuID: line.SkuID, Quantity: line.Quantity})
+	}
+
+	rma := &models.ReturnAuthorization{
+		TenantID:       tenantID,
+		HubID:          req.HubID,
+		OrderReference: req.OrderReference,
+		Reason:         req.Reason,
+		Lines:          lines,
+	}
+
+	if err := service.CreateReturn(context.Background(), rma); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusBadRequest), errors.New("hub not found")
+		}
+		if errors.Is(err, models.ErrInvalidReturn) {
+			return nil, int(http.StatusBadRequest), errors.New("hub and skus must belong to the tenant")
+		}
+		status, err := returnError(err, "failed to create return")
+		return nil, status, err
+	}
+
+	return rma, int(http.StatusCreated), nil
+}
+
+// CreateReturn godoc
+// @Summary Authorize a customer return of an order's SKUs to a hub
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body CreateReturnRequest true "Return authorization"
+// @Success 201 {object} models.ReturnAuthorization
+// @Router /returns [post]
+func CreateReturn(c *gin.Context) {
+	var req CreateReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	rma, status, err := createReturnLogic(models.ReturnModel{}, c.GetHeader("X-Tenant-ID"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, rma)
+}
+
+// GetReturns
+
+type ReturnFetcher interface {
+	GetReturns(ctx context.Context, tenantID uuid.UUID, orderReference, status string) ([]models.ReturnAuthorization, error)
+	GetReturn(ctx context.Context, tenantID, id uuid.UUID) (*models.ReturnAuthorization, error)
+}
+
+func getReturnsLogic(service ReturnFetcher, tenantIDStr, orderReference, status string) ([]models.ReturnAuthorization, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	if status != "" && !returnStatuses[status] {
+		return nil, int(http.StatusBadRequest), errors.New("status must be open or received")
+	}
+
+	returns, err := service.GetReturns(context.Background(), tenantID, orderReference, status)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch returns")
+	}
+
+	return returns, int(http.StatusOK), nil
+}
+
+// GetReturns godoc
+// @Summary List the tenant's returns, newest first
+// @Tags Returns
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param order_reference query string false "Only returns of this order"
+// @Param status query string false "open or received"
+// @Success 200 {array} models.ReturnAuthorization
+// @Router /returns [get]
+func GetReturns(c *gin.Context) {
+	returns, status, err := getReturnsLogic(models.ReturnModel{}, c.GetHeader("X-Tenant-ID"), c.Query("order_reference"), c.Query("status"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, returns)
+}
+
+// GetReturnByID
+
+func getReturnByIDLogic(service ReturnFetcher, tenantIDStr, idStr string) (*models.ReturnAuthorization, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	rma, err := service.GetReturn(context.Background(), tenantID, id)
+	if err != nil {
+		status, err := returnError(err, "failed to fetch return")
+		return nil, status, err
+	}
+
+	return rma, int(http.StatusOK), nil
+}
+
+// GetReturnByID godoc
+// @Summary Get a return with its lines and grading
+// @Tags Returns
+// @Produce json
+// @Param id path string true "Return ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {object} models.ReturnAuthorization
+// @Router /returns/{id} [get]
+func GetReturnByID(c *gin.Context) {
+	rma, status, err := getReturnByIDLogic(models.ReturnModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, rma)
+}
+
+// ReceiveReturn
+
+type ReturnReceiver interface {
+	ReceiveReturn(ctx context.Context, tenantID, id uuid.UUID, grades map[uuid.UUID]models.ReturnGrading) (*models.ReturnAuthorization, error)
+}
+
+func receiveReturnLogic(service ReturnReceiver, tenantIDStr, idStr string, req ReceiveReturnRequest, meta models.MovementMeta) (*models.ReturnAuthorization, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	grades := make(map[uuid.UUID]models.ReturnGrading, len(req.Lines))
+	for _, line := range req.Lines {
+		if line.Sellable < 0 || line.Damaged < 0 || line.Disposal < 0 {
+			return nil, int(http.StatusBadRequest), errors.New("graded quantities must not be negative")
+		}
+		if _, dup := grades[line.SkuID]; dup {
+			return nil, int(http.StatusBadRequest), errors.New("duplicate sku_id in lines")
+		}
+		grades[line.SkuID] = line.ReturnGrading
+	}
+
+	rma, err := service.ReceiveReturn(models.WithMovementMeta(context.Background(), meta), tenantID, id, grades)
+	if err != nil {
+		status, err := returnError(err, "failed to receive return")
+		return nil, status, err
+	}
+
+	return rma, int(http.StatusOK), nil
+}
+
+// ReceiveReturn godoc
+// @Summary Receive and grade a return: sellable units are restocked, damaged and disposal units go to their buckets
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Param id path string true "Return ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body ReceiveReturnRequest true "Graded quantities per SKU"
+// @Success 200 {object} models.ReturnAuthorization
+// @Router /returns/{id}/receive [post]
+func ReceiveReturn(c *gin.Context) {
+	var req ReceiveReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	rma, status, err := receiveReturnLogic(models.ReturnModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"), req, movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, rma)
+}
+
+// GetReturnLedger
+
+type ReturnLedgerFetcher interface {
+	GetReturnLedger(ctx context.Context, tenantID uuid.UUID, filter models.ReturnLedgerFilter) ([]models.ReturnLedgerEntry, int64, error)
+}
+
+func getReturnLedgerLogic(service ReturnLedgerFetcher, tenantIDStr, hubIDStr, fromStr, toStr, pageStr, pageSizeStr string) (*ReturnLedger, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	filter := models.ReturnLedgerFilter{}
+	if hubIDStr != "" {
+		hubID, err := uuid.Parse(hubIDStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid hub_id")
+		}
+		filter.HubID = &hubID
+	}
+
+	if filter.From, err = parseOptionalTime(fromStr, "from"); err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+	if filter.To, err = parseOptionalTime(toStr, "to"); err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, int(http.StatusBadRequest), errors.New("from must be before to")
+	}
+
+	if filter.Page, filter.PageSize, err = parsePagination(pageStr, pageSizeStr); err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	entries, total, err := service.GetReturnLedger(context.Background(), tenantID, filter)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch return ledger")
+	}
+
+	return &ReturnLedger{
+		Entries:  entries,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+		Total:    total,
+	}, int(http.StatusOK), nil
+}
+
+// GetReturnLedger godoc
+// @Summary List the tenant's received return units by SKU and grade, newest first
+// @Tags Returns
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param hub_id query string false "Only returns to this hub"
+// @Param from query string false "Start of time range (RFC3339, inclusive)"
+// @Param to query string false "End of time range (RFC3339, exclusive)"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 50, max 500)"
+// @Success 200 {object} ReturnLedger
+// @Router /returns/ledger [get]
+func GetReturnLedger(c *gin.Context) {
+	ledger, status, err := getReturnLedgerLogic(
+		models.ReturnModel{},
+		c.GetHeader("X-Tenant-ID"),
+		c.Query("hub_id"),
+		c.Query("from"),
+		c.Query("to"),
+		c.Query("page"),
+		c.Query("page_size"),
+	)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, ledger)
+}
@@ -0,0 +1,273 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockReturnService struct {
+	CreateReturnFunc    func(ctx context.Context, rma *models.ReturnAuthorization) error
+	ReceiveReturnFunc   func(ctx context.Context, tenantID, id uuid.UUID, grades map[uuid.UUID]models.ReturnGrading) (*models.ReturnAuthorization, error)
+	GetReturnLedgerFunc func(ctx context.Context, tenantID uuid.UUID, filter models.ReturnLedgerFilter) ([]models.ReturnLedgerEntry, int64, error)
+}
+
+func (m *mockReturnService) CreateReturn(ctx context.Context, rma *models.ReturnAuthorization) error {
+	return m.CreateReturnFunc(ctx, rma)
+}
+
+func (m *mockReturnService) ReceiveReturn(ctx context.Context, tenantID, id uuid.UUID, grades map[uuid.UUID]models.ReturnGrading) (*models.ReturnAuthorization, error) {
+	return m.ReceiveReturnFunc(ctx, tenantID, id, grades)
+}
+
+func (m *mockReturnService) GetReturnLedger(ctx context.Context, tenantID uuid.UUID, filter models.ReturnLedgerFilter) ([]models.ReturnLedgerEntry, int64, error) {
+	return m.GetReturnLedgerFunc(ctx, tenantID, filter)
+}
+
+func TestCreateReturnLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	skuID := uuid.New()
+	valid := CreateReturnRequest{HubID: uuid.New(), OrderReference: "ORD-1", Lines: []ReturnLineRequest{{SkuID: skuID, Quantity: 2}}}
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		req            CreateReturnRequest
+		mockFunc       func(ctx context.Context, rma *models.ReturnAuthorization) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad",
+			req:            valid,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "no lines",
+			tenantID:       tenantID,
+			req:            CreateReturnRequest{HubID: valid.HubID, OrderReference: "ORD-1"},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "duplicate sku",
+			tenantID: tenantID,
+			req: CreateReturnRequest{HubID: valid.HubID, OrderReference: "ORD-1", Lines: []ReturnLineRequest{
+				{SkuID: skuID, Quantity: 1}, {SkuID: skuID, Quantity: 1},
+			}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "serialized sku",
+			tenantID: tenantID,
+			req:      valid,
+			mockFunc: func(ctx context.Context, rma *models.ReturnAuthorization) error {
+				return fmt.Errorf("%w: PHONE-1", models.ErrSerializedSku)
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "hub of another tenant",
+			tenantID: tenantID,
+			req:      valid,
+			mockFunc: func(ctx context.Context, rma *models.ReturnAuthorization) error {
+				return models.ErrInvalidReturn
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "created",
+			tenantID: tenantID,
+			req:      valid,
+			mockFunc: func(ctx context.Context, rma *models.ReturnAuthorization) error {
+				rma.Status = models.ReturnOpen
+				return nil
+			},
+			expectedStatus: int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockReturnService{CreateReturnFunc: tt.mockFunc}
+			rma, status, err := createReturnLogic(mock, tt.tenantID, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "ORD-1", rma.OrderReference)
+			}
+		})
+	}
+}
+
+func TestReceiveReturnLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	id := uuid.New().String()
+	skuID := uuid.New()
+	graded := ReceiveReturnRequest{Lines: []GradedLineRequest{
+		{SkuID: skuID, ReturnGrading: models.ReturnGrading{Sellable: 1, Damaged: 1}},
+	}}
+
+	tests := []struct {
+		name           string
+		req            ReceiveReturnRequest
+		mockFunc       func(ctx context.Context, tenantID, id uuid.UUID, grades map[uuid.UUID]models.ReturnGrading) (*models.ReturnAuthorization, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name: "negative grade",
+			req: ReceiveReturnRequest{Lines: []GradedLineRequest{
+				{SkuID: skuID, ReturnGrading: models.ReturnGrading{Disposal: -1}},
+			}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "duplicate sku",
+			req: ReceiveReturnRequest{Lines: []GradedLineRequest{
+				{SkuID: skuID, ReturnGrading: models.ReturnGrading{Sellable: 1}},
+				{SkuID: skuID, ReturnGrading: models.ReturnGrading{Damaged: 1}},
+			}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "not found",
+			req:  graded,
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, grades map[uuid.UUID]models.ReturnGrading) (*models.ReturnAuthorization, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name: "already received",
+			req:  graded,
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, grades map[uuid.UUID]models.ReturnGrading) (*models.ReturnAuthorization, error) {
+				return nil, models.ErrReturnClosed
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "more than authorized",
+			req:  graded,
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, grades map[uuid.UUID]models.ReturnGrading) (*models.ReturnAuthorization, error) {
+				return nil, fmt.Errorf("%w: received more than authorized for sku %s", models.ErrInvalidReturn, skuID)
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "graded",
+			req:  graded,
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, grades map[uuid.UUID]models.ReturnGrading) (*models.ReturnAuthorization, error) {
+				g := grades[skuID]
+				return &models.ReturnAuthorization{
+					Status: models.ReturnReceived,
+					Lines:  []models.ReturnAuthorizationLine{{SkuID: skuID, Quantity: 2, SellableQuantity: g.Sellable, DamagedQuantity: g.Damaged}},
+				}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockReturnService{ReceiveReturnFunc: tt.mockFunc}
+			rma, status, err := receiveReturnLogic(mock, tenantID, id, tt.req, models.MovementMeta{})
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.ReturnReceived, rma.Status)
+				assert.Equal(t, 1, rma.Lines[0].SellableQuantity)
+			}
+		})
+	}
+}
+
+func TestGetReturnLedgerLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	entries := func(ctx context.Context, tenantID uuid.UUID, filter models.ReturnLedgerFilter) ([]models.ReturnLedgerEntry, int64, error) {
+		return []models.ReturnLedgerEntry{{Grade: models.StatusSellable, Quantity: 1}}, 1, nil
+	}
+
+	tests := []struct {
+		name           string
+		hubID          string
+		from, to       string
+		page           string
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, filter models.ReturnLedgerFilter) ([]models.ReturnLedgerEntry, int64, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid hub id",
+			hubID:          "bad",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "from after to",
+			from:           "2025-02-01T00:00:00Z",
+			to:             "2025-01-01T00:00:00Z",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid page",
+			page:           "0",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "db error",
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, filter models.ReturnLedgerFilter) ([]models.ReturnLedgerEntry, int64, error) {
+				return nil, 0, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:           "filtered by hub",
+			hubID:          uuid.New().String(),
+			mockFunc:       entries,
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockReturnService{GetReturnLedgerFunc: tt.mockFunc}
+			ledger, status, err := getReturnLedgerLogic(mock, tenantID, tt.hubID, tt.from, tt.to, tt.page, "")
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), ledger.Total)
+				assert.Equal(t, 1, ledger.Page)
+			}
+		})
+	}
+}
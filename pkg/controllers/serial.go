@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type RegisterSerialsRequest struct {
+	SKUID         uuid.UUID `json:"sku_id" binding:"required"`
+	HubID         uuid.UUID `json:"hub_id" binding:"required"`
+	SerialNumbers []string  `json:"serial_numbers" binding:"required"`
+}
+
+type AllocateSerialsRequest struct {
+	OrderReference string    `json:"order_reference" binding:"required"`
+	SKUID          uuid.UUID `json:"sku_id" binding:"required"`
+	HubID          uuid.UUID `json:"hub_id" binding:"required"`
+	SerialNumbers  []string  `json:"serial_numbers" binding:"required"`
+}
+
+// validateSerialNumbers rejects empty lists, blank serials and duplicates.
+func validateSerialNumbers(serialNumbers []string) error {
+	if len(serialNumbers) == 0 {
+		return errors.New("at least one serial number is required")
+	}
+
+	seen := make(map[string]bool, len(serialNumbers))
+	for _, number := range serialNumbers {
+		if number == "" {
+			return errors.New("serial numbers must not be blank")
+		}
+		if seen[number] {
+			return errors.New("duplicate serial number " + number)
+		}
+		seen[number] = true
+	}
+	return nil
+}
+
+// RegisterSerials
+
+type SerialRegisterer interface {
+	RegisterSerials(ctx context.Context, hubID, skuID uuid.UUID, serialNumbers []string) ([]models.Serial, error)
+}
+
+func registerSerialsLogic(service SerialRegisterer, req RegisterSerialsRequest, meta models.MovementMeta) ([]models.Serial, int, error) {
+	if err := validateSerialNumbers(req.SerialNumbers); err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	serials, err := service.RegisterSerials(models.WithMovementMeta(context.Background(), meta), req.HubID, req.SKUID, req.SerialNumbers)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("hub or sku not found")
+		}
+		if errors.Is(err, models.ErrNotSerialized) || errors.Is(err, models.ErrSerialExists) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to register serials")
+	}
+
+	return serials, int(http.StatusCreated), nil
+}
+
+// RegisterSerials godoc
+// @Summary Receive serialized units into a hub, one unit of stock per serial
+// @Tags Serials
+// @Accept json
+// @Produce json
+// @Param payload body RegisterSerialsRequest true "Serials to register"
+// @Success 201 {array} models.Serial
+// @Router /inventory/serials [post]
+func RegisterSerials(c *gin.Context) {
+	var req RegisterSerialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	serials, status, err := registerSerialsLogic(models.SerialModel{}, req, movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, serials)
+}
+
+// AllocateSerials
+
+type SerialAllocator interface {
+	AllocateSerials(ctx context.Context, hubID, skuID uuid.UUID, orderReference string, serialNumbers []string) ([]models.Serial, error)
+}
+
+func allocateSerialsLogic(service SerialAllocator, req AllocateSerialsRequest, meta models.MovementMeta) ([]models.Serial, int, error) {
+	if err := validateSerialNumbers(req.SerialNumbers); err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	meta.ReferenceID = req.OrderReference
+	ctx := models.WithMovementMeta(context.Background(), meta)
+
+	serials, err := service.AllocateSerials(ctx, req.HubID, req.SKUID, req.OrderReference, req.SerialNumbers)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("inventory not found")
+		}
+		if errors.Is(err, models.ErrSerialUnavailable) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to allocate serials")
+	}
+
+	return serials, int(http.StatusOK), nil
+}
+
+// AllocateSerials godoc
+// @Summary Dispatch in-stock serials from a hub against an order, all or none
+// @Tags Serials
+// @Accept json
+// @Produce json
+// @Param payload body AllocateSerialsRequest true "Serials to allocate"
+// @Success 200 {array} models.Serial
+// @Router /inventory/serials/allocate [post]
+func AllocateSerials(c *gin.Context) {
+	var req AllocateSerialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	serials, status, err := allocateSerialsLogic(models.SerialModel{}, req, movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, serials)
+}
+
+// GetSerial
+
+type SerialFetcher interface {
+	GetSerials(ctx context.Context, serialNumber string, skuID uuid.UUID) ([]models.Serial, error)
+}
+
+func getSerialLogic(service SerialFetcher, serialNumber, skuIDStr string) ([]models.Serial, int, error) {
+	if serialNumber == "" {
+		return nil, int(http.StatusBadRequest), errors.New("missing serial_number")
+	}
+
+	var skuID uuid.UUID
+	if skuIDStr != "" {
+		var err error
+		skuID, err = uuid.Parse(skuIDStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid sku_id")
+		}
+	}
+
+	serials, err := service.GetSerials(context.Background(), serialNumber, skuID)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch serial")
+	}
+	if len(serials) == 0 {
+		return nil, int(http.StatusNotFound), errors.New("serial not found")
+	}
+
+	return serials, int(http.StatusOK), nil
+}
+
+// GetSerial godoc
+// @Summary Look up a serial's current hub and status
+// @Tags Serials
+// @Produce json
+// @Param serial_number path string true "Serial number"
+// @Param sku_id query string false "Restrict to one SKU"
+// @Success 200 {array} models.Serial
+// @Router /inventory/serials/{serial_number} [get]
+func GetSerial(c *gin.Context) {
+	serials, status, err := getSerialLogic(models.SerialModel{}, c.Param("serial_number"), c.Query("sku_id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, serials)
+}
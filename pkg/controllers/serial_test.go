@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// RegisterSerials
+
+type mockSerialRegisterer struct {
+	RegisterSerialsFunc func(ctx context.Context, hubID, skuID uuid.UUID, serialNumbers []string) ([]models.Serial, error)
+}
+
+func (m *mockSerialRegisterer) RegisterSerials(ctx context.Context, hubID, skuID uuid.UUID, serialNumbers []string) ([]models.Serial, error) {
+	return m.RegisterSerialsFunc(ctx, hubID, skuID, serialNumbers)
+}
+
+func TestRegisterSerialsLogic(t *testing.T) {
+	req := RegisterSerialsRequest{SKUID: uuid.New(), HubID: uuid.New(), SerialNumbers: []string{"SN-1", "SN-2"}}
+
+	tests := []struct {
+		name           string
+		req            RegisterSerialsRequest
+		mockFunc       func(ctx context.Context, hubID, skuID uuid.UUID, serialNumbers []string) ([]models.Serial, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "no serials",
+			req:            RegisterSerialsRequest{SKUID: req.SKUID, HubID: req.HubID},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "blank serial",
+			req:            RegisterSerialsRequest{SKUID: req.SKUID, HubID: req.HubID, SerialNumbers: []string{"SN-1", ""}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "duplicate serial",
+			req:            RegisterSerialsRequest{SKUID: req.SKUID, HubID: req.HubID, SerialNumbers: []string{"SN-1", "SN-1"}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "sku not found",
+			req:  req,
+			mockFunc: func(ctx context.Context, hubID, skuID uuid.UUID, serialNumbers []string) ([]models.Serial, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name: "sku not serialized",
+			req:  req,
+			mockFunc: func(ctx context.Context, hubID, skuID uuid.UUID, serialNumbers []string) ([]models.Serial, error) {
+				return nil, models.ErrNotSerialized
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "serial already in stock",
+			req:  req,
+			mockFunc: func(ctx context.Context, hubID, skuID uuid.UUID, serialNumbers []string) ([]models.Serial, error) {
+				return nil, models.ErrSerialExists
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "db error",
+			req:  req,
+			mockFunc: func(ctx context.Context, hubID, skuID uuid.UUID, serialNumbers []string) ([]models.Serial, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name: "success",
+			req:  req,
+			mockFunc: func(ctx context.Context, hubID, skuID uuid.UUID, serialNumbers []string) ([]models.Serial, error) {
+				serials := make([]models.Serial, len(serialNumbers))
+				for i, number := range serialNumbers {
+					serials[i] = models.Serial{SerialNumber: number, HubID: hubID, Status: models.SerialInStock}
+				}
+				return serials, nil
+			},
+			expectedStatus: int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSerialRegisterer{RegisterSerialsFunc: tt.mockFunc}
+			serials, status, err := registerSerialsLogic(mock, tt.req, models.MovementMeta{})
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, serials, 2)
+			}
+		})
+	}
+}
+
+// AllocateSerials
+
+type mockSerialAllocator struct {
+	AllocateSerialsFunc func(ctx context.Context, hubID, skuID uuid.UUID, orderReference string, serialNumbers []string) ([]models.Serial, error)
+}
+
+func (m *mockSerialAllocator) AllocateSerials(ctx context.Context, hubID, skuID uuid.UUID, orderReference string, serialNumbers []string) ([]models.Serial, error) {
+	return m.AllocateSerialsFunc(ctx, hubID, skuID, orderReference, serialNumbers)
+}
+
+func TestAllocateSerialsLogic(t *testing.T) {
+	req := AllocateSerialsRequest{OrderReference: "ORD-1", SKUID: uuid.New(), HubID: uuid.New(), SerialNumbers: []string{"SN-1"}}
+
+	tests := []struct {
+		name           string
+		req            AllocateSerialsRequest
+		mockFunc       func(ctx context.Context, hubID, skuID uuid.UUID, orderReference string, serialNumbers []string) ([]models.Serial, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "no serials",
+			req:            AllocateSerialsRequest{OrderReference: "ORD-1", SKUID: req.SKUID, HubID: req.HubID},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "inventory not found",
+			req:  req,
+			mockFunc: func(ctx context.Context, hubID, skuID uuid.UUID, orderReference string, serialNumbers []string) ([]models.Serial, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name: "serial not in stock",
+			req:  req,
+			mockFunc: func(ctx context.Context, hubID, skuID uuid.UUID, orderReference string, serialNumbers []string) ([]models.Serial, error) {
+				return nil, models.ErrSerialUnavailable
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "db error",
+			req:  req,
+			mockFunc: func(ctx context.Context, hubID, skuID uuid.UUID, orderReference string, serialNumbers []string) ([]models.Serial, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name: "success",
+			req:  req,
+			mockFunc: func(ctx context.Context, hubID, skuID uuid.UUID, orderReference string, serialNumbers []string) ([]models.Serial, error) {
+				assert.Equal(t, "ORD-1", orderReference)
+				return []models.Serial{{SerialNumber: "SN-1", Status: models.SerialAllocated, OrderReference: orderReference}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSerialAllocator{AllocateSerialsFunc: tt.mockFunc}
+			serials, status, err := allocateSerialsLogic(mock, tt.req, models.MovementMeta{})
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, serials, 1)
+			}
+		})
+	}
+}
+
+// GetSerial
+
+type mockSerialFetcher struct {
+	GetSerialsFunc func(ctx context.Context, serialNumber string, skuID uuid.UUID) ([]models.Serial, error)
+}
+
+func (m *mockSerialFetcher) GetSerials(ctx context.Context, serialNumber string, skuID uuid.UUID) ([]models.Serial, error) {
+	return m.GetSerialsFunc(ctx, serialNumber, skuID)
+}
+
+func TestGetSerialLogic(t *testing.T) {
+	tests := []struct {
+		name           string
+		serial         string
+		skuID          string
+		mockFunc       func(ctx context.Context, serialNumber string, skuID uuid.UUID) ([]models.Serial, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid sku id",
+			serial:         "SN-1",
+			skuID:          "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:   "not found",
+			serial: "SN-1",
+			mockFunc: func(ctx context.Context, serialNumber string, skuID uuid.UUID) ([]models.Serial, error) {
+				return nil, nil
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:   "db error",
+			serial: "SN-1",
+			mockFunc: func(ctx context.Context, serialNumber string, skuID uuid.UUID) ([]models.Serial, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:   "found",
+			serial: "SN-1",
+			mockFunc: func(ctx context.Context, serialNumber string, skuID uuid.UUID) ([]models.Serial, error) {
+				return []models.Serial{{SerialNumber: serialNumber, Status: models.SerialInStock}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSerialFetcher{GetSerialsFunc: tt.mockFunc}
+			_, status, err := getSerialLogic(mock, tt.serial, tt.skuID)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
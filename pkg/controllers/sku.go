@@ -15,10 +15,10 @@ import (
 // GetSkus
 
 type SkuFetcher interface {
-	GetFilteredSkus(ctx context.Context, tenantID, sellerID uuid.UUID, skuCodes []string) ([]models.Sku, error)
+	GetFilteredSkus(ctx context.Context, tenantID uuid.UUID, filter models.SkuFilter) ([]models.Sku, error)
 }
 
-func getSkusLogic(service SkuFetcher, tenantIDStr, sellerIDStr string, skuCodes []string) ([]models.Sku, int, error) {
+func getSkusLogic(service SkuFetcher, tenantIDStr, sellerIDStr string, skuCodes []string, productIDStr string, attributes map[string]string) ([]models.Sku, int, error) {
 	if tenantIDStr == "" {
 		return nil, int(http.StatusBadRequest), errors.New("missing X-Tenant-ID header")
 	}
@@ -28,15 +28,23 @@ func getSkusLogic(service SkuFetcher, tenantIDStr, sellerIDStr string, skuCodes
 		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
 	}
 
-	var sellerID uuid.UUID
+	filter := models.SkuFilter{SkuCodes: skuCodes, Attributes: attributes}
 	if sellerIDStr != "" {
-		sellerID, err = uuid.Parse(sellerIDStr)
+		filter.SellerID, err = uuid.Parse(sellerIDStr)
 		if err != nil {
 			return nil, int(http.StatusBadRequest), errors.New("invalid seller_id")
 		}
 	}
 
-	skus, err := service.GetFilteredSkus(context.Background(), tenantID, sellerID, skuCodes)
+	if productIDStr != "" {
+		productID, err := uuid.Parse(productIDStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid product_id")
+		}
+		filter.ProductID = &productID
+	}
+
+	skus, err := service.GetFilteredSkus(context.Background(), tenantID, filter)
 	if err != nil {
 		return nil, int(http.StatusInternalServerError), err
 	}
@@ -51,6 +59,8 @@ func getSkusLogic(service SkuFetcher, tenantIDStr, sellerIDStr string, skuCodes
 // @Param X-Tenant-ID header string true "Tenant ID"
 // @Param seller_id query string false "Filter by Seller ID"
 // @Param sku_codes query []string false "Filter by multiple SKU codes (repeat param)"
+// @Param product_id query string false "Filter by product"
+// @Param attributes query string false "Filter by variant attribute as attributes[name]=value, e.g. attributes[size]=M (repeat for more)"
 // @Success 200 {array} models.Sku
 // @Router /skus [get]
 func GetSkus(c *gin.Context) {
@@ -58,7 +68,7 @@ func GetSkus(c *gin.Context) {
 	sellerIDStr := c.Query("seller_id")
 	skuCodes := c.QueryArray("sku_codes")
 
-	skus, status, err := getSkusLogic(models.SKUModel{}, tenantIDStr, sellerIDStr, skuCodes)
+	skus, status, err := getSkusLogic(models.SKUModel{}, tenantIDStr, sellerIDStr, skuCodes, c.Query("product_id"), c.QueryMap("attributes"))
 	if err != nil {
 		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
 		return
@@ -124,10 +134,17 @@ func createSkuLogic(service SkuCreator, tenantIDStr string, sku *models.Sku) (in
 	}
 	sku.TenantID = tenantID
 
+	if err := checkBundleComponents(sku.Components); err != nil {
+		return int(http.StatusBadRequest), err
+	}
+
 	if err := service.CreateSku(context.Background(), sku); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return int(http.StatusBadRequest), errors.New("tenant or seller not found")
 		}
+		if errors.Is(err, models.ErrInvalidBundle) || errors.Is(err, models.ErrInvalidProduct) || isBarcodeError(err) {
+			return int(http.StatusBadRequest), err
+		}
 		return int(http.StatusInternalServerError), errors.New("failed to create sku")
 	}
 
@@ -224,6 +241,9 @@ func updateSkuLogic(service SkuUpdater, idStr string, sku *models.Sku) (*models.
 	}
 
 	if err := service.UpdateSku(context.Background(), id, sku); err != nil {
+		if errors.Is(err, models.ErrSkuHasStock) || errors.Is(err, models.ErrInvalidProduct) {
+			return nil, int(http.StatusBadRequest), err
+		}
 		return nil, int(http.StatusInternalServerError), err
 	}
 
@@ -262,3 +282,96 @@ func UpdateSku(c *gin.Context) {
 
 	c.JSON(status, updated)
 }
+
+// SetBundleComponents
+
+type BundleComponentRequest struct {
+	SkuID    uuid.UUID `json:"sku_id" binding:"required"`
+	Quantity int       `json:"quantity" binding:"required"` // units of the component in one bundle
+}
+
+type SetBundleComponentsRequest struct {
+	Components []BundleComponentRequest `json:"components"` // empty to stop selling the SKU as a bundle
+}
+
+type SkuBundler interface {
+	SetBundleComponents(ctx context.Context, skuID uuid.UUID, components []models.BundleComponent) error
+	GetSku(ctx context.Context, id uuid.UUID) (*models.Sku, error)
+}
+
+// checkBundleComponents rejects components without a SKU, repeated SKUs and
+// non-positive quantities.
+func checkBundleComponents(components []models.BundleComponent) error {
+	seen := make(map[uuid.UUID]bool, len(components))
+	for _, component := range components {
+		if component.ComponentSkuID == uuid.Nil {
+			return errors.New("every component needs a sku_id")
+		}
+		if seen[component.ComponentSkuID] {
+			return errors.New("duplicate component sku")
+		}
+		seen[component.ComponentSkuID] = true
+		if component.Quantity <= 0 {
+			return errors.New("component quantity must be positive")
+		}
+	}
+	return nil
+}
+
+func setBundleComponentsLogic(service SkuBundler, idStr string, req SetBundleComponentsRequest) (*models.Sku, int, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid sku id")
+	}
+
+	components := make([]models.BundleComponent, len(req.Components))
+	for i, component := range req.Components {
+		components[i] = models.BundleComponent{ComponentSkuID: component.SkuID, Quantity: component.Quantity}
+	}
+	if err := checkBundleComponents(components); err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	if err := service.SetBundleComponents(context.Background(), id, components); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("sku not found")
+		}
+		if errors.Is(err, models.ErrInvalidBundle) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to update bundle")
+	}
+
+	sku, err := service.GetSku(context.Background(), id)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), err
+	}
+
+	return sku, int(http.StatusOK), nil
+}
+
+// SetBundleComponents godoc
+// @Summary Set the component SKUs and quantities of a bundle
+// @Tags SKUs
+// @Accept json
+// @Produce json
+// @Param id path string true "SKU ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param bundle body SetBundleComponentsRequest true "Components of one bundle"
+// @Success 200 {object} models.Sku
+// @Router /skus/{id}/components [put]
+func SetBundleComponents(c *gin.Context) {
+	var req SetBundleComponentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request body")})
+		return
+	}
+
+	sku, status, err := setBundleComponentsLogic(models.SKUModel{}, c.Param("id"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, sku)
+}
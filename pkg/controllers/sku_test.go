@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/aditya-goyal-omniful/ims/pkg/models"
@@ -15,23 +16,26 @@ import (
 // GetSkus
 
 type mockSkuFetcher struct {
-	GetFilteredSkusFunc func(ctx context.Context, tenantID, sellerID uuid.UUID, skuCodes []string) ([]models.Sku, error)
+	GetFilteredSkusFunc func(ctx context.Context, tenantID uuid.UUID, filter models.SkuFilter) ([]models.Sku, error)
 }
 
-func (m *mockSkuFetcher) GetFilteredSkus(ctx context.Context, tenantID, sellerID uuid.UUID, skuCodes []string) ([]models.Sku, error) {
-	return m.GetFilteredSkusFunc(ctx, tenantID, sellerID, skuCodes)
+func (m *mockSkuFetcher) GetFilteredSkus(ctx context.Context, tenantID uuid.UUID, filter models.SkuFilter) ([]models.Sku, error) {
+	return m.GetFilteredSkusFunc(ctx, tenantID, filter)
 }
 
 func TestGetSkusLogic(t *testing.T) {
 	tenantID := uuid.New()
 	sellerID := uuid.New()
+	productID := uuid.New()
 
 	tests := []struct {
 		name           string
 		tenantIDStr    string
 		sellerIDStr    string
 		skuCodes       []string
-		mockFunc       func(ctx context.Context, tenantID, sellerID uuid.UUID, skuCodes []string) ([]models.Sku, error)
+		productIDStr   string
+		attributes     map[string]string
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, filter models.SkuFilter) ([]models.Sku, error)
 		expectedStatus int
 		expectErr      bool
 	}{
@@ -54,12 +58,32 @@ func TestGetSkusLogic(t *testing.T) {
 			expectedStatus: int(http.StatusBadRequest),
 			expectErr:      true,
 		},
+		{
+			name:           "invalid product uuid",
+			tenantIDStr:    tenantID.String(),
+			productIDStr:   "bad-product-id",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:         "filtered by product and attribute",
+			tenantIDStr:  tenantID.String(),
+			productIDStr: productID.String(),
+			attributes:   map[string]string{"size": "M"},
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, filter models.SkuFilter) ([]models.Sku, error) {
+				assert.Equal(t, productID, *filter.ProductID)
+				assert.Equal(t, models.VariantAttributes{"size": "M"}, filter.Attributes)
+				return []models.Sku{{SkuCode: "TEE-M", ProductID: &productID}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+			expectErr:      false,
+		},
 		{
 			name:        "fetch error",
 			tenantIDStr: tenantID.String(),
 			sellerIDStr: sellerID.String(),
 			skuCodes:    []string{"SKU1", "SKU2"},
-			mockFunc: func(ctx context.Context, tenantID, sellerID uuid.UUID, skuCodes []string) ([]models.Sku, error) {
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, filter models.SkuFilter) ([]models.Sku, error) {
 				return nil, errors.New("db error")
 			},
 			expectedStatus: int(http.StatusInternalServerError),
@@ -70,7 +94,7 @@ func TestGetSkusLogic(t *testing.T) {
 			tenantIDStr: tenantID.String(),
 			sellerIDStr: sellerID.String(),
 			skuCodes:    []string{"SKU1", "SKU2"},
-			mockFunc: func(ctx context.Context, tenantID, sellerID uuid.UUID, skuCodes []string) ([]models.Sku, error) {
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, filter models.SkuFilter) ([]models.Sku, error) {
 				return []models.Sku{{SkuCode: "SKU1"}, {SkuCode: "SKU2"}}, nil
 			},
 			expectedStatus: int(http.StatusOK),
@@ -81,7 +105,7 @@ func TestGetSkusLogic(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := &mockSkuFetcher{GetFilteredSkusFunc: tt.mockFunc}
-			result, status, err := getSkusLogic(mock, tt.tenantIDStr, tt.sellerIDStr, tt.skuCodes)
+			result, status, err := getSkusLogic(mock, tt.tenantIDStr, tt.sellerIDStr, tt.skuCodes, tt.productIDStr, tt.attributes)
 
 			assert.Equal(t, tt.expectedStatus, status)
 			if tt.expectErr {
@@ -208,6 +232,15 @@ func TestCreateSkuLogic(t *testing.T) {
 			expectedStatus: int(http.StatusBadRequest),
 			expectErr:      true,
 		},
+		{
+			name:        "bundle component without quantity",
+			tenantIDStr: validTenantID.String(),
+			inputSku: &models.Sku{Name: "Gift pack", SkuCode: "GIFT-1", SellerID: uuid.New(), Components: []models.BundleComponent{
+				{ComponentSkuID: uuid.New()},
+			}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
 		{
 			name:        "creation error",
 			tenantIDStr: validTenantID.String(),
@@ -414,3 +447,119 @@ func TestUpdateSkuLogic(t *testing.T) {
 		})
 	}
 }
+
+// SetBundleComponents
+
+type mockSkuBundler struct {
+	SetBundleComponentsFunc func(ctx context.Context, skuID uuid.UUID, components []models.BundleComponent) error
+	GetSkuFunc              func(ctx context.Context, id uuid.UUID) (*models.Sku, error)
+}
+
+func (m *mockSkuBundler) SetBundleComponents(ctx context.Context, skuID uuid.UUID, components []models.BundleComponent) error {
+	return m.SetBundleComponentsFunc(ctx, skuID, components)
+}
+
+func (m *mockSkuBundler) GetSku(ctx context.Context, id uuid.UUID) (*models.Sku, error) {
+	return m.GetSkuFunc(ctx, id)
+}
+
+func TestSetBundleComponentsLogic(t *testing.T) {
+	id := uuid.New()
+	mug := uuid.New()
+	tea := uuid.New()
+	giftPack := SetBundleComponentsRequest{Components: []BundleComponentRequest{
+		{SkuID: mug, Quantity: 1},
+		{SkuID: tea, Quantity: 2},
+	}}
+
+	tests := []struct {
+		name           string
+		idStr          string
+		req            SetBundleComponentsRequest
+		mockSet        func(ctx context.Context, skuID uuid.UUID, components []models.BundleComponent) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid id",
+			idStr:          "bad-id",
+			req:            giftPack,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "duplicate component",
+			idStr: id.String(),
+			req: SetBundleComponentsRequest{Components: []BundleComponentRequest{
+				{SkuID: mug, Quantity: 1},
+				{SkuID: mug, Quantity: 1},
+			}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "non-positive quantity",
+			idStr: id.String(),
+			req: SetBundleComponentsRequest{Components: []BundleComponentRequest{
+				{SkuID: mug, Quantity: 0},
+			}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "sku not found",
+			idStr: id.String(),
+			req:   giftPack,
+			mockSet: func(ctx context.Context, skuID uuid.UUID, components []models.BundleComponent) error {
+				return gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:  "nested bundle",
+			idStr: id.String(),
+			req:   giftPack,
+			mockSet: func(ctx context.Context, skuID uuid.UUID, components []models.BundleComponent) error {
+				return fmt.Errorf("%w: components cannot be bundles", models.ErrInvalidBundle)
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "success",
+			idStr: id.String(),
+			req:   giftPack,
+			mockSet: func(ctx context.Context, skuID uuid.UUID, components []models.BundleComponent) error {
+				assert.Equal(t, id, skuID)
+				assert.Equal(t, tea, components[1].ComponentSkuID)
+				assert.Equal(t, 2, components[1].Quantity)
+				return nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSkuBundler{
+				SetBundleComponentsFunc: tt.mockSet,
+				GetSkuFunc: func(ctx context.Context, id uuid.UUID) (*models.Sku, error) {
+					return &models.Sku{ID: id, Components: []models.BundleComponent{
+						{BundleSkuID: id, ComponentSkuID: mug, Quantity: 1},
+						{BundleSkuID: id, ComponentSkuID: tea, Quantity: 2},
+					}}, nil
+				},
+			}
+			sku, status, err := setBundleComponentsLogic(mock, tt.idStr, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, sku.Components, 2)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/aditya-goyal-omniful/ims/pkg/sourcing"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type SourcingRequest struct {
+	Destination sourcing.Destination `json:"destination"`
+	Lines       []sourcing.Line      `json:"lines" binding:"required"`
+}
+
+// PlanSourcing
+
+type SourcingPlanner interface {
+	GetTenantSourcingStrategy(ctx context.Context, tenantID uuid.UUID) (string, error)
+	GetSourcingCandidates(ctx context.Context, tenantID uuid.UUID, skuIDs []uuid.UUID) ([]sourcing.Candidate, error)
+}
+
+func validateDestination(dest sourcing.Destination) error {
+	if (dest.Latitude == nil) != (dest.Longitude == nil) {
+		return errors.New("destination needs both latitude and longitude")
+	}
+	if dest.Latitude == nil && dest.PostalCode == "" {
+		return errors.New("destination needs a postal_code or coordinates")
+	}
+	if dest.Latitude != nil && (*dest.Latitude < -90 || *dest.Latitude > 90 || *dest.Longitude < -180 || *dest.Longitude > 180) {
+		return errors.New("destination coordinates out of range")
+	}
+	return nil
+}
+
+func planSourcingLogic(service SourcingPlanner, tenantIDStr string, req SourcingRequest) (*sourcing.Plan, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	if err := validateDestination(req.Destination); err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	if len(req.Lines) == 0 {
+		return nil, int(http.StatusBadRequest), errors.New("at least one line is required")
+	}
+
+	skuIDs := make([]uuid.UUID, 0, len(req.Lines))
+	seen := make(map[uuid.UUID]bool, len(req.Lines))
+	for _, line := range req.Lines {
+		if line.Quantity <= 0 {
+			return nil, int(http.StatusBadRequest), errors.New("quantity must be positive")
+		}
+		if seen[line.SkuID] {
+			return nil, int(http.StatusBadRequest), errors.New("duplicate sku_id in lines")
+		}
+		seen[line.SkuID] = true
+		skuIDs = append(skuIDs, line.SkuID)
+	}
+
+	ctx := context.Background()
+	name, err := service.GetTenantSourcingStrategy(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("tenant not found")
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to load sourcing strategy")
+	}
+
+	strategy, ok := sourcing.Lookup(name)
+	if !ok {
+		return nil, int(http.StatusInternalServerError), errors.New("unknown sourcing strategy " + name)
+	}
+
+	candidates, err := service.GetSourcingCandidates(ctx, tenantID, skuIDs)
+	if err != nil {
+		if errors.Is(err, models.ErrATPNotFound) {
+			return nil, int(http.StatusBadRequest), errors.New("sku not found for tenant")
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to load hub stock")
+	}
+
+	plan := strategy.Plan(req.Lines, sourcing.Rank(req.Destination, candidates))
+	return &plan, int(http.StatusOK), nil
+}
+
+// PlanSourcing godoc
+// @Summary Choose the hub, or split across hubs, to fulfil order lines for a destination using the tenant's sourcing strategy
+// @Tags Sourcing
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body SourcingRequest true "Destination and order lines"
+// @Success 200 {object} sourcing.Plan
+// @Router /sourcing/plan [post]
+func PlanSourcing(c *gin.Context) {
+	var req SourcingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	plan, status, err := planSourcingLogic(models.SourcingModel{}, c.GetHeader("X-Tenant-ID"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, plan)
+}
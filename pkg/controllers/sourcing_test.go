@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/aditya-goyal-omniful/ims/pkg/sourcing"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockSourcingPlanner struct {
+	GetTenantSourcingStrategyFunc func(ctx context.Context, tenantID uuid.UUID) (string, error)
+	GetSourcingCandidatesFunc     func(ctx context.Context, tenantID uuid.UUID, skuIDs []uuid.UUID) ([]sourcing.Candidate, error)
+}
+
+func (m *mockSourcingPlanner) GetTenantSourcingStrategy(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	return m.GetTenantSourcingStrategyFunc(ctx, tenantID)
+}
+
+func (m *mockSourcingPlanner) GetSourcingCandidates(ctx context.Context, tenantID uuid.UUID, skuIDs []uuid.UUID) ([]sourcing.Candidate, error) {
+	return m.GetSourcingCandidatesFunc(ctx, tenantID, skuIDs)
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestPlanSourcingLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	skuID := uuid.New()
+	near, far := uuid.New(), uuid.New()
+	dest := sourcing.Destination{Latitude: floatPtr(12.97), Longitude: floatPtr(77.59)}
+	lines := []sourcing.Line{{SkuID: skuID, Quantity: 5}}
+
+	strategy := func(name string) func(ctx context.Context, tenantID uuid.UUID) (string, error) {
+		return func(ctx context.Context, tenantID uuid.UUID) (string, error) { return name, nil }
+	}
+	candidates := func(ctx context.Context, tenantID uuid.UUID, skuIDs []uuid.UUID) ([]sourcing.Candidate, error) {
+		return []sourcing.Candidate{
+			{HubID: far, HubName: "far", Latitude: floatPtr(28.61), Longitude: floatPtr(77.20), Available: map[uuid.UUID]int{skuID: 10}},
+			{HubID: near, HubName: "near", Latitude: floatPtr(13.08), Longitude: floatPtr(80.27), Available: map[uuid.UUID]int{skuID: 3}},
+		}, nil
+	}
+
+	tests := []struct {
+		name           string
+		req            SourcingRequest
+		strategyFunc   func(ctx context.Context, tenantID uuid.UUID) (string, error)
+		candidatesFunc func(ctx context.Context, tenantID uuid.UUID, skuIDs []uuid.UUID) ([]sourcing.Candidate, error)
+		expectedStatus int
+		expectErr      bool
+		expectedHubs   int
+	}{
+		{
+			name:           "missing destination",
+			req:            SourcingRequest{Lines: lines},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "latitude without longitude",
+			req:            SourcingRequest{Destination: sourcing.Destination{Latitude: floatPtr(1)}, Lines: lines},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "non-positive quantity",
+			req:            SourcingRequest{Destination: dest, Lines: []sourcing.Line{{SkuID: skuID}}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "tenant not found",
+			req:  SourcingRequest{Destination: dest, Lines: lines},
+			strategyFunc: func(ctx context.Context, tenantID uuid.UUID) (string, error) {
+				return "", gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:           "unregistered strategy",
+			req:            SourcingRequest{Destination: dest, Lines: lines},
+			strategyFunc:   strategy("cheapest"),
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:         "sku of another tenant",
+			req:          SourcingRequest{Destination: dest, Lines: lines},
+			strategyFunc: strategy("single_hub"),
+			candidatesFunc: func(ctx context.Context, tenantID uuid.UUID, skuIDs []uuid.UUID) ([]sourcing.Candidate, error) {
+				return nil, models.ErrATPNotFound
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:         "db error",
+			req:          SourcingRequest{Destination: dest, Lines: lines},
+			strategyFunc: strategy("single_hub"),
+			candidatesFunc: func(ctx context.Context, tenantID uuid.UUID, skuIDs []uuid.UUID) ([]sourcing.Candidate, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:           "single hub prefers the hub holding everything",
+			req:            SourcingRequest{Destination: dest, Lines: lines},
+			strategyFunc:   strategy("single_hub"),
+			candidatesFunc: candidates,
+			expectedStatus: int(http.StatusOK),
+			expectedHubs:   1,
+		},
+		{
+			name:           "nearest splits from the closest hub",
+			req:            SourcingRequest{Destination: dest, Lines: lines},
+			strategyFunc:   strategy("nearest"),
+			candidatesFunc: candidates,
+			expectedStatus: int(http.StatusOK),
+			expectedHubs:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSourcingPlanner{
+				GetTenantSourcingStrategyFunc: tt.strategyFunc,
+				GetSourcingCandidatesFunc:     tt.candidatesFunc,
+			}
+			plan, status, err := planSourcingLogic(mock, tenantID, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, plan.Fulfillable)
+				assert.Equal(t, tt.expectedHubs, plan.HubCount)
+			}
+		})
+	}
+}
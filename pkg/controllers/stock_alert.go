@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+var stockAlertStatuses = map[string]bool{
+	models.StockAlertOpen:         true,
+	models.StockAlertAcknowledged: true,
+	models.StockAlertResolved:     true,
+}
+
+// SetStockThresholds
+
+type StockThresholdSetter interface {
+	SetStockThresholds(ctx context.Context, inventoryID uuid.UUID, thresholds models.StockThresholds) (*models.Inventory, error)
+}
+
+func setStockThresholdsLogic(service StockThresholdSetter, idStr string, req models.StockThresholds) (*models.Inventory, int, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid inventory id")
+	}
+
+	for _, v := range []models.OptionalInt{req.MinQuantity, req.ReorderPoint, req.SafetyStock, req.LeadTimeDays, req.DaysOfCover} {
+		if v.Value != nil && *v.Value < 0 {
+			return nil, int(http.StatusBadRequest), errors.New("thresholds must not be negative")
+		}
+	}
+
+	inv, err := service.SetStockThresholds(context.Background(), id, req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("inventory not found")
+		}
+		if errors.Is(err, models.ErrInvalidThresholds) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to set thresholds")
+	}
+
+	return inv, int(http.StatusOK), nil
+}
+
+// SetStockThresholds godoc
+// @Summary Change the thresholds, safety stock, lead time or days of cover of an inventory row; fields left out are kept and null clears one
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Inventory ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body models.StockThresholds true "Thresholds"
+// @Success 200 {object} models.Inventory
+// @Router /inventories/{id}/thresholds [put]
+func SetStockThresholds(c *gin.Context) {
+	var req models.StockThresholds
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	inv, status, err := setStockThresholdsLogic(models.InventoryModel{}, c.Param("id"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, inv)
+}
+
+// GetStockAlerts
+
+type StockAlertFetcher interface {
+	GetStockAlerts(ctx context.Context, tenantID uuid.UUID, filter models.StockAlertFilter) ([]models.StockAlert, error)
+}
+
+func getStockAlertsLogic(service StockAlertFetcher, tenantIDStr, hubIDStr, status string) ([]models.StockAlert, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	var filter models.StockAlertFilter
+	if hubIDStr != "" {
+		hubID, err := uuid.Parse(hubIDStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid hub_id")
+		}
+		filter.HubID = &hubID
+	}
+
+	if status != "" && !stockAlertStatuses[status] {
+		return nil, int(http.StatusBadRequest), errors.New("status must be open, acknowledged or resolved")
+	}
+	filter.Status = status
+
+	alerts, err := service.GetStockAlerts(context.Background(), tenantID, filter)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch alerts")
+	}
+
+	return alerts, int(http.StatusOK), nil
+}
+
+// GetStockAlerts godoc
+// @Summary List low-stock alerts of the tenant, unresolved ones unless a status is given
+// @Tags Alerts
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param hub_id query string false "Hub ID"
+// @Param status query string false "open, acknowledged or resolved"
+// @Success 200 {array} models.StockAlert
+// @Router /alerts [get]
+func GetStockAlerts(c *gin.Context) {
+	alerts, status, err := getStockAlertsLogic(models.StockAlertModel{}, c.GetHeader("X-Tenant-ID"), c.Query("hub_id"), c.Query("status"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, alerts)
+}
+
+// AcknowledgeStockAlert
+
+type StockAlertAcknowledger interface {
+	AcknowledgeStockAlert(ctx context.Context, tenantID, id uuid.UUID, actor string) (*models.StockAlert, error)
+}
+
+func acknowledgeStockAlertLogic(service StockAlertAcknowledger, tenantIDStr, idStr, actor string) (*models.StockAlert, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	alert, err := service.AcknowledgeStockAlert(context.Background(), tenantID, id, actor)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("alert not found")
+		}
+		if errors.Is(err, models.ErrAlertResolved) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to acknowledge alert")
+	}
+
+	return alert, int(http.StatusOK), nil
+}
+
+// AcknowledgeStockAlert godoc
+// @Summary Acknowledge an unresolved low-stock alert
+// @Tags Alerts
+// @Produce json
+// @Param id path string true "Alert ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {object} models.StockAlert
+// @Router /alerts/{id}/acknowledge [post]
+func AcknowledgeStockAlert(c *gin.Context) {
+	alert, status, err := acknowledgeStockAlertLogic(models.StockAlertModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"), c.GetHeader("X-User-ID"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, alert)
+}
@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func intPtr(v int) *int { return &v }
+
+func optionalInt(v int) models.OptionalInt { return models.OptionalInt{Set: true, Value: &v} }
+
+// SetStockThresholds
+
+type mockStockThresholdSetter struct {
+	SetStockThresholdsFunc func(ctx context.Context, inventoryID uuid.UUID, thresholds models.StockThresholds) (*models.Inventory, error)
+}
+
+func (m *mockStockThresholdSetter) SetStockThresholds(ctx context.Context, inventoryID uuid.UUID, thresholds models.StockThresholds) (*models.Inventory, error) {
+	return m.SetStockThresholdsFunc(ctx, inventoryID, thresholds)
+}
+
+func TestSetStockThresholdsLogic(t *testing.T) {
+	id := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		id             string
+		req            models.StockThresholds
+		mockFunc       func(ctx context.Context, inventoryID uuid.UUID, thresholds models.StockThresholds) (*models.Inventory, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid id",
+			id:             "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "negative threshold",
+			id:             id,
+			req:            models.StockThresholds{ReorderPoint: optionalInt(-1)},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "negative lead time",
+			id:             id,
+			req:            models.StockThresholds{LeadTimeDays: optionalInt(-3)},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "min above reorder point",
+			id:   id,
+			req:  models.StockThresholds{MinQuantity: optionalInt(10), ReorderPoint: optionalInt(5)},
+			mockFunc: func(ctx context.Context, inventoryID uuid.UUID, thresholds models.StockThresholds) (*models.Inventory, error) {
+				return nil, models.ErrInvalidThresholds
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "inventory not found",
+			id:   id,
+			req:  models.StockThresholds{ReorderPoint: optionalInt(5)},
+			mockFunc: func(ctx context.Context, inventoryID uuid.UUID, thresholds models.StockThresholds) (*models.Inventory, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name: "success",
+			id:   id,
+			req:  models.StockThresholds{MinQuantity: optionalInt(2), ReorderPoint: optionalInt(5)},
+			mockFunc: func(ctx context.Context, inventoryID uuid.UUID, thresholds models.StockThresholds) (*models.Inventory, error) {
+				return &models.Inventory{ID: inventoryID, MinQuantity: thresholds.MinQuantity.Value, ReorderPoint: thresholds.ReorderPoint.Value}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStockThresholdSetter{SetStockThresholdsFunc: tt.mockFunc}
+			inv, status, err := setStockThresholdsLogic(mock, tt.id, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 5, *inv.ReorderPoint)
+			}
+		})
+	}
+}
+
+func TestStockThresholdsJSON(t *testing.T) {
+	var req models.StockThresholds
+	err := json.Unmarshal([]byte(`{"reorder_point": 5, "min_quantity": null}`), &req)
+	assert.NoError(t, err)
+
+	assert.True(t, req.ReorderPoint.Set)
+	assert.Equal(t, 5, *req.ReorderPoint.Value)
+	assert.True(t, req.MinQuantity.Set)
+	assert.Nil(t, req.MinQuantity.Value)
+	assert.False(t, req.SafetyStock.Set)
+	assert.False(t, req.LeadTimeDays.Set)
+	assert.False(t, req.DaysOfCover.Set)
+
+	err = json.Unmarshal([]byte(`{"safety_stock": "ten"}`), &req)
+	assert.Error(t, err)
+}
+
+// GetStockAlerts
+
+type mockStockAlertFetcher struct {
+	GetStockAlertsFunc func(ctx context.Context, tenantID uuid.UUID, filter models.StockAlertFilter) ([]models.StockAlert, error)
+}
+
+func (m *mockStockAlertFetcher) GetStockAlerts(ctx context.Context, tenantID uuid.UUID, filter models.StockAlertFilter) ([]models.StockAlert, error) {
+	return m.GetStockAlertsFunc(ctx, tenantID, filter)
+}
+
+func TestGetStockAlertsLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	hubID := uuid.New()
+
+	tests := []struct {
+		name           string
+		hubID          string
+		status         string
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, filter models.StockAlertFilter) ([]models.StockAlert, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid hub id",
+			hubID:          "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid status",
+			status:         "closed",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "db error",
+			hubID: hubID.String(),
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, filter models.StockAlertFilter) ([]models.StockAlert, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:   "success",
+			hubID:  hubID.String(),
+			status: models.StockAlertOpen,
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, filter models.StockAlertFilter) ([]models.StockAlert, error) {
+				assert.Equal(t, hubID, *filter.HubID)
+				assert.Equal(t, models.StockAlertOpen, filter.Status)
+				return []models.StockAlert{{HubID: hubID, Level: models.StockAlertReorder}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStockAlertFetcher{GetStockAlertsFunc: tt.mockFunc}
+			alerts, status, err := getStockAlertsLogic(mock, tenantID, tt.hubID, tt.status)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, alerts, 1)
+			}
+		})
+	}
+}
+
+// AcknowledgeStockAlert
+
+type mockStockAlertAcknowledger struct {
+	AcknowledgeStockAlertFunc func(ctx context.Context, tenantID, id uuid.UUID, actor string) (*models.StockAlert, error)
+}
+
+func (m *mockStockAlertAcknowledger) AcknowledgeStockAlert(ctx context.Context, tenantID, id uuid.UUID, actor string) (*models.StockAlert, error) {
+	return m.AcknowledgeStockAlertFunc(ctx, tenantID, id, actor)
+}
+
+func TestAcknowledgeStockAlertLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	id := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		mockFunc       func(ctx context.Context, tenantID, id uuid.UUID, actor string) (*models.StockAlert, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name: "not found",
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, actor string) (*models.StockAlert, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name: "already resolved",
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, actor string) (*models.StockAlert, error) {
+				return nil, models.ErrAlertResolved
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "success",
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, actor string) (*models.StockAlert, error) {
+				return &models.StockAlert{ID: id, Status: models.StockAlertAcknowledged, AcknowledgedBy: actor}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStockAlertAcknowledger{AcknowledgeStockAlertFunc: tt.mockFunc}
+			alert, status, err := acknowledgeStockAlertLogic(mock, tenantID, id, "user-1")
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "user-1", alert.AcknowledgedBy)
+			}
+		})
+	}
+}
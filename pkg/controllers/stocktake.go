@@ -0,0 +1,286 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type CreateStocktakeRequest struct {
+	HubID  uuid.UUID   `json:"hub_id" binding:"required"`
+	SkuIDs []uuid.UUID `json:"sku_ids"`
+}
+
+type StocktakeCount struct {
+	SkuID           uuid.UUID `json:"sku_id" binding:"required"`
+	CountedQuantity int       `json:"counted_quantity"`
+}
+
+type RecordStocktakeCountsRequest struct {
+	Counts []StocktakeCount `json:"counts" binding:"required"`
+}
+
+// stocktakeError maps model errors shared by the stocktake endpoints.
+func stocktakeError(err error, fallback string) (int, error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return int(http.StatusNotFound), errors.New("stocktake not found")
+	case errors.Is(err, models.ErrInvalidStocktake), errors.Is(err, models.ErrStocktakeClosed):
+		return int(http.StatusBadRequest), err
+	default:
+		return int(http.StatusInternalServerError), errors.New(fallback)
+	}
+}
+
+func parseTenantAndID(tenantIDStr, idStr string) (uuid.UUID, uuid.UUID, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("invalid tenant_id in header")
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errors.New("invalid id")
+	}
+	return tenantID, id, nil
+}
+
+// CreateStocktake
+
+type StocktakeCreator interface {
+	CreateStocktake(ctx context.Context, stocktake *models.Stocktake, skuIDs []uuid.UUID) error
+}
+
+func createStocktakeLogic(service StocktakeCreator, tenantIDStr string, req CreateStocktakeRequest, actor string) (*models.Stocktake, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	stocktake := &models.Stocktake{TenantID: tenantID, HubID: req.HubID, CreatedBy: actor}
+	if err := service.CreateStocktake(context.Background(), stocktake, req.SkuIDs); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusBadRequest), errors.New("hub not found")
+		}
+		status, err := stocktakeError(err, "failed to create stocktake")
+		return nil, status, err
+	}
+
+	return stocktake, int(http.StatusCreated), nil
+}
+
+// CreateStocktake godoc
+// @Summary Open a stocktake for a hub, snapshotting expected quantities (optionally for a SKU list)
+// @Tags Stocktakes
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body CreateStocktakeRequest true "Stocktake scope"
+// @Success 201 {object} models.Stocktake
+// @Router /stocktakes [post]
+func CreateStocktake(c *gin.Context) {
+	var req CreateStocktakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	stocktake, status, err := createStocktakeLogic(models.StocktakeModel{}, c.GetHeader("X-Tenant-ID"), req, c.GetHeader("X-User-ID"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, stocktake)
+}
+
+// GetStocktake
+
+type StocktakeFetcher interface {
+	GetStocktake(ctx context.Context, tenantID, id uuid.UUID) (*models.Stocktake, error)
+}
+
+func getStocktakeLogic(service StocktakeFetcher, tenantIDStr, idStr string) (*models.Stocktake, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	stocktake, err := service.GetStocktake(context.Background(), tenantID, id)
+	if err != nil {
+		status, err := stocktakeError(err, "failed to fetch stocktake")
+		return nil, status, err
+	}
+
+	return stocktake, int(http.StatusOK), nil
+}
+
+// GetStocktake godoc
+// @Summary Get a stocktake with its lines
+// @Tags Stocktakes
+// @Produce json
+// @Param id path string true "Stocktake ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {object} models.Stocktake
+// @Router /stocktakes/{id} [get]
+func GetStocktake(c *gin.Context) {
+	stocktake, status, err := getStocktakeLogic(models.StocktakeModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, stocktake)
+}
+
+// RecordStocktakeCounts
+
+type StocktakeCounter interface {
+	RecordStocktakeCounts(ctx context.Context, tenantID, id uuid.UUID, counts map[uuid.UUID]int) error
+}
+
+func recordStocktakeCountsLogic(service StocktakeCounter, tenantIDStr, idStr string, req RecordStocktakeCountsRequest) (int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return int(http.StatusBadRequest), err
+	}
+
+	if len(req.Counts) == 0 {
+		return int(http.StatusBadRequest), errors.New("at least one count is required")
+	}
+
+	counts := make(map[uuid.UUID]int, len(req.Counts))
+	for _, count := range req.Counts {
+		if count.CountedQuantity < 0 {
+			return int(http.StatusBadRequest), errors.New("counted_quantity must not be negative")
+		}
+		if _, dup := counts[count.SkuID]; dup {
+			return int(http.StatusBadRequest), errors.New("duplicate sku_id in counts")
+		}
+		counts[count.SkuID] = count.CountedQuantity
+	}
+
+	if err := service.RecordStocktakeCounts(context.Background(), tenantID, id, counts); err != nil {
+		return stocktakeError(err, "failed to record counts")
+	}
+
+	return int(http.StatusOK), nil
+}
+
+// RecordStocktakeCounts godoc
+// @Summary Record counted quantities for an open stocktake
+// @Tags Stocktakes
+// @Accept json
+// @Produce json
+// @Param id path string true "Stocktake ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body RecordStocktakeCountsRequest true "Counts per SKU"
+// @Success 200 {object} map[string]string
+// @Router /stocktakes/{id}/counts [post]
+func RecordStocktakeCounts(c *gin.Context) {
+	var req RecordStocktakeCountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	status, err := recordStocktakeCountsLogic(models.StocktakeModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, gin.H{i18n.Translate(c, "message"): i18n.Translate(c, "Counts recorded")})
+}
+
+// ApproveStocktake
+
+type StocktakeApprover interface {
+	ApproveStocktake(ctx context.Context, tenantID, id uuid.UUID) (*models.Stocktake, error)
+}
+
+func approveStocktakeLogic(service StocktakeApprover, tenantIDStr, idStr string, meta models.MovementMeta) (*models.Stocktake, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	if meta.Actor == "" {
+		return nil, int(http.StatusBadRequest), errors.New("X-User-ID header is required")
+	}
+
+	stocktake, err := service.ApproveStocktake(models.WithMovementMeta(context.Background(), meta), tenantID, id)
+	if err != nil {
+		if errors.Is(err, models.ErrSerializedSku) || errors.Is(err, models.ErrStocktakeSelfApproval) || isQuantityAdjustmentError(err) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		status, err := stocktakeError(err, "failed to approve stocktake")
+		return nil, status, err
+	}
+
+	return stocktake, int(http.StatusOK), nil
+}
+
+// ApproveStocktake godoc
+// @Summary Approve a stocktake, booking counted variances as adjustments
+// @Tags Stocktakes
+// @Produce json
+// @Param id path string true "Stocktake ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param X-User-ID header string true "Approving user, other than the stocktake's creator"
+// @Success 200 {object} models.Stocktake
+// @Router /stocktakes/{id}/approve [post]
+func ApproveStocktake(c *gin.Context) {
+	stocktake, status, err := approveStocktakeLogic(models.StocktakeModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"), movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, stocktake)
+}
+
+// GetStocktakeVariance
+
+type StocktakeVarianceFetcher interface {
+	GetStocktakeVariance(ctx context.Context, tenantID, id uuid.UUID) (*models.StocktakeVarianceReport, error)
+}
+
+func getStocktakeVarianceLogic(service StocktakeVarianceFetcher, tenantIDStr, idStr string) (*models.StocktakeVarianceReport, int, error) {
+	tenantID, id, err := parseTenantAndID(tenantIDStr, idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	report, err := service.GetStocktakeVariance(context.Background(), tenantID, id)
+	if err != nil {
+		status, err := stocktakeError(err, "failed to build variance report")
+		return nil, status, err
+	}
+
+	return report, int(http.StatusOK), nil
+}
+
+// GetStocktakeVariance godoc
+// @Summary Variance report of a stocktake, largest variances first
+// @Tags Stocktakes
+// @Produce json
+// @Param id path string true "Stocktake ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {object} models.StocktakeVarianceReport
+// @Router /stocktakes/{id}/variance [get]
+func GetStocktakeVariance(c *gin.Context) {
+	report, status, err := getStocktakeVarianceLogic(models.StocktakeModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, report)
+}
@@ -0,0 +1,330 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// CreateStocktake
+
+type mockStocktakeCreator struct {
+	CreateStocktakeFunc func(ctx context.Context, stocktake *models.Stocktake, skuIDs []uuid.UUID) error
+}
+
+func (m *mockStocktakeCreator) CreateStocktake(ctx context.Context, stocktake *models.Stocktake, skuIDs []uuid.UUID) error {
+	return m.CreateStocktakeFunc(ctx, stocktake, skuIDs)
+}
+
+func TestCreateStocktakeLogic(t *testing.T) {
+	tenantID := uuid.New()
+	req := CreateStocktakeRequest{HubID: uuid.New(), SkuIDs: []uuid.UUID{uuid.New()}}
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		mockFunc       func(ctx context.Context, stocktake *models.Stocktake, skuIDs []uuid.UUID) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "hub not found",
+			tenantID: tenantID.String(),
+			mockFunc: func(ctx context.Context, stocktake *models.Stocktake, skuIDs []uuid.UUID) error {
+				return gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "nothing to count",
+			tenantID: tenantID.String(),
+			mockFunc: func(ctx context.Context, stocktake *models.Stocktake, skuIDs []uuid.UUID) error {
+				return fmt.Errorf("%w: no inventory to count", models.ErrInvalidStocktake)
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "success",
+			tenantID: tenantID.String(),
+			mockFunc: func(ctx context.Context, stocktake *models.Stocktake, skuIDs []uuid.UUID) error {
+				assert.Equal(t, tenantID, stocktake.TenantID)
+				assert.Equal(t, "user-1", stocktake.CreatedBy)
+				assert.Len(t, skuIDs, 1)
+				stocktake.Status = models.StocktakeOpen
+				return nil
+			},
+			expectedStatus: int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStocktakeCreator{CreateStocktakeFunc: tt.mockFunc}
+			stocktake, status, err := createStocktakeLogic(mock, tt.tenantID, req, "user-1")
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.StocktakeOpen, stocktake.Status)
+			}
+		})
+	}
+}
+
+// RecordStocktakeCounts
+
+type mockStocktakeCounter struct {
+	RecordStocktakeCountsFunc func(ctx context.Context, tenantID, id uuid.UUID, counts map[uuid.UUID]int) error
+}
+
+func (m *mockStocktakeCounter) RecordStocktakeCounts(ctx context.Context, tenantID, id uuid.UUID, counts map[uuid.UUID]int) error {
+	return m.RecordStocktakeCountsFunc(ctx, tenantID, id, counts)
+}
+
+func TestRecordStocktakeCountsLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	id := uuid.New().String()
+	skuID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		req            RecordStocktakeCountsRequest
+		mockFunc       func(ctx context.Context, tenantID, id uuid.UUID, counts map[uuid.UUID]int) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid id",
+			id:             "bad-uuid",
+			req:            RecordStocktakeCountsRequest{Counts: []StocktakeCount{{SkuID: skuID, CountedQuantity: 1}}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "no counts",
+			id:             id,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "negative count",
+			id:             id,
+			req:            RecordStocktakeCountsRequest{Counts: []StocktakeCount{{SkuID: skuID, CountedQuantity: -1}}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "stocktake approved already",
+			id:   id,
+			req:  RecordStocktakeCountsRequest{Counts: []StocktakeCount{{SkuID: skuID, CountedQuantity: 4}}},
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, counts map[uuid.UUID]int) error {
+				return models.ErrStocktakeClosed
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "not found",
+			id:   id,
+			req:  RecordStocktakeCountsRequest{Counts: []StocktakeCount{{SkuID: skuID, CountedQuantity: 4}}},
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, counts map[uuid.UUID]int) error {
+				return gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name: "success",
+			id:   id,
+			req:  RecordStocktakeCountsRequest{Counts: []StocktakeCount{{SkuID: skuID, CountedQuantity: 0}}},
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, counts map[uuid.UUID]int) error {
+				counted, ok := counts[skuID]
+				assert.True(t, ok)
+				assert.Equal(t, 0, counted)
+				return nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStocktakeCounter{RecordStocktakeCountsFunc: tt.mockFunc}
+			status, err := recordStocktakeCountsLogic(mock, tenantID, tt.id, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// ApproveStocktake
+
+type mockStocktakeApprover struct {
+	ApproveStocktakeFunc func(ctx context.Context, tenantID, id uuid.UUID) (*models.Stocktake, error)
+}
+
+func (m *mockStocktakeApprover) ApproveStocktake(ctx context.Context, tenantID, id uuid.UUID) (*models.Stocktake, error) {
+	return m.ApproveStocktakeFunc(ctx, tenantID, id)
+}
+
+func TestApproveStocktakeLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	id := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		actor          string
+		mockFunc       func(ctx context.Context, tenantID, id uuid.UUID) (*models.Stocktake, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "missing approver",
+			actor:          "",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "approved by its creator",
+			actor: "user-2",
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.Stocktake, error) {
+				return nil, models.ErrStocktakeSelfApproval
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "tenant's stocktake code refuses the direction",
+			actor: "user-2",
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.Stocktake, error) {
+				return nil, models.ErrInvalidAdjustment
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "already approved",
+			actor: "user-2",
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.Stocktake, error) {
+				return nil, models.ErrStocktakeClosed
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "db error",
+			actor: "user-2",
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.Stocktake, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:  "success",
+			actor: "user-2",
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.Stocktake, error) {
+				return &models.Stocktake{ID: id, Status: models.StocktakeApproved, ApprovedBy: "user-2"}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStocktakeApprover{ApproveStocktakeFunc: tt.mockFunc}
+			stocktake, status, err := approveStocktakeLogic(mock, tenantID, id, models.MovementMeta{Actor: tt.actor})
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.StocktakeApproved, stocktake.Status)
+			}
+		})
+	}
+}
+
+// GetStocktakeVariance
+
+type mockStocktakeVarianceFetcher struct {
+	GetStocktakeVarianceFunc func(ctx context.Context, tenantID, id uuid.UUID) (*models.StocktakeVarianceReport, error)
+}
+
+func (m *mockStocktakeVarianceFetcher) GetStocktakeVariance(ctx context.Context, tenantID, id uuid.UUID) (*models.StocktakeVarianceReport, error) {
+	return m.GetStocktakeVarianceFunc(ctx, tenantID, id)
+}
+
+func TestGetStocktakeVarianceLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		id             string
+		mockFunc       func(ctx context.Context, tenantID, id uuid.UUID) (*models.StocktakeVarianceReport, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid id",
+			id:             "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "not found",
+			id:   uuid.New().String(),
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.StocktakeVarianceReport, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name: "success",
+			id:   uuid.New().String(),
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.StocktakeVarianceReport, error) {
+				return &models.StocktakeVarianceReport{StocktakeID: id, LinesCounted: 2, NetVariance: -3}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStocktakeVarianceFetcher{GetStocktakeVarianceFunc: tt.mockFunc}
+			_, status, err := getStocktakeVarianceLogic(mock, tenantID, tt.id)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
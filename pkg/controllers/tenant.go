@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"context"
+	"errors"
 
 	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/aditya-goyal-omniful/ims/pkg/sourcing"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/omniful/go_commons/http"
@@ -91,7 +93,33 @@ type TenantCreator interface {
 	CreateTenant(ctx context.Context, tenant *models.Tenant) error
 }
 
+var (
+	errUnknownSourcingStrategy   = errors.New("unknown sourcing_strategy")
+	errInvalidApprovalThresholds = errors.New("adjustment approval thresholds must not be negative")
+	errUnknownValuationMethod    = errors.New("unknown valuation_method, expected fifo or weighted_average")
+)
+
+// validateTenantSettings checks the configurable tenant settings; its errors
+// are safe to show to the caller.
+func validateTenantSettings(tenant *models.Tenant) error {
+	if tenant.SourcingStrategy != "" && !sourcing.IsStrategy(tenant.SourcingStrategy) {
+		return errUnknownSourcingStrategy
+	}
+	if (tenant.AdjustmentApprovalUnits != nil && *tenant.AdjustmentApprovalUnits < 0) ||
+		(tenant.AdjustmentApprovalPercent != nil && *tenant.AdjustmentApprovalPercent < 0) {
+		return errInvalidApprovalThresholds
+	}
+	if tenant.ValuationMethod != "" && !models.IsValuationMethod(tenant.ValuationMethod) {
+		return errUnknownValuationMethod
+	}
+	return nil
+}
+
 func createTenantLogic(service TenantCreator, tenant *models.Tenant) (int, error) {
+	if err := validateTenantSettings(tenant); err != nil {
+		return int(http.StatusBadRequest), err
+	}
+
 	err := service.CreateTenant(context.Background(), tenant)
 	if err != nil {
 		return int(http.StatusInternalServerError), err
@@ -181,6 +209,10 @@ func updateTenantLogic(service TenantUpdater, idStr string, updated *models.Tena
 		return nil, int(http.StatusBadRequest), err
 	}
 
+	if err := validateTenantSettings(updated); err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
 	err = service.UpdateTenant(context.Background(), id, updated)
 	if err != nil {
 		return nil, int(http.StatusInternalServerError), err
@@ -216,7 +248,10 @@ func UpdateTenant(c *gin.Context) {
 	updatedTenant, status, err := updateTenantLogic(models.TenantModel{}, idStr, &tenant)
 	if err != nil {
 		msg := "Error updating tenant"
-		if status ==int(http.StatusBadRequest) {
+		if errors.Is(err, errUnknownSourcingStrategy) || errors.Is(err, errInvalidApprovalThresholds) ||
+			errors.Is(err, errUnknownValuationMethod) {
+			msg = err.Error()
+		} else if status ==int(http.StatusBadRequest) {
 			msg = "Invalid Tenant ID"
 		}
 		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, msg)})
@@ -152,6 +152,24 @@ func TestCreateTenantLogic(t *testing.T) {
 			expectedStatus: http.StatusCreated,
 			expectErr:      false,
 		},
+		{
+			name: "unknown sourcing strategy",
+			input: &models.Tenant{
+				Name:             "TestTenant",
+				SourcingStrategy: "cheapest",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectErr:      true,
+		},
+		{
+			name: "unknown valuation method",
+			input: &models.Tenant{
+				Name:            "TestTenant",
+				ValuationMethod: "lifo",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectErr:      true,
+		},
 		{
 			name: "creation failed",
 			input: &models.Tenant{
@@ -283,6 +301,27 @@ func TestUpdateTenantLogic(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectErr:      true,
 		},
+		{
+			name:           "unknown sourcing strategy",
+			idStr:          validID.String(),
+			input:          &models.Tenant{SourcingStrategy: "cheapest"},
+			expectedStatus: http.StatusBadRequest,
+			expectErr:      true,
+		},
+		{
+			name:           "negative approval threshold",
+			idStr:          validID.String(),
+			input:          &models.Tenant{AdjustmentApprovalUnits: intPtr(-1)},
+			expectedStatus: http.StatusBadRequest,
+			expectErr:      true,
+		},
+		{
+			name:           "unknown valuation method",
+			idStr:          validID.String(),
+			input:          &models.Tenant{ValuationMethod: "lifo"},
+			expectedStatus: http.StatusBadRequest,
+			expectErr:      true,
+		},
 		{
 			name:  "update failed",
 			idStr: validID.String(),
@@ -0,0 +1,330 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type TransferLineRequest struct {
+	SkuID    uuid.UUID `json:"sku_id" binding:"required"`
+	Quantity int       `json:"quantity" binding:"required"`
+}
+
+type CreateTransferRequest struct {
+	SourceHubID      uuid.UUID             `json:"source_hub_id" binding:"required"`
+	DestinationHubID uuid.UUID             `json:"destination_hub_id" binding:"required"`
+	Lines            []TransferLineRequest `json:"lines" binding:"required"`
+	ExpectedAt       string                `json:"expected_at" example:"2025-01-31T00:00:00Z"` // RFC3339, optional
+}
+
+type ReceivedLineRequest struct {
+	SkuID            uuid.UUID `json:"sku_id" binding:"required"`
+	ReceivedQuantity int       `json:"received_quantity"`
+}
+
+type ReceiveTransferRequest struct {
+	Lines []ReceivedLineRequest `json:"lines"`
+}
+
+var transferStatuses = map[string]bool{
+	models.TransferCreated:    true,
+	models.TransferDispatched: true,
+	models.TransferReceived:   true,
+}
+
+// transferError maps model errors shared by the transfer endpoints.
+func transferError(err error, fallback string) (int, error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return int(http.StatusNotFound), errors.New("transfer order not found")
+	case errors.Is(err, models.ErrInvalidTransfer),
+		errors.Is(err, models.ErrTransferState),
+		errors.Is(err, models.ErrInsufficientStock),
+		errors.Is(err, models.ErrSerializedSku),
+		errors.Is(err, models.ErrBundleSku):
+		return int(http.StatusBadRequest), err
+	default:
+		return int(http.StatusInternalServerError), errors.New(fallback)
+	}
+}
+
+// CreateTransferOrder
+
+type TransferCreator interface {
+	CreateTransferOrder(ctx context.Context, order *models.TransferOrder) error
+}
+
+func createTransferOrderLogic(service TransferCreator, tenantIDStr string, req CreateTransferRequest) (*models.TransferOrder, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	if req.SourceHubID == req.DestinationHubID {
+		return nil, int(http.StatusBadRequest), errors.New("source and destination hub must differ")
+	}
+
+	if len(req.Lines) == 0 {
+		return nil, int(http.StatusBadRequest), errors.New("at least one line is required")
+	}
+
+	expectedAt, err := parseOptionalTime(req.ExpectedAt, "expected_at")
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+
+	lines := make([]models.TransferOrderLine, 0, len(req.Lines))
+	seen := make(map[uuid.UUID]bool, len(req.Lines))
+	for _, line := range req.Lines {
+		if line.Quantity <= 0 {
+			return nil, int(http.StatusBadRequest), errors.New("quantity must be positive")
+		}
+		if seen[line.SkuID] {
+			return nil, int(http.StatusBadRequest), errors.New("duplicate sku_id in lines")
+		}
+		seen[line.SkuID] = true
+		lines = append(lines, models.TransferOrderLine{SkuID: line.SkuID, Quantity: line.Quantity})
+	}
+
+	order := &models.TransferOrder{
+		TenantID:         tenantID,
+		SourceHubID:      req.SourceHubID,
+		DestinationHubID: req.DestinationHubID,
+		ExpectedAt:       expectedAt,
+		Lines:            lines,
+	}
+
+	if err := service.CreateTransferOrder(context.Background(), order); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusBadRequest), errors.New("hub not found")
+		}
+		if errors.Is(err, models.ErrInvalidTransfer) {
+			return nil, int(http.StatusBadRequest), errors.New("hubs and skus must belong to the tenant")
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to create transfer order")
+	}
+
+	return order, int(http.StatusCreated), nil
+}
+
+// CreateTransferOrder godoc
+// @Summary Create a transfer order between two hubs
+// @Tags Transfers
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body CreateTransferRequest true "Transfer order"
+// @Success 201 {object} models.TransferOrder
+// @Router /transfers [post]
+func CreateTransferOrder(c *gin.Context) {
+	var req CreateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	order, status, err := createTransferOrderLogic(models.TransferModel{}, c.GetHeader("X-Tenant-ID"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, order)
+}
+
+// GetTransferOrders
+
+type TransferFetcher interface {
+	GetTransferOrders(ctx context.Context, tenantID uuid.UUID, status string) ([]models.TransferOrder, error)
+	GetTransferOrder(ctx context.Context, tenantID, id uuid.UUID) (*models.TransferOrder, error)
+}
+
+func getTransferOrdersLogic(service TransferFetcher, tenantIDStr, status string) ([]models.TransferOrder, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	if status != "" && !transferStatuses[status] {
+		return nil, int(http.StatusBadRequest), errors.New("status must be created, dispatched or received")
+	}
+
+	orders, err := service.GetTransferOrders(context.Background(), tenantID, status)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch transfer orders")
+	}
+
+	return orders, int(http.StatusOK), nil
+}
+
+// GetTransferOrders godoc
+// @Summary List the tenant's transfer orders, newest first
+// @Tags Transfers
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param status query string false "created, dispatched or received"
+// @Success 200 {array} models.TransferOrder
+// @Router /transfers [get]
+func GetTransferOrders(c *gin.Context) {
+	orders, status, err := getTransferOrdersLogic(models.TransferModel{}, c.GetHeader("X-Tenant-ID"), c.Query("status"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, orders)
+}
+
+// GetTransferOrderByID
+
+func getTransferOrderByIDLogic(service TransferFetcher, tenantIDStr, idStr string) (*models.TransferOrder, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid transfer id")
+	}
+
+	order, err := service.GetTransferOrder(context.Background(), tenantID, id)
+	if err != nil {
+		status, err := transferError(err, "failed to fetch transfer order")
+		return nil, status, err
+	}
+
+	return order, int(http.StatusOK), nil
+}
+
+// GetTransferOrderByID godoc
+// @Summary Get a transfer order with its lines
+// @Tags Transfers
+// @Produce json
+// @Param id path string true "Transfer order ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {object} models.TransferOrder
+// @Router /transfers/{id} [get]
+func GetTransferOrderByID(c *gin.Context) {
+	order, status, err := getTransferOrderByIDLogic(models.TransferModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, order)
+}
+
+// DispatchTransferOrder
+
+type TransferDispatcher interface {
+	DispatchTransferOrder(ctx context.Context, tenantID, id uuid.UUID) (*models.TransferOrder, error)
+}
+
+func dispatchTransferOrderLogic(service TransferDispatcher, tenantIDStr, idStr string, meta models.MovementMeta) (*models.TransferOrder, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid transfer id")
+	}
+
+	order, err := service.DispatchTransferOrder(models.WithMovementMeta(context.Background(), meta), tenantID, id)
+	if err != nil {
+		status, err := transferError(err, "failed to dispatch transfer order")
+		return nil, status, err
+	}
+
+	return order, int(http.StatusOK), nil
+}
+
+// DispatchTransferOrder godoc
+// @Summary Dispatch a transfer, moving its lines from the source hub's sellable stock to in-transit
+// @Tags Transfers
+// @Produce json
+// @Param id path string true "Transfer order ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {object} models.TransferOrder
+// @Router /transfers/{id}/dispatch [post]
+func DispatchTransferOrder(c *gin.Context) {
+	order, status, err := dispatchTransferOrderLogic(models.TransferModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"), movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, order)
+}
+
+// ReceiveTransferOrder
+
+type TransferReceiver interface {
+	ReceiveTransferOrder(ctx context.Context, tenantID, id uuid.UUID, received map[uuid.UUID]int) (*models.TransferOrder, error)
+}
+
+func receiveTransferOrderLogic(service TransferReceiver, tenantIDStr, idStr string, req ReceiveTransferRequest, meta models.MovementMeta) (*models.TransferOrder, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid transfer id")
+	}
+
+	received := make(map[uuid.UUID]int, len(req.Lines))
+	for _, line := range req.Lines {
+		if line.ReceivedQuantity < 0 {
+			return nil, int(http.StatusBadRequest), errors.New("received_quantity must not be negative")
+		}
+		if _, dup := received[line.SkuID]; dup {
+			return nil, int(http.StatusBadRequest), errors.New("duplicate sku_id in lines")
+		}
+		received[line.SkuID] = line.ReceivedQuantity
+	}
+
+	order, err := service.ReceiveTransferOrder(models.WithMovementMeta(context.Background(), meta), tenantID, id, received)
+	if err != nil {
+		status, err := transferError(err, "failed to receive transfer order")
+		return nil, status, err
+	}
+
+	return order, int(http.StatusOK), nil
+}
+
+// ReceiveTransferOrder godoc
+// @Summary Receive a dispatched transfer at the destination hub; short lines are recorded as discrepancies
+// @Tags Transfers
+// @Accept json
+// @Produce json
+// @Param id path string true "Transfer order ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param payload body ReceiveTransferRequest true "Received quantities per SKU"
+// @Success 200 {object} models.TransferOrder
+// @Router /transfers/{id}/receive [post]
+func ReceiveTransferOrder(c *gin.Context) {
+	var req ReceiveTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request payload")})
+		return
+	}
+
+	order, status, err := receiveTransferOrderLogic(models.TransferModel{}, c.GetHeader("X-Tenant-ID"), c.Param("id"), req, movementMetaFromRequest(c))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, order)
+}
@@ -0,0 +1,384 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// CreateTransferOrder
+
+type mockTransferCreator struct {
+	CreateTransferOrderFunc func(ctx context.Context, order *models.TransferOrder) error
+}
+
+func (m *mockTransferCreator) CreateTransferOrder(ctx context.Context, order *models.TransferOrder) error {
+	return m.CreateTransferOrderFunc(ctx, order)
+}
+
+func TestCreateTransferOrderLogic(t *testing.T) {
+	tenantID := uuid.New()
+	source := uuid.New()
+	destination := uuid.New()
+	skuID := uuid.New()
+	valid := CreateTransferRequest{
+		SourceHubID:      source,
+		DestinationHubID: destination,
+		Lines:            []TransferLineRequest{{SkuID: skuID, Quantity: 5}},
+	}
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		req            CreateTransferRequest
+		mockFunc       func(ctx context.Context, order *models.TransferOrder) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad-uuid",
+			req:            valid,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "same hub",
+			tenantID:       tenantID.String(),
+			req:            CreateTransferRequest{SourceHubID: source, DestinationHubID: source, Lines: valid.Lines},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "no lines",
+			tenantID:       tenantID.String(),
+			req:            CreateTransferRequest{SourceHubID: source, DestinationHubID: destination},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "duplicate sku",
+			tenantID: tenantID.String(),
+			req: CreateTransferRequest{
+				SourceHubID:      source,
+				DestinationHubID: destination,
+				Lines:            []TransferLineRequest{{SkuID: skuID, Quantity: 1}, {SkuID: skuID, Quantity: 2}},
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "invalid expected_at",
+			tenantID: tenantID.String(),
+			req: CreateTransferRequest{
+				SourceHubID:      source,
+				DestinationHubID: destination,
+				Lines:            valid.Lines,
+				ExpectedAt:       "tomorrow",
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "hub of another tenant",
+			tenantID: tenantID.String(),
+			req:      valid,
+			mockFunc: func(ctx context.Context, order *models.TransferOrder) error {
+				return models.ErrInvalidTransfer
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "db error",
+			tenantID: tenantID.String(),
+			req:      valid,
+			mockFunc: func(ctx context.Context, order *models.TransferOrder) error {
+				return errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:     "success",
+			tenantID: tenantID.String(),
+			req:      valid,
+			mockFunc: func(ctx context.Context, order *models.TransferOrder) error {
+				assert.Equal(t, tenantID, order.TenantID)
+				assert.Len(t, order.Lines, 1)
+				order.Status = models.TransferCreated
+				return nil
+			},
+			expectedStatus: int(http.StatusCreated),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTransferCreator{CreateTransferOrderFunc: tt.mockFunc}
+			order, status, err := createTransferOrderLogic(mock, tt.tenantID, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.TransferCreated, order.Status)
+			}
+		})
+	}
+}
+
+// GetTransferOrders
+
+type mockTransferFetcher struct {
+	GetTransferOrdersFunc func(ctx context.Context, tenantID uuid.UUID, status string) ([]models.TransferOrder, error)
+	GetTransferOrderFunc  func(ctx context.Context, tenantID, id uuid.UUID) (*models.TransferOrder, error)
+}
+
+func (m *mockTransferFetcher) GetTransferOrders(ctx context.Context, tenantID uuid.UUID, status string) ([]models.TransferOrder, error) {
+	return m.GetTransferOrdersFunc(ctx, tenantID, status)
+}
+
+func (m *mockTransferFetcher) GetTransferOrder(ctx context.Context, tenantID, id uuid.UUID) (*models.TransferOrder, error) {
+	return m.GetTransferOrderFunc(ctx, tenantID, id)
+}
+
+func TestGetTransferOrdersLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		status         string
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, status string) ([]models.TransferOrder, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "unknown status",
+			tenantID:       tenantID,
+			status:         "lost",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "filtered by status",
+			tenantID: tenantID,
+			status:   models.TransferDispatched,
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, status string) ([]models.TransferOrder, error) {
+				assert.Equal(t, models.TransferDispatched, status)
+				return []models.TransferOrder{{Status: status}}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:     "db error",
+			tenantID: tenantID,
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, status string) ([]models.TransferOrder, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTransferFetcher{GetTransferOrdersFunc: tt.mockFunc}
+			_, status, err := getTransferOrdersLogic(mock, tt.tenantID, tt.status)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// DispatchTransferOrder
+
+type mockTransferDispatcher struct {
+	DispatchTransferOrderFunc func(ctx context.Context, tenantID, id uuid.UUID) (*models.TransferOrder, error)
+}
+
+func (m *mockTransferDispatcher) DispatchTransferOrder(ctx context.Context, tenantID, id uuid.UUID) (*models.TransferOrder, error) {
+	return m.DispatchTransferOrderFunc(ctx, tenantID, id)
+}
+
+func TestDispatchTransferOrderLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	id := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		id             string
+		mockFunc       func(ctx context.Context, tenantID, id uuid.UUID) (*models.TransferOrder, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid id",
+			id:             "bad-uuid",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "not found",
+			id:   id,
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.TransferOrder, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name: "already dispatched",
+			id:   id,
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.TransferOrder, error) {
+				return nil, models.ErrTransferState
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "source short",
+			id:   id,
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.TransferOrder, error) {
+				return nil, fmt.Errorf("%w for sku %s", models.ErrInsufficientStock, uuid.New())
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "db error",
+			id:   id,
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.TransferOrder, error) {
+				return nil, errors.New("db error")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name: "success",
+			id:   id,
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID) (*models.TransferOrder, error) {
+				return &models.TransferOrder{ID: id, Status: models.TransferDispatched}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTransferDispatcher{DispatchTransferOrderFunc: tt.mockFunc}
+			order, status, err := dispatchTransferOrderLogic(mock, tenantID, tt.id, models.MovementMeta{})
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.TransferDispatched, order.Status)
+			}
+		})
+	}
+}
+
+// ReceiveTransferOrder
+
+type mockTransferReceiver struct {
+	ReceiveTransferOrderFunc func(ctx context.Context, tenantID, id uuid.UUID, received map[uuid.UUID]int) (*models.TransferOrder, error)
+}
+
+func (m *mockTransferReceiver) ReceiveTransferOrder(ctx context.Context, tenantID, id uuid.UUID, received map[uuid.UUID]int) (*models.TransferOrder, error) {
+	return m.ReceiveTransferOrderFunc(ctx, tenantID, id, received)
+}
+
+func TestReceiveTransferOrderLogic(t *testing.T) {
+	tenantID := uuid.New().String()
+	id := uuid.New().String()
+	skuID := uuid.New()
+
+	tests := []struct {
+		name           string
+		req            ReceiveTransferRequest
+		mockFunc       func(ctx context.Context, tenantID, id uuid.UUID, received map[uuid.UUID]int) (*models.TransferOrder, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "negative quantity",
+			req:            ReceiveTransferRequest{Lines: []ReceivedLineRequest{{SkuID: skuID, ReceivedQuantity: -1}}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "duplicate sku",
+			req:            ReceiveTransferRequest{Lines: []ReceivedLineRequest{{SkuID: skuID, ReceivedQuantity: 1}, {SkuID: skuID, ReceivedQuantity: 1}}},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "not dispatched",
+			req:  ReceiveTransferRequest{Lines: []ReceivedLineRequest{{SkuID: skuID, ReceivedQuantity: 5}}},
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, received map[uuid.UUID]int) (*models.TransferOrder, error) {
+				return nil, models.ErrTransferState
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "over receipt",
+			req:  ReceiveTransferRequest{Lines: []ReceivedLineRequest{{SkuID: skuID, ReceivedQuantity: 50}}},
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, received map[uuid.UUID]int) (*models.TransferOrder, error) {
+				return nil, fmt.Errorf("%w: received more than dispatched", models.ErrInvalidTransfer)
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name: "short receipt",
+			req:  ReceiveTransferRequest{Lines: []ReceivedLineRequest{{SkuID: skuID, ReceivedQuantity: 3}}},
+			mockFunc: func(ctx context.Context, tenantID, id uuid.UUID, received map[uuid.UUID]int) (*models.TransferOrder, error) {
+				assert.Equal(t, 3, received[skuID])
+				return &models.TransferOrder{
+					Status:         models.TransferReceived,
+					HasDiscrepancy: true,
+					Lines:          []models.TransferOrderLine{{SkuID: skuID, Quantity: 5, ReceivedQuantity: 3, Discrepancy: 2}},
+				}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTransferReceiver{ReceiveTransferOrderFunc: tt.mockFunc}
+			order, status, err := receiveTransferOrderLogic(mock, tenantID, id, tt.req, models.MovementMeta{})
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, order.HasDiscrepancy)
+			}
+		})
+	}
+}
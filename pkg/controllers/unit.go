@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+type SkuUnitRequest struct {
+	Unit         string `json:"unit" binding:"required" example:"case"`
+	UnitQuantity int    `json:"unit_quantity" example:"1"` // defaults to 1
+	BaseQuantity int    `json:"base_quantity" binding:"required" example:"24"`
+}
+
+// isUnitError reports whether err is a quantity whose unit could not be
+// converted to the SKU's base unit.
+func isUnitError(err error) bool {
+	return errors.Is(err, models.ErrUnknownUnit) || errors.Is(err, models.ErrNonIntegralConversion)
+}
+
+// GetSkuUnits
+
+type SkuUnitFetcher interface {
+	GetSkuUnits(ctx context.Context, skuID uuid.UUID) ([]models.SkuUnit, error)
+}
+
+func getSkuUnitsLogic(service SkuUnitFetcher, idStr string) ([]models.SkuUnit, int, error) {
+	skuID, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid sku id")
+	}
+
+	units, err := service.GetSkuUnits(context.Background(), skuID)
+	if err != nil {
+		return nil, int(http.StatusInternalServerError), errors.New("failed to fetch units")
+	}
+
+	return units, int(http.StatusOK), nil
+}
+
+// GetSkuUnits godoc
+// @Summary List the pack-size conversions of a SKU to its base unit
+// @Tags SKUs
+// @Produce json
+// @Param id path string true "SKU ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Success 200 {array} models.SkuUnit
+// @Router /skus/{id}/units [get]
+func GetSkuUnits(c *gin.Context) {
+	units, status, err := getSkuUnitsLogic(models.UnitModel{}, c.Param("id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, units)
+}
+
+// SetSkuUnit
+
+type SkuUnitSetter interface {
+	SetSkuUnit(ctx context.Context, unit *models.SkuUnit) error
+}
+
+func setSkuUnitLogic(service SkuUnitSetter, idStr string, req SkuUnitRequest) (*models.SkuUnit, int, error) {
+	skuID, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid sku id")
+	}
+
+	unit := strings.TrimSpace(req.Unit)
+	if unit == "" {
+		return nil, int(http.StatusBadRequest), errors.New("unit is required")
+	}
+	if req.UnitQuantity < 0 || req.BaseQuantity <= 0 {
+		return nil, int(http.StatusBadRequest), errors.New("unit_quantity and base_quantity must be positive")
+	}
+
+	skuUnit := &models.SkuUnit{
+		SkuID:        skuID,
+		Unit:         unit,
+		UnitQuantity: req.UnitQuantity,
+		BaseQuantity: req.BaseQuantity,
+	}
+	if err := service.SetSkuUnit(context.Background(), skuUnit); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("sku not found")
+		}
+		if errors.Is(err, models.ErrInvalidUnit) {
+			return nil, int(http.StatusBadRequest), err
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to save unit")
+	}
+
+	return skuUnit, int(http.StatusOK), nil
+}
+
+// SetSkuUnit godoc
+// @Summary Add or replace a pack-size conversion of a SKU, e.g. 1 case = 24 each
+// @Tags SKUs
+// @Accept json
+// @Produce json
+// @Param id path string true "SKU ID"
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param unit body SkuUnitRequest true "unit_quantity of unit equal base_quantity base units"
+// @Success 200 {object} models.SkuUnit
+// @Router /skus/{id}/units [post]
+func SetSkuUnit(c *gin.Context) {
+	var req SkuUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(int(http.StatusBadRequest), gin.H{i18n.Translate(c, "error"): i18n.Translate(c, "Invalid request body")})
+		return
+	}
+
+	unit, status, err := setSkuUnitLogic(models.UnitModel{}, c.Param("id"), req)
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, unit)
+}
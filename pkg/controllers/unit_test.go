@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockSkuUnitSetter struct {
+	SetSkuUnitFunc func(ctx context.Context, unit *models.SkuUnit) error
+}
+
+func (m *mockSkuUnitSetter) SetSkuUnit(ctx context.Context, unit *models.SkuUnit) error {
+	return m.SetSkuUnitFunc(ctx, unit)
+}
+
+func TestSetSkuUnitLogic(t *testing.T) {
+	skuID := uuid.New().String()
+	caseOf24 := SkuUnitRequest{Unit: "case", BaseQuantity: 24}
+
+	tests := []struct {
+		name           string
+		skuID          string
+		req            SkuUnitRequest
+		mockFunc       func(ctx context.Context, unit *models.SkuUnit) error
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid sku id",
+			skuID:          "bad",
+			req:            caseOf24,
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "blank unit",
+			skuID:          skuID,
+			req:            SkuUnitRequest{Unit: "  ", BaseQuantity: 24},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "non-positive base quantity",
+			skuID:          skuID,
+			req:            SkuUnitRequest{Unit: "case", BaseQuantity: -24},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "sku not found",
+			skuID: skuID,
+			req:   caseOf24,
+			mockFunc: func(ctx context.Context, unit *models.SkuUnit) error {
+				return gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:  "conversion of the base unit",
+			skuID: skuID,
+			req:   SkuUnitRequest{Unit: "each", BaseQuantity: 1},
+			mockFunc: func(ctx context.Context, unit *models.SkuUnit) error {
+				return fmt.Errorf("%w: each is the base unit", models.ErrInvalidUnit)
+			},
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:  "saved",
+			skuID: skuID,
+			req:   caseOf24,
+			mockFunc: func(ctx context.Context, unit *models.SkuUnit) error {
+				unit.UnitQuantity = 1
+				return nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSkuUnitSetter{SetSkuUnitFunc: tt.mockFunc}
+			unit, status, err := setSkuUnitLogic(mock, tt.skuID, tt.req)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "case", unit.Unit)
+				assert.Equal(t, 24, unit.BaseQuantity)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/omniful/go_commons/i18n"
+	"gorm.io/gorm"
+)
+
+// GetValuation
+
+type ValuationFetcher interface {
+	GetValuation(ctx context.Context, tenantID uuid.UUID, asOf time.Time, hubID *uuid.UUID) (*models.ValuationReport, error)
+}
+
+func getValuationLogic(service ValuationFetcher, tenantIDStr, asOfStr, hubIDStr string) (*models.ValuationReport, int, error) {
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), errors.New("invalid tenant_id in header")
+	}
+
+	asOf := time.Now()
+	date, err := parseAsOf(asOfStr)
+	if err != nil {
+		return nil, int(http.StatusBadRequest), err
+	}
+	if date != nil {
+		asOf = *date
+	}
+
+	var hubID *uuid.UUID
+	if hubIDStr != "" {
+		id, err := uuid.Parse(hubIDStr)
+		if err != nil {
+			return nil, int(http.StatusBadRequest), errors.New("invalid hub_id")
+		}
+		hubID = &id
+	}
+
+	report, err := service.GetValuation(context.Background(), tenantID, asOf, hubID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, int(http.StatusNotFound), errors.New("tenant not found")
+		}
+		return nil, int(http.StatusInternalServerError), errors.New("failed to value inventory")
+	}
+
+	return report, int(http.StatusOK), nil
+}
+
+// GetValuation godoc
+// @Summary Value sellable stock per SKU, hub and tenant from its cost layers
+// @Tags Valuation
+// @Produce json
+// @Param X-Tenant-ID header string true "Tenant ID"
+// @Param as_of query string false "Value at the end of this day, YYYY-MM-DD; defaults to now"
+// @Param hub_id query string false "Only value this hub"
+// @Success 200 {object} models.ValuationReport
+// @Router /valuation [get]
+func GetValuation(c *gin.Context) {
+	report, status, err := getValuationLogic(models.ValuationModel{}, c.GetHeader("X-Tenant-ID"), c.Query("as_of"), c.Query("hub_id"))
+	if err != nil {
+		c.JSON(status, gin.H{i18n.Translate(c, "error"): i18n.Translate(c, err.Error())})
+		return
+	}
+
+	c.JSON(status, report)
+}
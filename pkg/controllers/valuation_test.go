@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/http"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockValuationService struct {
+	GetValuationFunc func(ctx context.Context, tenantID uuid.UUID, asOf time.Time, hubID *uuid.UUID) (*models.ValuationReport, error)
+}
+
+func (m *mockValuationService) GetValuation(ctx context.Context, tenantID uuid.UUID, asOf time.Time, hubID *uuid.UUID) (*models.ValuationReport, error) {
+	return m.GetValuationFunc(ctx, tenantID, asOf, hubID)
+}
+
+func TestGetValuationLogic(t *testing.T) {
+	tenantID := uuid.New()
+	hubID := uuid.New()
+
+	tests := []struct {
+		name           string
+		tenantID       string
+		asOf           string
+		hubID          string
+		mockFunc       func(ctx context.Context, tenantID uuid.UUID, asOf time.Time, hubID *uuid.UUID) (*models.ValuationReport, error)
+		expectedStatus int
+		expectErr      bool
+	}{
+		{
+			name:           "invalid tenant id",
+			tenantID:       "bad",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid as_of",
+			tenantID:       tenantID.String(),
+			asOf:           "31-01-2026",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:           "invalid hub id",
+			tenantID:       tenantID.String(),
+			hubID:          "bad",
+			expectedStatus: int(http.StatusBadRequest),
+			expectErr:      true,
+		},
+		{
+			name:     "tenant not found",
+			tenantID: tenantID.String(),
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, asOf time.Time, hubID *uuid.UUID) (*models.ValuationReport, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+			expectedStatus: int(http.StatusNotFound),
+			expectErr:      true,
+		},
+		{
+			name:     "db error",
+			tenantID: tenantID.String(),
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, asOf time.Time, hubID *uuid.UUID) (*models.ValuationReport, error) {
+				return nil, errors.New("db down")
+			},
+			expectedStatus: int(http.StatusInternalServerError),
+			expectErr:      true,
+		},
+		{
+			name:     "date values the end of the day",
+			tenantID: tenantID.String(),
+			asOf:     "2026-01-31",
+			hubID:    hubID.String(),
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, asOf time.Time, hub *uuid.UUID) (*models.ValuationReport, error) {
+				if !asOf.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) || hub == nil || *hub != hubID {
+					return nil, errors.New("unexpected filter")
+				}
+				return &models.ValuationReport{TenantID: tenantID, AsOf: asOf, Value: 125.5}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+		{
+			name:     "defaults to now",
+			tenantID: tenantID.String(),
+			mockFunc: func(ctx context.Context, tenantID uuid.UUID, asOf time.Time, hub *uuid.UUID) (*models.ValuationReport, error) {
+				if time.Since(asOf) > time.Minute || hub != nil {
+					return nil, errors.New("unexpected filter")
+				}
+				return &models.ValuationReport{TenantID: tenantID, AsOf: asOf}, nil
+			},
+			expectedStatus: int(http.StatusOK),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockValuationService{GetValuationFunc: tt.mockFunc}
+			report, status, err := getValuationLogic(mock, tt.tenantID, tt.asOf, tt.hubID)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tenantID, report.TenantID)
+			}
+		})
+	}
+}
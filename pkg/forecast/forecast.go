@@ -0,0 +1,56 @@
+// Package forecast turns a daily demand history into a demand rate and a
+// reorder suggestion. It only works on the series it is given; reading the
+// ledger and stock positions is up to the caller.
+package forecast
+
+import "math"
+
+// MovingAverage is the mean daily demand over the last window days of
+// daily, or over all of it when it is shorter.
+func MovingAverage(daily []int, window int) float64 {
+	if window <= 0 || len(daily) == 0 {
+		return 0
+	}
+	if window > len(daily) {
+		window = len(daily)
+	}
+
+	total := 0
+	for _, quantity := range daily[len(daily)-window:] {
+		total += quantity
+	}
+	return float64(total) / float64(window)
+}
+
+// ExponentialSmoothing is the simple exponentially smoothed daily demand,
+// oldest day first. It starts from the first day, and each later day moves
+// the level by alpha of the gap to it, so recent days weigh the most.
+func ExponentialSmoothing(daily []int, alpha float64) float64 {
+	if len(daily) == 0 {
+		return 0
+	}
+
+	level := float64(daily[0])
+	for _, quantity := range daily[1:] {
+		level += alpha * (float64(quantity) - level)
+	}
+	return level
+}
+
+// Position is the stock a hub can count on for a SKU before anything new is
+// ordered.
+type Position struct {
+	OnHand      int
+	Reserved    int
+	Inbound     int // ordered or in transit, not yet received
+	SafetyStock int
+}
+
+// ReorderQuantity is how much to order so that, after leadTimeDays of
+// demand at dailyDemand, the hub still holds daysOfCover days of it on top
+// of its safety stock. It is never negative.
+func ReorderQuantity(dailyDemand float64, leadTimeDays, daysOfCover int, position Position) int {
+	need := int(math.Ceil(dailyDemand*float64(leadTimeDays+daysOfCover))) + position.SafetyStock
+	have := position.OnHand - position.Reserved + position.Inbound
+	return max(need-have, 0)
+}
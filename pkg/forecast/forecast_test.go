@@ -0,0 +1,122 @@
+package forecast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMovingAverage(t *testing.T) {
+	tests := []struct {
+		name     string
+		daily    []int
+		window   int
+		expected float64
+	}{
+		{name: "last window days", daily: []int{1, 2, 3, 4, 5, 6}, window: 3, expected: 5},               // (4+5+6)/3
+		{name: "history shorter than window", daily: []int{1, 2, 3, 4, 5, 6}, window: 10, expected: 3.5}, // 21/6
+		{name: "window equals history", daily: []int{2, 4}, window: 2, expected: 3},
+		{name: "zero demand", daily: []int{0, 0, 0, 0}, window: 3, expected: 0},
+		{name: "empty history", daily: nil, window: 7, expected: 0},
+		{name: "zero window", daily: []int{5, 5}, window: 0, expected: 0},
+		{name: "negative window", daily: []int{5, 5}, window: -1, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, MovingAverage(tt.daily, tt.window), 1e-9)
+		})
+	}
+}
+
+func TestExponentialSmoothing(t *testing.T) {
+	tests := []struct {
+		name     string
+		daily    []int
+		alpha    float64
+		expected float64
+	}{
+		{name: "half alpha", daily: []int{10, 20, 30}, alpha: 0.5, expected: 22.5}, // 10 -> 15 -> 22.5
+		{name: "repo alpha", daily: []int{10, 0, 20}, alpha: 0.3, expected: 10.9},  // 10 -> 7 -> 10.9
+		{name: "alpha zero keeps the first day", daily: []int{10, 20, 30}, alpha: 0, expected: 10},
+		{name: "alpha one follows the last day", daily: []int{10, 20, 30}, alpha: 1, expected: 30},
+		{name: "single day", daily: []int{7}, alpha: 0.3, expected: 7},
+		{name: "zero demand", daily: []int{0, 0, 0}, alpha: 0.3, expected: 0},
+		{name: "empty history", daily: nil, alpha: 0.3, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, ExponentialSmoothing(tt.daily, tt.alpha), 1e-9)
+		})
+	}
+}
+
+func TestReorderQuantity(t *testing.T) {
+	tests := []struct {
+		name        string
+		dailyDemand float64
+		leadTime    int
+		daysOfCover int
+		position    Position
+		expected    int
+	}{
+		{
+			// ceil(2.5*(3+4)) + 5 - (10 - 2 + 4) = 18 + 5 - 12
+			name:        "demand over lead time and cover plus safety less position",
+			dailyDemand: 2.5,
+			leadTime:    3,
+			daysOfCover: 4,
+			position:    Position{OnHand: 10, Reserved: 2, Inbound: 4, SafetyStock: 5},
+			expected:    11,
+		},
+		{
+			// ceil(0.1*7) = 1
+			name:        "fractional demand rounds up",
+			dailyDemand: 0.1,
+			leadTime:    7,
+			daysOfCover: 0,
+			expected:    1,
+		},
+		{
+			name:        "zero demand still restores safety stock",
+			dailyDemand: 0,
+			leadTime:    7,
+			daysOfCover: 14,
+			position:    Position{OnHand: 2, SafetyStock: 5},
+			expected:    3,
+		},
+		{
+			name:        "inbound counts towards the position",
+			dailyDemand: 1,
+			leadTime:    5,
+			daysOfCover: 5,
+			position:    Position{Inbound: 10},
+			expected:    0,
+		},
+		{
+			// 10 - (100 - 0 + 0) is negative
+			name:        "floored at zero",
+			dailyDemand: 1,
+			leadTime:    5,
+			daysOfCover: 5,
+			position:    Position{OnHand: 100},
+			expected:    0,
+		},
+		{
+			// 10 - (5 - 8) = 13
+			name:        "oversold stock is made up",
+			dailyDemand: 1,
+			leadTime:    5,
+			daysOfCover: 5,
+			position:    Position{OnHand: 5, Reserved: 8},
+			expected:    13,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ReorderQuantity(tt.dailyDemand, tt.leadTime, tt.daysOfCover, tt.position))
+		})
+	}
+}
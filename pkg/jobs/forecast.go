@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/constants"
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/omniful/go_commons/i18n"
+	"github.com/omniful/go_commons/log"
+)
+
+// StartDemandForecasts rebuilds the demand forecasts and reorder suggestions
+// once a day. Runs after a tenant's forecasts are rebuilt skip it.
+func StartDemandForecasts(ctx context.Context) {
+	runEvery(ctx, "demand-forecast", constants.DemandForecastInterval, func(ctx context.Context) error {
+		written, err := models.RefreshDemandForecasts(ctx)
+		if err != nil {
+			return err
+		}
+		if written > 0 {
+			log.Infof(i18n.Translate(ctx, "Forecast demand for %d inventories"), written)
+		}
+		return nil
+	})
+}
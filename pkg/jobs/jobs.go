@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/omniful/go_commons/i18n"
+	"github.com/omniful/go_commons/log"
+)
+
+// runEvery calls fn on a fixed interval in its own goroutine until ctx is done.
+func runEvery(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := fn(ctx); err != nil {
+					log.Errorf(i18n.Translate(ctx, "Job %s failed: %v"), name, err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/constants"
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/omniful/go_commons/i18n"
+	"github.com/omniful/go_commons/log"
+)
+
+// StartReservationExpiry periodically expires holds that were never committed
+// or released, returning their stock to the available pool.
+func StartReservationExpiry(ctx context.Context) {
+	runEvery(ctx, "reservation-expiry", constants.ReservationExpiryInterval, func(ctx context.Context) error {
+		expired, err := models.ExpireReservations(ctx)
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			log.Infof(i18n.Translate(ctx, "Expired %d reservations"), expired)
+		}
+		return nil
+	})
+}
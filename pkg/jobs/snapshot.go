@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/constants"
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/omniful/go_commons/i18n"
+	"github.com/omniful/go_commons/log"
+)
+
+// StartInventorySnapshots takes the daily inventory snapshot that as-of
+// views start from. Runs after the day's snapshot are no-ops.
+func StartInventorySnapshots(ctx context.Context) {
+	runEvery(ctx, "inventory-snapshot", constants.InventorySnapshotInterval, func(ctx context.Context) error {
+		copied, err := models.TakeInventorySnapshot(ctx)
+		if err != nil {
+			return err
+		}
+		if copied > 0 {
+			log.Infof(i18n.Translate(ctx, "Snapshotted %d inventories"), copied)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/constants"
+	"github.com/aditya-goyal-omniful/ims/pkg/models"
+	"github.com/omniful/go_commons/i18n"
+	"github.com/omniful/go_commons/log"
+)
+
+// StartStockAlertEvaluator checks thresholds of every row whose stock was
+// decremented, and periodically sweeps rows in case a signal was dropped.
+func StartStockAlertEvaluator(ctx context.Context) {
+	go func() {
+		decrements := models.StockDecrements()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case inventoryID := <-decrements:
+				if err := models.EvaluateStockAlerts(ctx, inventoryID); err != nil {
+					log.Errorf(i18n.Translate(ctx, "Stock alert evaluation failed for %s: %v"), inventoryID, err)
+				}
+			}
+		}
+	}()
+
+	runEvery(ctx, "stock-alert-sweep", constants.StockAlertSweepInterval, func(ctx context.Context) error {
+		evaluated, err := models.SweepStockAlerts(ctx)
+		if err != nil {
+			return err
+		}
+		if evaluated > 0 {
+			log.Infof(i18n.Translate(ctx, "Re-evaluated stock alerts of %d inventories"), evaluated)
+		}
+		return nil
+	})
+}
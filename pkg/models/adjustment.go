@@ -0,0 +1,359 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	AdjustmentPending  = "pending"
+	AdjustmentApplied  = "applied"
+	AdjustmentRejected = "rejected"
+)
+
+// Reason code directions
+const (
+	AdjustmentIncrease = "increase"
+	AdjustmentDecrease = "decrease"
+	AdjustmentAny      = "any"
+)
+
+var (
+	ErrInvalidAdjustment   = errors.New("invalid adjustment")
+	ErrInvalidReasonCode   = errors.New("unknown reason code")
+	ErrReasonCodeExists    = errors.New("reason code already exists")
+	ErrAdjustmentState     = errors.New("adjustment is not pending")
+	ErrSelfApproval        = errors.New("adjustment must be approved by someone other than the requester")
+	ErrQuantityNeedsReason = errors.New("quantity changes need a reason_code and a requesting X-User-ID")
+)
+
+// DefaultAdjustmentReasonCodes apply to tenants that have not configured
+// their own.
+var DefaultAdjustmentReasonCodes = []AdjustmentReasonCode{
+	{Code: "damage", Description: "Units damaged in the hub", Direction: AdjustmentDecrease},
+	{Code: "shrinkage", Description: "Units lost or stolen", Direction: AdjustmentDecrease},
+	{Code: "found", Description: "Units found that were not on record", Direction: AdjustmentIncrease},
+	{Code: "correction", Description: "Correction of a recording error", Direction: AdjustmentAny},
+}
+
+// StocktakeReasonCode books the variances of approved stocktakes. Every
+// tenant has it, alongside the defaults or its own codes.
+var StocktakeReasonCode = AdjustmentReasonCode{
+	Code:        "stocktake",
+	Description: "Variance found by an approved stocktake",
+	Direction:   AdjustmentAny,
+}
+
+type AdjustmentReasonCode struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID    uuid.UUID `gorm:"type:uuid;not null" json:"tenant_id"`
+	Code        string    `gorm:"not null" json:"code"`
+	Description string    `json:"description"`
+	Direction   string    `gorm:"not null;default:any" json:"direction"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type InventoryAdjustment struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID       uuid.UUID  `gorm:"type:uuid;not null" json:"tenant_id"`
+	InventoryID    uuid.UUID  `gorm:"type:uuid;not null" json:"inventory_id"`
+	HubID          uuid.UUID  `gorm:"type:uuid;not null" json:"hub_id"`
+	SkuID          uuid.UUID  `gorm:"type:uuid;not null" json:"sku_id"`
+	Delta          int        `gorm:"not null" json:"delta"`
+	ReasonCode     string     `gorm:"not null" json:"reason_code"`
+	Note           string     `json:"note"`
+	Status         string     `gorm:"not null" json:"status"`
+	RequestedBy    string     `gorm:"not null" json:"requested_by"`
+	ReviewedBy     string     `json:"reviewed_by"`
+	ReviewedAt     *time.Time `json:"reviewed_at"`
+	QuantityBefore *int       `json:"quantity_before"` // set once applied
+	QuantityAfter  *int       `json:"quantity_after"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type AdjustmentModel struct{}
+
+// GetAdjustmentReasonCodes
+
+func (a AdjustmentModel) GetAdjustmentReasonCodes(ctx context.Context, tenantID uuid.UUID) ([]AdjustmentReasonCode, error) {
+	return GetAdjustmentReasonCodes(ctx, tenantID)
+}
+
+func GetAdjustmentReasonCodes(ctx context.Context, tenantID uuid.UUID) ([]AdjustmentReasonCode, error) {
+	return reasonCodes(getDB(ctx), tenantID)
+}
+
+// reasonCodes returns the tenant's reason codes, or the defaults when it has
+// none of its own, plus the stocktake code unless the tenant defined one.
+func reasonCodes(db *gorm.DB, tenantID uuid.UUID) ([]AdjustmentReasonCode, error) {
+	var codes []AdjustmentReasonCode
+	if err := db.Where("tenant_id = ?", tenantID).Order("code").Find(&codes).Error; err != nil {
+		return nil, err
+	}
+	if len(codes) == 0 {
+		codes = make([]AdjustmentReasonCode, len(DefaultAdjustmentReasonCodes))
+		for i, code := range DefaultAdjustmentReasonCodes {
+			code.TenantID = tenantID
+			codes[i] = code
+		}
+	}
+
+	for _, code := range codes {
+		if code.Code == StocktakeReasonCode.Code {
+			return codes, nil
+		}
+	}
+	stocktake := StocktakeReasonCode
+	stocktake.TenantID = tenantID
+	return append(codes, stocktake), nil
+}
+
+// CreateAdjustmentReasonCode
+
+func (a AdjustmentModel) CreateAdjustmentReasonCode(ctx context.Context, code *AdjustmentReasonCode) error {
+	return CreateAdjustmentReasonCode(ctx, code)
+}
+
+// CreateAdjustmentReasonCode adds a code for the tenant. Once a tenant has a
+// code of its own the built-in set no longer applies to it.
+func CreateAdjustmentReasonCode(ctx context.Context, code *AdjustmentReasonCode) error {
+	result := getDB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "code"}},
+		DoNothing: true,
+	}).Create(code)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReasonCodeExists
+	}
+	return nil
+}
+
+// CreateAdjustment
+
+func (a AdjustmentModel) CreateAdjustment(ctx context.Context, adj *InventoryAdjustment) error {
+	return CreateAdjustment(ctx, adj)
+}
+
+// CreateAdjustment records an adjustment against one of the tenant's
+// inventory rows. Small adjustments are applied straight away; ones above the
+// tenant's unit or percentage threshold wait for approval.
+func CreateAdjustment(ctx context.Context, adj *InventoryAdjustment) error {
+	return getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		inv, err := lockInventoryByID(tx, adj.InventoryID)
+		if err != nil {
+			return err
+		}
+		if inv.TenantID != adj.TenantID {
+			return gorm.ErrRecordNotFound
+		}
+		return requestAdjustment(ctx, tx, adj, inv)
+	})
+}
+
+// requestAdjustment records adj against a row locked in tx and applies it
+// unless it needs approval.
+func requestAdjustment(ctx context.Context, tx *gorm.DB, adj *InventoryAdjustment, inv *Inventory) error {
+	if err := ensureNotSerialized(tx, inv.SkuID); err != nil {
+		return err
+	}
+
+	codes, err := reasonCodes(tx, adj.TenantID)
+	if err != nil {
+		return err
+	}
+	if err := checkReasonCode(codes, adj.ReasonCode, adj.Delta); err != nil {
+		return err
+	}
+
+	var tenant Tenant
+	if err := tx.First(&tenant, "id = ?", adj.TenantID).Error; err != nil {
+		return err
+	}
+
+	adj.HubID = inv.HubID
+	adj.SkuID = inv.SkuID
+	adj.Status = AdjustmentPending
+	if err := tx.Create(adj).Error; err != nil {
+		return err
+	}
+
+	if needsApproval(&tenant, inv.Quantity, adj.Delta) {
+		return nil
+	}
+	return applyAdjustment(ctx, tx, adj, inv, adj.RequestedBy)
+}
+
+func checkReasonCode(codes []AdjustmentReasonCode, code string, delta int) error {
+	for _, c := range codes {
+		if c.Code != code {
+			continue
+		}
+		if (c.Direction == AdjustmentIncrease && delta < 0) || (c.Direction == AdjustmentDecrease && delta > 0) {
+			return fmt.Errorf("%w: %s only allows an %s", ErrInvalidAdjustment, code, c.Direction)
+		}
+		return nil
+	}
+	return ErrInvalidReasonCode
+}
+
+// needsApproval applies the tenant's thresholds. The percentage is measured
+// against on-hand stock, so it only applies when there is some.
+func needsApproval(tenant *Tenant, onHand, delta int) bool {
+	units := max(delta, -delta)
+	if tenant.AdjustmentApprovalUnits != nil && units > *tenant.AdjustmentApprovalUnits {
+		return true
+	}
+	if tenant.AdjustmentApprovalPercent != nil && onHand > 0 {
+		return float64(units)*100/float64(onHand) > *tenant.AdjustmentApprovalPercent
+	}
+	return false
+}
+
+// applyAdjustment changes the quantity of a row locked in tx and marks the
+// adjustment applied. The delta is applied to the current quantity, which may
+// have moved since the adjustment was requested.
+func applyAdjustment(ctx context.Context, tx *gorm.DB, adj *InventoryAdjustment, inv *Inventory, reviewer string) error {
+	if inv.Quantity+adj.Delta < 0 {
+		return ErrInsufficientStock
+	}
+
+	meta := movementMetaFromContext(ctx)
+	meta.Reason = MovementAdjustment
+	if meta.ReferenceID == "" {
+		meta.ReferenceID = adj.ID.String()
+	}
+
+	before := inv.Quantity
+	if err := applyQuantityChange(WithMovementMeta(ctx, meta), tx, inv, adj.Delta, MovementAdjustment); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	adj.Status = AdjustmentApplied
+	adj.ReviewedBy = reviewer
+	adj.ReviewedAt = &now
+	adj.QuantityBefore = &before
+	adj.QuantityAfter = &inv.Quantity
+	return tx.Model(&InventoryAdjustment{}).Where("id = ?", adj.ID).Updates(map[string]interface{}{
+		"status":          adj.Status,
+		"reviewed_by":     reviewer,
+		"reviewed_at":     now,
+		"quantity_before": before,
+		"quantity_after":  inv.Quantity,
+	}).Error
+}
+
+// lockPendingAdjustment locks an adjustment of the tenant and checks it is
+// still waiting for review.
+func lockPendingAdjustment(tx *gorm.DB, tenantID, id uuid.UUID) (*InventoryAdjustment, error) {
+	var adj InventoryAdjustment
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&adj, "id = ? AND tenant_id = ?", id, tenantID).Error
+	if err != nil {
+		return nil, err
+	}
+	if adj.Status != AdjustmentPending {
+		return nil, ErrAdjustmentState
+	}
+	return &adj, nil
+}
+
+// ApproveAdjustment
+
+func (a AdjustmentModel) ApproveAdjustment(ctx context.Context, tenantID, id uuid.UUID, approver string) (*InventoryAdjustment, error) {
+	return ApproveAdjustment(ctx, tenantID, id, approver)
+}
+
+// ApproveAdjustment applies a pending adjustment. The approver must differ
+// from the requester. Both are the X-User-ID header, which nothing
+// authenticates yet, so this only guards against honest self-approval; it
+// is not a control against a caller sending another user's ID.
+func ApproveAdjustment(ctx context.Context, tenantID, id uuid.UUID, approver string) (*InventoryAdjustment, error) {
+	var adj *InventoryAdjustment
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		adj, err = lockPendingAdjustment(tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+		if approver == adj.RequestedBy {
+			return ErrSelfApproval
+		}
+
+		inv, err := lockInventoryByID(tx, adj.InventoryID)
+		if err != nil {
+			return err
+		}
+		return applyAdjustment(ctx, tx, adj, inv, approver)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return adj, nil
+}
+
+// RejectAdjustment
+
+func (a AdjustmentModel) RejectAdjustment(ctx context.Context, tenantID, id uuid.UUID, reviewer string) (*InventoryAdjustment, error) {
+	return RejectAdjustment(ctx, tenantID, id, reviewer)
+}
+
+// RejectAdjustment closes a pending adjustment without touching stock. The
+// requester may withdraw their own.
+func RejectAdjustment(ctx context.Context, tenantID, id uuid.UUID, reviewer string) (*InventoryAdjustment, error) {
+	var adj *InventoryAdjustment
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		adj, err = lockPendingAdjustment(tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		adj.Status = AdjustmentRejected
+		adj.ReviewedBy = reviewer
+		adj.ReviewedAt = &now
+		return tx.Model(&InventoryAdjustment{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":      adj.Status,
+			"reviewed_by": reviewer,
+			"reviewed_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return adj, nil
+}
+
+// GetAdjustments
+
+func (a AdjustmentModel) GetAdjustments(ctx context.Context, tenantID uuid.UUID, status string) ([]InventoryAdjustment, error) {
+	return GetAdjustments(ctx, tenantID, status)
+}
+
+// GetAdjustments lists a tenant's adjustments, newest first, optionally
+// narrowed to one status.
+func GetAdjustments(ctx context.Context, tenantID uuid.UUID, status string) ([]InventoryAdjustment, error) {
+	query := getDB(ctx).Where("tenant_id = ?", tenantID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var adjustments []InventoryAdjustment
+	if err := query.Order("created_at DESC").Find(&adjustments).Error; err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
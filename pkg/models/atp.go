@@ -0,0 +1,236 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrATPNotFound = errors.New("sku or hub not found for tenant")
+
+// ATPQuery asks for one SKU at one hub, or across all hubs of the tenant
+// when HubID is nil.
+type ATPQuery struct {
+	SkuID uuid.UUID  `json:"sku_id" binding:"required"`
+	HubID *uuid.UUID `json:"hub_id"`
+}
+
+type ATPLine struct {
+	HubID       *uuid.UUID `json:"hub_id,omitempty"` // nil on the tenant total
+	OnHand      int        `json:"on_hand"`
+	Reserved    int        `json:"reserved"`
+	SafetyStock int        `json:"safety_stock"`
+	Inbound     int        `json:"inbound"` // confirmed to arrive within the horizon
+	ATP         int        `json:"atp"`
+}
+
+type ATPResult struct {
+	SkuID uuid.UUID `json:"sku_id"`
+	Total ATPLine   `json:"total"`
+	Hubs  []ATPLine `json:"hubs"`
+}
+
+type atpRow struct {
+	SkuID       uuid.UUID
+	HubID       uuid.UUID
+	OnHand      int
+	Reserved    int
+	SafetyStock int
+	Inbound     int
+}
+
+// GetATP
+
+func (i InventoryModel) GetATP(ctx context.Context, tenantID uuid.UUID, queries []ATPQuery, until time.Time) ([]ATPResult, error) {
+	return GetATP(ctx, tenantID, queries, until)
+}
+
+// GetATP computes available-to-promise per hub as sellable on-hand minus
+// active reservations minus safety stock plus inbound expected by until,
+// floored at zero. On-hand and reservations come from the hub inventory
+// view, so a bundle promises the whole bundles its components make up; it
+// holds no safety stock or inbound of its own. Like GetInventoryWithDefaults
+// it reports hubs without an inventory row as zero. The tenant total adds up
+// the per-hub figures, so a shortfall at one hub never eats into another.
+func GetATP(ctx context.Context, tenantID uuid.UUID, queries []ATPQuery, until time.Time) ([]ATPResult, error) {
+	skuIDs := make([]uuid.UUID, 0, len(queries))
+	seen := make(map[uuid.UUID]bool, len(queries))
+	for _, q := range queries {
+		if !seen[q.SkuID] {
+			seen[q.SkuID] = true
+			skuIDs = append(skuIDs, q.SkuID)
+		}
+	}
+
+	db := getDB(ctx)
+
+	// Only the asked-for hubs, unless some query spans the tenant
+	hubQuery := db.Model(&Hub{}).Where("tenant_id = ?", tenantID)
+	requested := make([]uuid.UUID, 0, len(queries))
+	for _, q := range queries {
+		if q.HubID == nil {
+			requested = nil
+			break
+		}
+		requested = append(requested, *q.HubID)
+	}
+	if requested != nil {
+		hubQuery = hubQuery.Where("id IN ?", requested)
+	}
+
+	var hubIDs []uuid.UUID
+	if err := hubQuery.Order("id").Pluck("id", &hubIDs).Error; err != nil {
+		return nil, err
+	}
+
+	var safety []struct {
+		HubID       uuid.UUID
+		SkuID       uuid.UUID
+		SafetyStock int
+	}
+	if err := db.Model(&Inventory{}).Select("hub_id, sku_id, safety_stock").
+		Where("tenant_id = ? AND sku_id IN ?", tenantID, skuIDs).Scan(&safety).Error; err != nil {
+		return nil, err
+	}
+	safetyStock := make(map[stockRowKey]int, len(safety))
+	for _, row := range safety {
+		safetyStock[stockRowKey{row.HubID, row.SkuID}] = row.SafetyStock
+	}
+
+	inbound, err := inboundStock(db, tenantID, skuIDs, &until)
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := hubInventoryViews(db, tenantID, hubIDs, skuIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]atpRow, 0, len(views))
+	for _, view := range views {
+		key := stockRowKey{view.HubID, view.SkuID}
+		rows = append(rows, atpRow{
+			SkuID:       view.SkuID,
+			HubID:       view.HubID,
+			OnHand:      view.OnHand,
+			Reserved:    view.Reserved,
+			SafetyStock: safetyStock[key],
+			Inbound:     inbound[key],
+		})
+	}
+
+	bySku := make(map[uuid.UUID][]atpRow, len(skuIDs))
+	for _, row := range rows {
+		bySku[row.SkuID] = append(bySku[row.SkuID], row)
+	}
+
+	results := make([]ATPResult, 0, len(queries))
+	for _, q := range queries {
+		skuRows, ok := bySku[q.SkuID]
+		if !ok {
+			return nil, ErrATPNotFound
+		}
+
+		result := ATPResult{SkuID: q.SkuID, Hubs: []ATPLine{}}
+		for _, row := range skuRows {
+			if q.HubID != nil && row.HubID != *q.HubID {
+				continue
+			}
+			if q.HubID == nil && row.OnHand == 0 && row.Reserved == 0 && row.Inbound == 0 {
+				continue
+			}
+
+			hubID := row.HubID
+			line := ATPLine{
+				HubID:       &hubID,
+				OnHand:      row.OnHand,
+				Reserved:    row.Reserved,
+				SafetyStock: row.SafetyStock,
+				Inbound:     row.Inbound,
+				ATP:         max(row.OnHand-row.Reserved-row.SafetyStock+row.Inbound, 0),
+			}
+			result.Hubs = append(result.Hubs, line)
+
+			result.Total.OnHand += line.OnHand
+			result.Total.Reserved += line.Reserved
+			result.Total.SafetyStock += line.SafetyStock
+			result.Total.Inbound += line.Inbound
+			result.Total.ATP += line.ATP
+		}
+
+		if q.HubID != nil && len(result.Hubs) == 0 {
+			return nil, ErrATPNotFound
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// inboundStock sums the stock confirmed to arrive per hub and SKU:
+// dispatched transfers and shipping notices still in transit. A notice's
+// quantity is netted against what its order has already received without a
+// notice, since those units may be the ones it announced. With until set
+// only what is expected by then counts; arrivals without an expected date
+// always do. A nil skuIDs covers every SKU of the tenant.
+func inboundStock(db *gorm.DB, tenantID uuid.UUID, skuIDs []uuid.UUID, until *time.Time) (map[stockRowKey]int, error) {
+	transferFilter, noticeFilter := "", ""
+	transferArgs := []interface{}{tenantID, TransferDispatched}
+	noticeArgs := []interface{}{ASNReceived, tenantID, PurchaseOrderClosed, tenantID, ASNInTransit, PurchaseOrderClosed}
+	if skuIDs != nil {
+		transferFilter += " AND l.sku_id IN ?"
+		transferArgs = append(transferArgs, skuIDs)
+		noticeFilter += " AND l.sku_id IN ?"
+		noticeArgs = append(noticeArgs, skuIDs)
+	}
+	if until != nil {
+		transferFilter += " AND (o.expected_at IS NULL OR o.expected_at <= ?)"
+		transferArgs = append(transferArgs, *until)
+		noticeFilter += " AND (COALESCE(n.expected_at, o.expected_at) IS NULL OR COALESCE(n.expected_at, o.expected_at) <= ?)"
+		noticeArgs = append(noticeArgs, *until)
+	}
+
+	var rows []struct {
+		HubID   uuid.UUID
+		SkuID   uuid.UUID
+		Inbound int
+	}
+	err := db.Raw(`
+		SELECT hub_id, sku_id, SUM(quantity) AS inbound
+		FROM (
+			SELECT o.destination_hub_id AS hub_id, l.sku_id, l.quantity
+			FROM transfer_order_lines l
+			JOIN transfer_orders o ON o.id = l.transfer_order_id
+			WHERE o.tenant_id = ? AND o.status = ?`+transferFilter+`
+			UNION ALL
+			SELECT o.hub_id, l.sku_id, GREATEST(SUM(l.quantity) - COALESCE(MAX(u.unmatched), 0), 0) AS quantity
+			FROM advance_shipping_notice_lines l
+			JOIN advance_shipping_notices n ON n.id = l.advance_shipping_notice_id
+			JOIN purchase_orders o ON o.id = n.purchase_order_id
+			LEFT JOIN (
+				SELECT pl.purchase_order_id, pl.sku_id, pl.received_quantity - COALESCE(SUM(rl.received_quantity), 0) AS unmatched
+				FROM purchase_order_lines pl
+				LEFT JOIN advance_shipping_notices rn ON rn.purchase_order_id = pl.purchase_order_id AND rn.status = ?
+				LEFT JOIN advance_shipping_notice_lines rl ON rl.advance_shipping_notice_id = rn.id AND rl.sku_id = pl.sku_id
+				WHERE pl.purchase_order_id IN (SELECT id FROM purchase_orders WHERE tenant_id = ? AND status <> ?)
+				GROUP BY pl.purchase_order_id, pl.sku_id, pl.received_quantity
+			) u ON u.purchase_order_id = o.id AND u.sku_id = l.sku_id
+			WHERE o.tenant_id = ? AND n.status = ? AND o.status <> ?`+noticeFilter+`
+			GROUP BY o.id, o.hub_id, l.sku_id
+		) arriving
+		GROUP BY hub_id, sku_id
+	`, append(transferArgs, noticeArgs...)...).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	inbound := make(map[stockRowKey]int, len(rows))
+	for _, row := range rows {
+		inbound[stockRowKey{row.HubID, row.SkuID}] = row.Inbound
+	}
+	return inbound, nil
+}
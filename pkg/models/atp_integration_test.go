@@ -0,0 +1,54 @@
+//go:build integration
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// TestGetATPBundle checks that a bundle, which has no inventory row of its
+// own, promises the whole bundles its components make up and can be sourced.
+func TestGetATPBundle(t *testing.T) {
+	ctx := context.Background()
+	tenantID, sellerID := seedTenant(t, ctx)
+	hubID := seedHub(t, ctx, tenantID)
+
+	first, second := seedSku(t, ctx, tenantID, sellerID), seedSku(t, ctx, tenantID, sellerID)
+	seedInventory(t, ctx, tenantID, hubID, first, 10)
+	seedInventory(t, ctx, tenantID, hubID, second, 7)
+
+	bundleID := seedSku(t, ctx, tenantID, sellerID)
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		var bundle Sku
+		if err := tx.First(&bundle, "id = ?", bundleID).Error; err != nil {
+			return err
+		}
+		return setBundleComponents(tx, &bundle, []BundleComponent{
+			{ComponentSkuID: first, Quantity: 2},
+			{ComponentSkuID: second, Quantity: 1},
+		})
+	})
+	if err != nil {
+		t.Fatalf("seed bundle: %v", err)
+	}
+
+	results, err := GetATP(ctx, tenantID, []ATPQuery{{SkuID: bundleID, HubID: &hubID}}, time.Now())
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, 5, results[0].Total.OnHand)
+		assert.Equal(t, 5, results[0].Total.ATP)
+	}
+
+	candidates, err := GetSourcingCandidates(ctx, tenantID, []uuid.UUID{bundleID})
+	assert.NoError(t, err)
+	if assert.Len(t, candidates, 1) {
+		assert.Equal(t, hubID, candidates[0].HubID)
+		assert.Equal(t, 5, candidates[0].Available[bundleID])
+	}
+}
@@ -0,0 +1,210 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/configs"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	BarcodeEAN13    = "ean13"
+	BarcodeUPCA     = "upca"
+	BarcodeGTIN14   = "gtin14"
+	BarcodeInternal = "internal"
+)
+
+// gs1Lengths is the digit count of each GS1 barcode type. Internal codes are
+// free-form.
+var gs1Lengths = map[string]int{
+	BarcodeEAN13:  13,
+	BarcodeUPCA:   12,
+	BarcodeGTIN14: 14,
+}
+
+var (
+	ErrInvalidBarcode = errors.New("invalid barcode")
+	ErrBarcodeExists  = errors.New("barcode already assigned to a sku of the tenant")
+	ErrHubNotFound    = errors.New("hub not found for tenant")
+)
+
+type SkuBarcode struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID  uuid.UUID `gorm:"type:uuid;not null" json:"tenant_id"`
+	SkuID     uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
+	Barcode   string    `gorm:"not null" json:"barcode"`
+	Type      string    `gorm:"not null" json:"type"`
+	GTIN      *string   `gorm:"column:gtin" json:"gtin,omitempty"` // GS1 codes as 14 digits; nil for internal codes
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// BarcodeLookup is what a scan resolves to: the SKU and, when the scan came
+// from a hub, its stock there.
+type BarcodeLookup struct {
+	Barcode SkuBarcode     `json:"barcode"`
+	Sku     Sku            `json:"sku"`
+	Stock   *InventoryView `json:"stock,omitempty"`
+}
+
+// ValidateBarcode checks the format of a barcode of the given type. GS1
+// codes must have the right number of digits and a valid check digit.
+func ValidateBarcode(barcodeType, code string) error {
+	if barcodeType == BarcodeInternal {
+		if code == "" || strings.ContainsAny(code, " \t\r\n") {
+			return fmt.Errorf("%w: internal codes must be non-empty without spaces", ErrInvalidBarcode)
+		}
+		return nil
+	}
+
+	length, ok := gs1Lengths[barcodeType]
+	if !ok {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidBarcode, barcodeType)
+	}
+	if len(code) != length || !isDigits(code) {
+		return fmt.Errorf("%w: %s must be %d digits", ErrInvalidBarcode, barcodeType, length)
+	}
+	if gs1CheckDigit(code[:length-1]) != code[length-1] {
+		return fmt.Errorf("%w: wrong check digit in %s", ErrInvalidBarcode, code)
+	}
+	return nil
+}
+
+// gs1CheckDigit computes the GS1 mod-10 check digit of the digits before
+// it: weights 3 and 1 alternate from the rightmost digit.
+func gs1CheckDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// toGTIN14 left-pads a GS1 code of up to 14 digits with zeros.
+func toGTIN14(code string) string {
+	return strings.Repeat("0", 14-len(code)) + code
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// AddSkuBarcode
+
+func (s SKUModel) AddSkuBarcode(ctx context.Context, barcode *SkuBarcode) error {
+	return AddSkuBarcode(ctx, barcode)
+}
+
+func AddSkuBarcode(ctx context.Context, barcode *SkuBarcode) error {
+	var sku Sku
+	if err := getDB(ctx).First(&sku, "id = ?", barcode.SkuID).Error; err != nil {
+		return err
+	}
+
+	barcodes := []SkuBarcode{*barcode}
+	if err := addSkuBarcodes(getDB(ctx), &sku, barcodes); err != nil {
+		return err
+	}
+	*barcode = barcodes[0]
+
+	// Invalidate cache
+	_, _ = configs.RedisClient.Del(ctx, fmt.Sprintf("sku:%s", sku.ID))
+
+	return nil
+}
+
+// addSkuBarcodes validates and stores barcodes for sku, filling in the rows
+// as stored.
+func addSkuBarcodes(tx *gorm.DB, sku *Sku, barcodes []SkuBarcode) error {
+	for i := range barcodes {
+		barcode := &barcodes[i]
+		if err := ValidateBarcode(barcode.Type, barcode.Barcode); err != nil {
+			return err
+		}
+		barcode.ID = uuid.Nil
+		barcode.TenantID = sku.TenantID
+		barcode.SkuID = sku.ID
+		barcode.GTIN = nil
+		if barcode.Type != BarcodeInternal {
+			gtin := toGTIN14(barcode.Barcode)
+			barcode.GTIN = &gtin
+		}
+
+		// Conflicts on either the code or its GTIN mean it is taken
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(barcode)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %s", ErrBarcodeExists, barcode.Barcode)
+		}
+	}
+
+	sku.Barcodes = barcodes
+	return nil
+}
+
+// LookupBarcode
+
+func (s SKUModel) LookupBarcode(ctx context.Context, tenantID uuid.UUID, code string, hubID *uuid.UUID) (*BarcodeLookup, error) {
+	return LookupBarcode(ctx, tenantID, code, hubID)
+}
+
+// LookupBarcode resolves a scanned code to its SKU. GS1 codes match across
+// lengths, so a UPC-A scanned as a 13-digit EAN still resolves. With a hub
+// the SKU's stock there is included.
+func LookupBarcode(ctx context.Context, tenantID uuid.UUID, code string, hubID *uuid.UUID) (*BarcodeLookup, error) {
+	db := getDB(ctx)
+	query := db.Where("tenant_id = ?", tenantID)
+	if isDigits(code) && len(code) <= 14 {
+		query = query.Where("barcode = ? OR gtin = ?", code, toGTIN14(code)).
+			Order(clause.OrderBy{Expression: clause.Expr{SQL: "barcode = ? DESC", Vars: []interface{}{code}}})
+	} else {
+		query = query.Where("barcode = ?", code)
+	}
+
+	var lookup BarcodeLookup
+	if err := query.First(&lookup.Barcode).Error; err != nil {
+		return nil, err
+	}
+
+	sku, err := GetSku(ctx, lookup.Barcode.SkuID)
+	if err != nil {
+		return nil, err
+	}
+	lookup.Sku = *sku
+
+	if hubID != nil {
+		hub, err := GetHub(ctx, *hubID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if hub == nil || hub.TenantID != tenantID {
+			return nil, ErrHubNotFound
+		}
+
+		views, err := inventoryViews(db, tenantID, *hubID, []uuid.UUID{sku.ID})
+		if err != nil {
+			return nil, err
+		}
+		if len(views) > 0 {
+			lookup.Stock = &views[0]
+		}
+	}
+
+	return &lookup, nil
+}
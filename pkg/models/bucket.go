@@ -0,0 +1,213 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Inventory statuses. Sellable stock is inventories.quantity itself; the
+// others live in inventory_buckets.
+const (
+	StatusSellable    = "sellable"
+	StatusDamaged     = "damaged"
+	StatusQuarantined = "quarantined"
+	StatusInTransit   = "in_transit"
+	StatusDisposal    = "disposal"
+)
+
+var inventoryStatuses = map[string]bool{
+	StatusSellable:    true,
+	StatusDamaged:     true,
+	StatusQuarantined: true,
+	StatusInTransit:   true,
+	StatusDisposal:    true,
+}
+
+var ErrInvalidStatus = errors.New("invalid inventory status")
+
+type InventoryBucket struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	InventoryID uuid.UUID `gorm:"type:uuid;not null" json:"inventory_id"`
+	Status      string    `gorm:"not null" json:"status"`
+	Quantity    int       `gorm:"not null" json:"quantity"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type InventoryBuckets struct {
+	Sellable    int `json:"sellable"`
+	Damaged     int `json:"damaged"`
+	Quarantined int `json:"quarantined"`
+	InTransit   int `json:"in_transit"`
+	Disposal    int `json:"disposal"`
+}
+
+type InventoryDetailView struct {
+	SkuID   uuid.UUID `json:"sku_id"`
+	SkuCode string    `json:"sku_code"`
+	SkuName string    `json:"sku_name"`
+	InventoryBuckets
+	Reserved  int `json:"reserved"`
+	Available int `json:"available"` // sellable minus active reservations
+	Total     int `json:"total"`
+}
+
+type StatusMove struct {
+	InventoryID uuid.UUID `json:"inventory_id"`
+	FromStatus  string    `json:"from_status"`
+	ToStatus    string    `json:"to_status"`
+	Quantity    int       `json:"quantity"`
+}
+
+func IsInventoryStatus(status string) bool {
+	return inventoryStatuses[status]
+}
+
+// MoveInventoryStatus
+
+func (i InventoryModel) MoveInventoryStatus(ctx context.Context, move StatusMove) (*InventoryBuckets, error) {
+	return MoveInventoryStatus(ctx, move)
+}
+
+// MoveInventoryStatus shifts quantity between the status buckets of one
+// inventory row. Reserved sellable stock cannot be moved out.
+func MoveInventoryStatus(ctx context.Context, move StatusMove) (*InventoryBuckets, error) {
+	if !IsInventoryStatus(move.FromStatus) || !IsInventoryStatus(move.ToStatus) {
+		return nil, ErrInvalidStatus
+	}
+
+	var buckets *InventoryBuckets
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		inv, err := lockInventoryByID(tx, move.InventoryID)
+		if err != nil {
+			return err
+		}
+
+		if err := takeFromBucket(ctx, tx, inv, move.FromStatus, move.Quantity); err != nil {
+			return err
+		}
+		if err := addToBucket(ctx, tx, inv, move.ToStatus, move.Quantity); err != nil {
+			return err
+		}
+
+		buckets, err = loadBuckets(tx, inv)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// takeFromBucket removes quantity from a status bucket of a locked row.
+func takeFromBucket(ctx context.Context, tx *gorm.DB, inv *Inventory, status string, quantity int) error {
+	if status == StatusSellable {
+		reserved, err := activeReservedQuantity(tx, inv.SkuID, inv.HubID)
+		if err != nil {
+			return err
+		}
+		if inv.Quantity-reserved < quantity {
+			return ErrInsufficientStock
+		}
+		return applyQuantityChange(ctx, tx, inv, -quantity, MovementStatusChange)
+	}
+
+	result := tx.Model(&InventoryBucket{}).
+		Where("inventory_id = ? AND status = ? AND quantity >= ?", inv.ID, status, quantity).
+		Update("quantity", gorm.Expr("quantity - ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// addToBucket adds quantity to a status bucket of a locked row.
+func addToBucket(ctx context.Context, tx *gorm.DB, inv *Inventory, status string, quantity int) error {
+	if status == StatusSellable {
+		return applyQuantityChange(ctx, tx, inv, quantity, MovementStatusChange)
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "inventory_id"}, {Name: "status"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"quantity": gorm.Expr("inventory_buckets.quantity + EXCLUDED.quantity"), "updated_at": gorm.Expr("NOW()")}),
+	}).Create(&InventoryBucket{InventoryID: inv.ID, Status: status, Quantity: quantity}).Error
+}
+
+func loadBuckets(tx *gorm.DB, inv *Inventory) (*InventoryBuckets, error) {
+	var rows []InventoryBucket
+	if err := tx.Where("inventory_id = ?", inv.ID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	buckets := &InventoryBuckets{Sellable: inv.Quantity}
+	for _, row := range rows {
+		switch row.Status {
+		case StatusDamaged:
+			buckets.Damaged = row.Quantity
+		case StatusQuarantined:
+			buckets.Quarantined = row.Quantity
+		case StatusInTransit:
+			buckets.InTransit = row.Quantity
+		case StatusDisposal:
+			buckets.Disposal = row.Quantity
+		}
+	}
+	return buckets, nil
+}
+
+// GetInventoryDetail
+
+func (i InventoryModel) GetInventoryDetail(ctx context.Context, tenantID, hubID uuid.UUID) ([]InventoryDetailView, error) {
+	return GetInventoryDetail(ctx, tenantID, hubID)
+}
+
+// GetInventoryDetail is GetInventoryWithDefaults with every status bucket.
+func GetInventoryDetail(ctx context.Context, tenantID, hubID uuid.UUID) ([]InventoryDetailView, error) {
+	var result []InventoryDetailView
+
+	err := getDB(ctx).Raw(`
+		SELECT
+			s.id AS sku_id,
+			s.sku_code,
+			s.name AS sku_name,
+			COALESCE(i.quantity, 0) AS sellable,
+			COALESCE(b.damaged, 0) AS damaged,
+			COALESCE(b.quarantined, 0) AS quarantined,
+			COALESCE(b.in_transit, 0) AS in_transit,
+			COALESCE(b.disposal, 0) AS disposal,
+			COALESCE(r.reserved, 0) AS reserved,
+			COALESCE(i.quantity, 0) - COALESCE(r.reserved, 0) AS available,
+			COALESCE(i.quantity, 0) + COALESCE(b.damaged, 0) + COALESCE(b.quarantined, 0) + COALESCE(b.in_transit, 0) + COALESCE(b.disposal, 0) AS total
+		FROM skus s
+		LEFT JOIN inventories i
+			ON s.id = i.sku_id AND i.hub_id = ? AND i.tenant_id = s.tenant_id
+		LEFT JOIN (
+			SELECT inventory_id,
+				SUM(quantity) FILTER (WHERE status = ?) AS damaged,
+				SUM(quantity) FILTER (WHERE status = ?) AS quarantined,
+				SUM(quantity) FILTER (WHERE status = ?) AS in_transit,
+				SUM(quantity) FILTER (WHERE status = ?) AS disposal
+			FROM inventory_buckets
+			GROUP BY inventory_id
+		) b ON b.inventory_id = i.id
+		LEFT JOIN (
+			SELECT sku_id, SUM(quantity) AS reserved
+			FROM reservations
+			WHERE hub_id = ? AND status = ? AND expires_at > NOW()
+			GROUP BY sku_id
+		) r ON r.sku_id = s.id
+		WHERE s.tenant_id = ?
+		ORDER BY s.sku_code
+	`, hubID, StatusDamaged, StatusQuarantined, StatusInTransit, StatusDisposal, hubID, ReservationActive, tenantID).Scan(&result).Error
+
+	return result, err
+}
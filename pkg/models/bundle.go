@@ -0,0 +1,228 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/configs"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidBundle = errors.New("invalid bundle")
+	ErrBundleSku     = errors.New("sku is a bundle, stock is held by its components")
+)
+
+// BundleComponent is one SKU of a bundle and how many of it make up one
+// bundle.
+type BundleComponent struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	BundleSkuID    uuid.UUID `gorm:"type:uuid;not null" json:"bundle_sku_id"`
+	ComponentSkuID uuid.UUID `gorm:"type:uuid;not null" json:"component_sku_id"`
+	Quantity       int       `gorm:"not null" json:"quantity"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// SetBundleComponents
+
+func (s SKUModel) SetBundleComponents(ctx context.Context, skuID uuid.UUID, components []BundleComponent) error {
+	return SetBundleComponents(ctx, skuID, components)
+}
+
+// SetBundleComponents replaces the components of a SKU. An empty list turns
+// the bundle back into a plain SKU.
+func SetBundleComponents(ctx context.Context, skuID uuid.UUID, components []BundleComponent) error {
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		var sku Sku
+		if err := tx.First(&sku, "id = ?", skuID).Error; err != nil {
+			return err
+		}
+		return setBundleComponents(tx, &sku, components)
+	})
+
+	// Invalidate cache
+	_, _ = configs.RedisClient.Del(ctx, fmt.Sprintf("sku:%s", skuID))
+
+	return err
+}
+
+// setBundleComponents validates and stores the components of sku. Bundles
+// do not nest, and neither the bundle nor its components may be
+// serial-tracked. A SKU that already has inventory cannot become a bundle.
+func setBundleComponents(tx *gorm.DB, sku *Sku, components []BundleComponent) error {
+	if err := tx.Where("bundle_sku_id = ?", sku.ID).Delete(&BundleComponent{}).Error; err != nil {
+		return err
+	}
+	if len(components) == 0 {
+		sku.Components = nil
+		return nil
+	}
+
+	if sku.Serialized {
+		return fmt.Errorf("%w: a serial-tracked sku cannot be a bundle", ErrInvalidBundle)
+	}
+
+	var stocked int64
+	if err := tx.Model(&Inventory{}).Where("sku_id = ?", sku.ID).Count(&stocked).Error; err != nil {
+		return err
+	}
+	if stocked > 0 {
+		return fmt.Errorf("%w: sku already has inventory", ErrInvalidBundle)
+	}
+
+	var usedIn int64
+	if err := tx.Model(&BundleComponent{}).Where("component_sku_id = ?", sku.ID).Count(&usedIn).Error; err != nil {
+		return err
+	}
+	if usedIn > 0 {
+		return fmt.Errorf("%w: sku is a component of another bundle", ErrInvalidBundle)
+	}
+
+	componentIDs := make([]uuid.UUID, len(components))
+	for i, component := range components {
+		if component.ComponentSkuID == sku.ID {
+			return fmt.Errorf("%w: a bundle cannot contain itself", ErrInvalidBundle)
+		}
+		componentIDs[i] = component.ComponentSkuID
+	}
+
+	var skus []Sku
+	if err := tx.Where("id IN ? AND tenant_id = ?", componentIDs, sku.TenantID).Find(&skus).Error; err != nil {
+		return err
+	}
+	if len(skus) != len(componentIDs) {
+		return fmt.Errorf("%w: unknown component sku", ErrInvalidBundle)
+	}
+	for _, component := range skus {
+		if component.Serialized {
+			return fmt.Errorf("%w: component %s is serial-tracked", ErrInvalidBundle, component.SkuCode)
+		}
+	}
+
+	var nested int64
+	if err := tx.Model(&BundleComponent{}).Where("bundle_sku_id IN ?", componentIDs).Count(&nested).Error; err != nil {
+		return err
+	}
+	if nested > 0 {
+		return fmt.Errorf("%w: components cannot be bundles", ErrInvalidBundle)
+	}
+
+	for i := range components {
+		components[i].ID = uuid.Nil
+		components[i].BundleSkuID = sku.ID
+	}
+	if err := tx.Create(&components).Error; err != nil {
+		return err
+	}
+	sku.Components = components
+	return nil
+}
+
+// bundleComponents loads the components of a SKU in SKU order, so rows are
+// always locked in the same order. It is empty for SKUs that are not
+// bundles.
+func bundleComponents(tx *gorm.DB, skuID uuid.UUID) ([]BundleComponent, error) {
+	var components []BundleComponent
+	err := orderByComponent(tx).Where("bundle_sku_id = ?", skuID).Find(&components).Error
+	return components, err
+}
+
+// orderByComponent keeps preloaded components in a stable order.
+func orderByComponent(db *gorm.DB) *gorm.DB {
+	return db.Order("component_sku_id")
+}
+
+// ensureNotBundle stops a bundle from getting an inventory row of its own;
+// its stock is always derived from its components.
+func ensureNotBundle(tx *gorm.DB, skuID uuid.UUID) error {
+	var components int64
+	if err := tx.Model(&BundleComponent{}).Where("bundle_sku_id = ?", skuID).Count(&components).Error; err != nil {
+		return err
+	}
+	if components > 0 {
+		return ErrBundleSku
+	}
+	return nil
+}
+
+// allocateBundle takes whole bundles off a hub by decrementing every
+// component together. With allowPartial it takes as many whole bundles as
+// the scarcest component allows. Allocation figures are in bundles.
+func allocateBundle(ctx context.Context, tx *gorm.DB, components []BundleComponent, hubID uuid.UUID, quantity int, allowPartial bool) (*StockAllocation, error) {
+	allocation := &StockAllocation{Requested: quantity, Remaining: quantity}
+
+	locked := make([]*Inventory, len(components))
+	possible := quantity
+	for i, component := range components {
+		inv, err := lockInventoryBySkuHub(tx, component.ComponentSkuID, hubID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return allocation, nil
+			}
+			return nil, err
+		}
+
+		reserved, err := activeReservedQuantity(tx, component.ComponentSkuID, hubID)
+		if err != nil {
+			return nil, err
+		}
+		possible = min(possible, max(inv.Quantity-reserved, 0)/component.Quantity)
+		locked[i] = inv
+	}
+	allocation.CurrentStock = bundlesOnHand(components, locked)
+
+	if possible < quantity && !allowPartial {
+		return allocation, nil
+	}
+
+	for i, component := range components {
+		if err := applyQuantityChange(ctx, tx, locked[i], -possible*component.Quantity, MovementOrderConsumption); err != nil {
+			return nil, err
+		}
+	}
+
+	allocation.Allocated = possible
+	allocation.Remaining = quantity - possible
+	allocation.CurrentStock = bundlesOnHand(components, locked)
+	return allocation, nil
+}
+
+// bundlesOnHand is how many whole bundles the locked component rows make up.
+func bundlesOnHand(components []BundleComponent, locked []*Inventory) int {
+	bundles := -1
+	for i, component := range components {
+		whole := max(locked[i].Quantity, 0) / component.Quantity
+		if bundles < 0 || whole < bundles {
+			bundles = whole
+		}
+	}
+	return max(bundles, 0)
+}
+
+// expandBundleLines replaces bundle lines with one line per component, so
+// ordering a bundle decrements its components.
+func expandBundleLines(tx *gorm.DB, lines []StockLine) ([]StockLine, error) {
+	expanded := make([]StockLine, 0, len(lines))
+	for _, line := range lines {
+		components, err := bundleComponents(tx, line.SkuID)
+		if err != nil {
+			return nil, err
+		}
+		if len(components) == 0 {
+			expanded = append(expanded, line)
+			continue
+		}
+		for _, component := range components {
+			expanded = append(expanded, StockLine{
+				SkuID:    component.ComponentSkuID,
+				HubID:    line.HubID,
+				Quantity: line.Quantity * component.Quantity,
+			})
+		}
+	}
+	return expanded, nil
+}
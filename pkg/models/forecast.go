@@ -0,0 +1,289 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/constants"
+	"github.com/aditya-goyal-omniful/ims/pkg/forecast"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Movement reasons that ship stock to customers. Their decrements are the
+// demand history forecasts are built from.
+var demandReasons = []string{MovementOrderConsumption, MovementReservationCommit, MovementSerialDispatch}
+
+// DemandForecast is the latest daily demand forecast of a SKU at a hub and
+// the reorder it suggests.
+type DemandForecast struct {
+	HubID                uuid.UUID `gorm:"type:uuid;primaryKey" json:"hub_id"`
+	SkuID                uuid.UUID `gorm:"type:uuid;primaryKey" json:"sku_id"`
+	TenantID             uuid.UUID `gorm:"type:uuid;not null" json:"tenant_id"`
+	ForecastDate         time.Time `gorm:"type:date;not null" json:"forecast_date"`
+	MovingAverage        float64   `gorm:"type:numeric(14,4);not null" json:"moving_average"`        // units per day
+	ExponentialSmoothing float64   `gorm:"type:numeric(14,4);not null" json:"exponential_smoothing"` // units per day; drives the suggestion
+	LeadTimeDays         int       `gorm:"not null" json:"lead_time_days"`
+	DaysOfCover          int       `gorm:"column:target_days_of_cover;not null" json:"target_days_of_cover"`
+	OnHand               int       `gorm:"not null" json:"on_hand"`
+	Reserved             int       `gorm:"not null" json:"reserved"`
+	Inbound              int       `gorm:"not null" json:"inbound"` // in-transit shipping notices and dispatched transfers, as in ATP
+	SafetyStock          int       `gorm:"not null" json:"safety_stock"`
+	SuggestedQuantity    int       `gorm:"not null" json:"suggested_quantity"`
+	ComputedAt           time.Time `gorm:"not null" json:"computed_at"`
+}
+
+// DemandForecastRun marks a tenant's forecasts of a day as built, so
+// concurrent refreshes build them once.
+type DemandForecastRun struct {
+	TenantID     uuid.UUID `gorm:"type:uuid;primaryKey" json:"tenant_id"`
+	ForecastDate time.Time `gorm:"type:date;primaryKey" json:"forecast_date"`
+	ComputedAt   time.Time `gorm:"not null" json:"computed_at"`
+	RowCount     int64     `gorm:"not null;default:0" json:"row_count"`
+}
+
+type DemandForecastFilter struct {
+	HubID       *uuid.UUID
+	SkuID       *uuid.UUID
+	ReorderOnly bool // only rows with something to reorder
+}
+
+type DemandForecastModel struct{}
+
+type demandPosition struct {
+	HubID        uuid.UUID
+	SkuID        uuid.UUID
+	OnHand       int
+	Reserved     int
+	Inbound      int
+	SafetyStock  int
+	LeadTimeDays *int
+	DaysOfCover  *int `gorm:"column:target_days_of_cover"`
+}
+
+type dailyDemand struct {
+	HubID    uuid.UUID
+	SkuID    uuid.UUID
+	Day      time.Time
+	Quantity int
+}
+
+type demandKey struct {
+	HubID uuid.UUID
+	SkuID uuid.UUID
+}
+
+// GetDemandForecasts
+
+func (d DemandForecastModel) GetDemandForecasts(ctx context.Context, tenantID uuid.UUID, filter DemandForecastFilter) ([]DemandForecast, error) {
+	return GetDemandForecasts(ctx, tenantID, filter)
+}
+
+// GetDemandForecasts lists the tenant's latest forecasts, largest suggested
+// reorder first.
+func GetDemandForecasts(ctx context.Context, tenantID uuid.UUID, filter DemandForecastFilter) ([]DemandForecast, error) {
+	query := getDB(ctx).Where("tenant_id = ?", tenantID)
+	if filter.HubID != nil {
+		query = query.Where("hub_id = ?", *filter.HubID)
+	}
+	if filter.SkuID != nil {
+		query = query.Where("sku_id = ?", *filter.SkuID)
+	}
+	if filter.ReorderOnly {
+		query = query.Where("suggested_quantity > 0")
+	}
+
+	var forecasts []DemandForecast
+	err := query.Order("suggested_quantity DESC, hub_id, sku_id").Find(&forecasts).Error
+	return forecasts, err
+}
+
+// RefreshDemandForecasts rebuilds the forecasts of every tenant that has not
+// had today's (UTC) built and returns how many rows it wrote, so it is safe
+// to run more often than daily and from several instances at once.
+func RefreshDemandForecasts(ctx context.Context) (int64, error) {
+	var tenantIDs []uuid.UUID
+	if err := getDB(ctx).Model(&Tenant{}).Pluck("id", &tenantIDs).Error; err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var written int64
+	for _, tenantID := range tenantIDs {
+		err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+			// A concurrent refresh waits on the claim and then finds it taken
+			run := &DemandForecastRun{TenantID: tenantID, ForecastDate: today, ComputedAt: now}
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(run)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return nil
+			}
+
+			forecasts, err := buildDemandForecasts(tx, tenantID, today, now)
+			if err != nil {
+				return err
+			}
+
+			// Rows of deleted inventories go too
+			if err := tx.Where("tenant_id = ?", tenantID).Delete(&DemandForecast{}).Error; err != nil {
+				return err
+			}
+			if len(forecasts) == 0 {
+				return nil
+			}
+			if err := tx.CreateInBatches(forecasts, 500).Error; err != nil {
+				return err
+			}
+			err = tx.Model(&DemandForecastRun{}).Where("tenant_id = ? AND forecast_date = ?", tenantID, today).
+				Update("row_count", len(forecasts)).Error
+			if err != nil {
+				return err
+			}
+			written += int64(len(forecasts))
+			return nil
+		})
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// buildDemandForecasts forecasts every inventory row of the tenant from its
+// demand over the complete days before today. A series starts at the row's
+// first demand, or the start of the lookback window if that is older, so a
+// new SKU is not diluted by days before it sold. Later days without demand
+// count as zero, so a SKU that stopped selling forecasts towards nothing.
+func buildDemandForecasts(tx *gorm.DB, tenantID uuid.UUID, today, now time.Time) ([]DemandForecast, error) {
+	positions, err := demandPositions(tx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var firsts []dailyDemand
+	err = tx.Raw(`
+		SELECT hub_id, sku_id, MIN((created_at AT TIME ZONE 'UTC')::date) AS day
+		FROM inventory_movements
+		WHERE tenant_id = ? AND reason IN ? AND delta < 0 AND created_at < ?
+		GROUP BY hub_id, sku_id
+	`, tenantID, demandReasons, today).Scan(&firsts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	since := today.AddDate(0, 0, -constants.ForecastLookbackDays)
+	starts := make(map[demandKey]time.Time, len(firsts))
+	series := make(map[demandKey][]int, len(firsts))
+	for _, f := range firsts {
+		key := demandKey{HubID: f.HubID, SkuID: f.SkuID}
+		start := f.Day
+		if start.Before(since) {
+			start = since
+		}
+		starts[key] = start
+		series[key] = make([]int, int(today.Sub(start).Hours()/24))
+	}
+
+	var days []dailyDemand
+	err = tx.Raw(`
+		SELECT hub_id, sku_id, (created_at AT TIME ZONE 'UTC')::date AS day, SUM(-delta) AS quantity
+		FROM inventory_movements
+		WHERE tenant_id = ? AND reason IN ? AND delta < 0 AND created_at >= ? AND created_at < ?
+		GROUP BY hub_id, sku_id, day
+	`, tenantID, demandReasons, since, today).Scan(&days).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range days {
+		key := demandKey{HubID: d.HubID, SkuID: d.SkuID}
+		index := int(d.Day.Sub(starts[key]).Hours() / 24)
+		if index >= 0 && index < len(series[key]) {
+			series[key][index] += d.Quantity
+		}
+	}
+
+	forecasts := make([]DemandForecast, 0, len(positions))
+	for _, p := range positions {
+		// Rows that never sold have no series and forecast zero demand
+		daily := series[demandKey{HubID: p.HubID, SkuID: p.SkuID}]
+
+		leadTime := constants.DefaultLeadTimeDays
+		if p.LeadTimeDays != nil {
+			leadTime = *p.LeadTimeDays
+		}
+		cover := constants.DefaultTargetDaysOfCover
+		if p.DaysOfCover != nil {
+			cover = *p.DaysOfCover
+		}
+
+		smoothed := forecast.ExponentialSmoothing(daily, constants.ForecastSmoothingAlpha)
+		forecasts = append(forecasts, DemandForecast{
+			HubID:                p.HubID,
+			SkuID:                p.SkuID,
+			TenantID:             tenantID,
+			ForecastDate:         today,
+			MovingAverage:        forecast.MovingAverage(daily, constants.ForecastMovingAverageDays),
+			ExponentialSmoothing: smoothed,
+			LeadTimeDays:         leadTime,
+			DaysOfCover:          cover,
+			OnHand:               p.OnHand,
+			Reserved:             p.Reserved,
+			Inbound:              p.Inbound,
+			SafetyStock:          p.SafetyStock,
+			SuggestedQuantity: forecast.ReorderQuantity(smoothed, leadTime, cover, forecast.Position{
+				OnHand:      p.OnHand,
+				Reserved:    p.Reserved,
+				Inbound:     p.Inbound,
+				SafetyStock: p.SafetyStock,
+			}),
+			ComputedAt: now,
+		})
+	}
+
+	return forecasts, nil
+}
+
+// demandPositions loads each inventory row of the tenant with its active
+// reservations and the stock confirmed to arrive, counted the same way as
+// ATP counts inbound.
+func demandPositions(tx *gorm.DB, tenantID uuid.UUID) ([]demandPosition, error) {
+	var positions []demandPosition
+	err := tx.Raw(`
+		SELECT
+			i.hub_id,
+			i.sku_id,
+			i.quantity AS on_hand,
+			COALESCE(r.reserved, 0) AS reserved,
+			i.safety_stock,
+			i.lead_time_days,
+			i.target_days_of_cover
+		FROM inventories i
+		LEFT JOIN (
+			SELECT hub_id, sku_id, SUM(quantity) AS reserved
+			FROM reservations
+			WHERE tenant_id = ? AND status = ? AND expires_at > NOW()
+			GROUP BY hub_id, sku_id
+		) r ON r.hub_id = i.hub_id AND r.sku_id = i.sku_id
+		WHERE i.tenant_id = ?
+	`, tenantID, ReservationActive, tenantID).Scan(&positions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	inbound, err := inboundStock(tx, tenantID, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range positions {
+		positions[i].Inbound = inbound[stockRowKey{positions[i].HubID, positions[i].SkuID}]
+	}
+
+	return positions, nil
+}
@@ -0,0 +1,39 @@
+//go:build integration
+
+package models
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRefreshDemandForecastsConcurrently checks that refreshes running at
+// once build a tenant's day once instead of colliding on its rows.
+func TestRefreshDemandForecastsConcurrently(t *testing.T) {
+	ctx := context.Background()
+	inv := seedStock(t, ctx, 10)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = RefreshDemandForecasts(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	var runs, forecasts int64
+	assert.NoError(t, getDB(ctx).Model(&DemandForecastRun{}).Where("tenant_id = ?", inv.TenantID).Count(&runs).Error)
+	assert.NoError(t, getDB(ctx).Model(&DemandForecast{}).Where("tenant_id = ?", inv.TenantID).Count(&forecasts).Error)
+	assert.Equal(t, int64(1), runs)
+	assert.Equal(t, int64(1), forecasts)
+}
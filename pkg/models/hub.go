@@ -17,12 +17,15 @@ import (
 type HubModel struct{}
 
 type Hub struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Name      string    `gorm:"not null" json:"name"`
-	Location  string    `json:"location"`
-	TenantID  uuid.UUID `gorm:"not null" json:"tenant_id"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name       string    `gorm:"not null" json:"name"`
+	Location   string    `json:"location"`
+	PostalCode string    `json:"postal_code"`
+	Latitude   *float64  `json:"latitude"`
+	Longitude  *float64  `json:"longitude"`
+	TenantID   uuid.UUID `gorm:"not null" json:"tenant_id"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 func getDB(ctx context.Context) *gorm.DB {
@@ -0,0 +1,117 @@
+//go:build integration
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/configs"
+	"github.com/google/uuid"
+	"github.com/omniful/go_commons/db/sql/migration"
+	"github.com/omniful/go_commons/db/sql/postgres"
+)
+
+// Integration tests run against a throwaway Postgres database:
+//
+//	IMS_TEST_POSTGRES_HOST=localhost IMS_TEST_POSTGRES_NAME=ims_test go test -tags integration ./pkg/models/
+//
+// The migrations are applied first, and every test seeds its own tenant.
+
+func testEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func TestMain(m *testing.M) {
+	dbConfig := postgres.DBConfig{
+		Host:                   testEnv("IMS_TEST_POSTGRES_HOST", "localhost"),
+		Port:                   testEnv("IMS_TEST_POSTGRES_PORT", "5432"),
+		Username:               testEnv("IMS_TEST_POSTGRES_USER", "postgres"),
+		Password:               testEnv("IMS_TEST_POSTGRES_PASSWORD", "postgres"),
+		Dbname:                 testEnv("IMS_TEST_POSTGRES_NAME", "ims_test"),
+		MaxOpenConnections:     20,
+		MaxIdleConnections:     5,
+		ConnMaxLifetime:        time.Minute,
+		SkipDefaultTransaction: true,
+	}
+
+	dsn := fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		dbConfig.Username,
+		dbConfig.Password,
+		dbConfig.Host,
+		dbConfig.Port,
+		dbConfig.Dbname,
+	)
+
+	migrator, err := migration.InitializeMigrate("file://../../migrations", dsn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize migrator: %v\n", err)
+		os.Exit(1)
+	}
+	if err := migrator.Up(); err != nil {
+		fmt.Fprintf(os.Stderr, "migration failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	slaves := []postgres.DBConfig{}
+	configs.DB = postgres.InitializeDBInstance(dbConfig, &slaves)
+
+	os.Exit(m.Run())
+}
+
+// seedTenant creates a tenant with one seller and returns both IDs.
+func seedTenant(t *testing.T, ctx context.Context) (tenantID, sellerID uuid.UUID) {
+	t.Helper()
+	suffix := uuid.NewString()
+	if err := getDB(ctx).Raw("INSERT INTO tenants (name) VALUES (?) RETURNING id", "tenant-"+suffix).Scan(&tenantID).Error; err != nil {
+		t.Fatalf("seed tenant: %v", err)
+	}
+	if err := getDB(ctx).Raw("INSERT INTO sellers (name, tenant_id) VALUES (?, ?) RETURNING id", "seller-"+suffix, tenantID).Scan(&sellerID).Error; err != nil {
+		t.Fatalf("seed seller: %v", err)
+	}
+	return tenantID, sellerID
+}
+
+func seedHub(t *testing.T, ctx context.Context, tenantID uuid.UUID) uuid.UUID {
+	t.Helper()
+	var hubID uuid.UUID
+	if err := getDB(ctx).Raw("INSERT INTO hubs (name, tenant_id) VALUES (?, ?) RETURNING id", "hub-"+uuid.NewString(), tenantID).Scan(&hubID).Error; err != nil {
+		t.Fatalf("seed hub: %v", err)
+	}
+	return hubID
+}
+
+func seedSku(t *testing.T, ctx context.Context, tenantID, sellerID uuid.UUID) uuid.UUID {
+	t.Helper()
+	var skuID uuid.UUID
+	code := "SKU-" + uuid.NewString()
+	if err := getDB(ctx).Raw("INSERT INTO skus (name, sku_code, seller_id, tenant_id) VALUES (?, ?, ?, ?) RETURNING id",
+		code, code, sellerID, tenantID).Scan(&skuID).Error; err != nil {
+		t.Fatalf("seed sku: %v", err)
+	}
+	return skuID
+}
+
+func seedInventory(t *testing.T, ctx context.Context, tenantID, hubID, skuID uuid.UUID, quantity int) *Inventory {
+	t.Helper()
+	// New tenants have no approval thresholds, so the starting stock applies at once
+	inv := &Inventory{TenantID: tenantID, HubID: hubID, SkuID: skuID, Quantity: quantity, ReasonCode: "found"}
+	if err := CreateInventory(WithMovementMeta(ctx, MovementMeta{Actor: "seed"}), inv); err != nil {
+		t.Fatalf("seed inventory: %v", err)
+	}
+	return inv
+}
+
+// seedStock creates a tenant with one hub and one SKU holding quantity units.
+func seedStock(t *testing.T, ctx context.Context, quantity int) *Inventory {
+	t.Helper()
+	tenantID, sellerID := seedTenant(t, ctx)
+	return seedInventory(t, ctx, tenantID, seedHub(t, ctx, tenantID), seedSku(t, ctx, tenantID, sellerID), quantity)
+}
@@ -2,27 +2,48 @@ package models
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+var (
+	ErrInsufficientStock  = errors.New("insufficient stock")
+	ErrInventoryKeyChange = errors.New("tenant_id, hub_id and sku_id of an inventory row cannot be changed")
+	ErrInventoryNotEmpty  = errors.New("adjust the quantity to zero before deleting the inventory")
+)
+
 type Inventory struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	TenantID  uuid.UUID `gorm:"type:uuid;not null" json:"tenant_id"`
-	HubID     uuid.UUID `gorm:"type:uuid;not null" json:"hub_id"`
-	SkuID     uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
-	Quantity  int       `gorm:"not null" json:"quantity"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID           uuid.UUID            `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID     uuid.UUID            `gorm:"type:uuid;not null" json:"tenant_id"`
+	HubID        uuid.UUID            `gorm:"type:uuid;not null" json:"hub_id"`
+	SkuID        uuid.UUID            `gorm:"type:uuid;not null" json:"sku_id"`
+	Quantity     int                  `gorm:"not null" json:"quantity"`
+	MinQuantity  *int                 `json:"min_quantity"`
+	ReorderPoint *int                 `json:"reorder_point"`
+	SafetyStock  int                  `gorm:"not null;default:0" json:"safety_stock"`
+	LeadTimeDays *int                 `json:"lead_time_days"`
+	DaysOfCover  *int                 `gorm:"column:target_days_of_cover" json:"target_days_of_cover"`
+	Unit         string               `gorm:"-" json:"unit,omitempty"`        // unit of Quantity on writes; stored in the SKU's base unit
+	UnitCost     *float64             `gorm:"-" json:"unit_cost,omitempty"`   // cost per base unit of any quantity added by a write
+	ReasonCode   string               `gorm:"-" json:"reason_code,omitempty"` // adjustment reason of a quantity change on writes
+	Adjustment   *InventoryAdjustment `gorm:"-" json:"adjustment,omitempty"`  // recorded by a quantity change on a write
+	CreatedAt    time.Time            `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time            `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 type InventoryView struct {
 	SkuID    uuid.UUID `json:"sku_id"`
 	SkuCode  string    `json:"sku_code"`
 	SkuName  string    `json:"sku_name"`
-	Quantity int       `json:"quantity"`
+	Bundle   bool      `json:"bundle"` // figures are whole bundles the components make up
+	OnHand   int       `json:"on_hand"`
+	Reserved int       `json:"reserved"`
+	Quantity int       `json:"quantity"` // available: on hand minus active reservations
 }
 
 type InventoryModel struct{}
@@ -61,6 +82,9 @@ func (i InventoryModel) CreateInventory(ctx context.Context, inv *Inventory) err
 	return CreateInventory(ctx, inv)
 }
 
+// CreateInventory adds a hub/SKU row. Its starting quantity is booked as an
+// adjustment, as in UpdateInventory, so the row may start empty while the
+// adjustment waits for approval.
 func CreateInventory(ctx context.Context, inventory *Inventory) error {
 	// Check if tenant exists before creating inventory
 	_, err := GetTenant(ctx, inventory.TenantID)
@@ -68,10 +92,32 @@ func CreateInventory(ctx context.Context, inventory *Inventory) error {
 		return err // This will be a gorm.ErrRecordNotFound if tenant doesn't exist
 	}
 
-	if err := getDB(ctx).Create(inventory).Error; err != nil {
+	ctx, err = withUnitCost(ctx, inventory.UnitCost)
+	if err != nil {
 		return err
 	}
-	return nil
+
+	return getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := convertInventoryUnit(tx, inventory, inventory.SkuID); err != nil {
+			return err
+		}
+		if err := ensureNotBundle(tx, inventory.SkuID); err != nil {
+			return err
+		}
+
+		quantity := inventory.Quantity
+		inventory.Quantity = 0
+		if err := tx.Create(inventory).Error; err != nil {
+			return err
+		}
+
+		adj, err := adjustQuantity(ctx, tx, inventory, quantity, inventory.ReasonCode)
+		if err != nil {
+			return err
+		}
+		inventory.Adjustment = adj
+		return nil
+	})
 }
 
 // DeleteInventory
@@ -80,27 +126,79 @@ func (i InventoryModel) DeleteInventory(ctx context.Context, id uuid.UUID) (*Inv
 	return DeleteInventory(ctx, id)
 }
 
+// DeleteInventory removes an empty row. Stock has to leave through an
+// adjustment first, so nothing is written off unchecked.
 func DeleteInventory(ctx context.Context, id uuid.UUID) (*Inventory, error) {
-	var inventory Inventory
-	if err := getDB(ctx).First(&inventory, "id = ?", id).Error; err != nil {
-		return nil, err
-	}
-
-	if err := getDB(ctx).Delete(&inventory).Error; err != nil {
+	var inventory *Inventory
+
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		inventory, err = lockInventoryByID(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if inventory.Quantity != 0 {
+			return ErrInventoryNotEmpty
+		}
+		return tx.Delete(inventory).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &inventory, nil
+	return inventory, nil
 }
 
 // UpdateInventory
 
-func (i InventoryModel) UpdateInventory(ctx context.Context, id uuid.UUID, updated *Inventory) error {
-	return UpdateInventory(ctx, id, updated)
+func (i InventoryModel) UpdateInventory(ctx context.Context, id uuid.UUID, updated *Inventory, quantity *int) error {
+	return UpdateInventory(ctx, id, updated, quantity)
 }
 
-func UpdateInventory(ctx context.Context, id uuid.UUID, updated *Inventory) error {
-	return getDB(ctx).Model(&Inventory{}).Where("id = ?", id).Updates(updated).Error
+// UpdateInventory changes the fields of a row. A non-nil quantity, zero
+// included, goes through the adjustment flow like POST /adjustments does, so
+// it needs a reason code and waits for approval above the tenant's
+// thresholds. updated.Quantity is ignored.
+func UpdateInventory(ctx context.Context, id uuid.UUID, updated *Inventory, quantity *int) error {
+	updated.Quantity = 0
+	if quantity != nil {
+		updated.Quantity = *quantity
+	}
+
+	ctx, err := withUnitCost(ctx, updated.UnitCost)
+	if err != nil {
+		return err
+	}
+
+	return getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		current, err := lockInventoryByID(tx, id)
+		if err != nil {
+			return err
+		}
+
+		// Lots, bins, serials and cost layers are keyed by hub and SKU, so a
+		// row never moves; stock moves with a transfer or adjustments
+		if (updated.TenantID != uuid.Nil && updated.TenantID != current.TenantID) ||
+			(updated.HubID != uuid.Nil && updated.HubID != current.HubID) ||
+			(updated.SkuID != uuid.Nil && updated.SkuID != current.SkuID) {
+			return ErrInventoryKeyChange
+		}
+		if err := convertInventoryUnit(tx, updated, current.SkuID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&Inventory{}).Where("id = ?", id).
+			Omit("quantity", "tenant_id", "hub_id", "sku_id").Updates(updated).Error; err != nil {
+			return err
+		}
+		if quantity == nil {
+			return nil
+		}
+
+		updated.Adjustment, err = adjustQuantity(ctx, tx, current, updated.Quantity-current.Quantity, updated.ReasonCode)
+		return err
+	})
 }
 
 // UpsertInventory
@@ -109,48 +207,170 @@ func (i InventoryModel) UpsertInventory(ctx context.Context, inv *Inventory) err
 	return UpsertInventory(ctx, inv)
 }
 
+// UpsertInventory sets the quantity of a hub/SKU row, creating the row if
+// needed. The change goes through the adjustment flow, as in UpdateInventory.
 func UpsertInventory(ctx context.Context, inventory *Inventory) error {
 	// Validate tenant exists
 	if _, err := GetTenant(ctx, inventory.TenantID); err != nil {
 		return err
 	}
 
-	db := getDB(ctx)
+	ctx, err := withUnitCost(ctx, inventory.UnitCost)
+	if err != nil {
+		return err
+	}
 
-	// Atomic UPSERT: (sku_id, hub_id) must be unique for this to work properly
-	return db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "sku_id"}, {Name: "hub_id"}}, // conflict target
-		DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
-	}).Create(inventory).Error
+	return getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := convertInventoryUnit(tx, inventory, inventory.SkuID); err != nil {
+			return err
+		}
+
+		current, err := lockOrCreateInventory(tx, inventory.TenantID, inventory.HubID, inventory.SkuID)
+		if err != nil {
+			return err
+		}
+
+		adj, err := adjustQuantity(ctx, tx, current, inventory.Quantity-current.Quantity, inventory.ReasonCode)
+		if err != nil {
+			return err
+		}
+
+		*inventory = *current
+		inventory.Adjustment = adj
+		return nil
+	})
+}
+
+// adjustQuantity records a write's quantity change on a row locked in tx as
+// an adjustment requested by the caller's actor. It returns nil when there is
+// nothing to change.
+func adjustQuantity(ctx context.Context, tx *gorm.DB, inv *Inventory, delta int, reasonCode string) (*InventoryAdjustment, error) {
+	if delta == 0 {
+		return nil, nil
+	}
+
+	requester := movementMetaFromContext(ctx).Actor
+	if reasonCode == "" || requester == "" {
+		return nil, ErrQuantityNeedsReason
+	}
+
+	adj := &InventoryAdjustment{
+		TenantID:    inv.TenantID,
+		InventoryID: inv.ID,
+		Delta:       delta,
+		ReasonCode:  reasonCode,
+		RequestedBy: requester,
+	}
+	if err := requestAdjustment(ctx, tx, adj, inv); err != nil {
+		return nil, err
+	}
+	return adj, nil
 }
 
 // GetInventoryWithDefaults
 
-func (i InventoryModel) GetInventoryWithDefaults(ctx context.Context, tenantID, hubID uuid.UUID) ([]InventoryView, error) {
-	return GetInventoryWithDefaults(ctx, tenantID, hubID)
+func (i InventoryModel) GetInventoryWithDefaults(ctx context.Context, tenantID, hubID uuid.UUID, asOf *time.Time) ([]InventoryView, error) {
+	return GetInventoryWithDefaults(ctx, tenantID, hubID, asOf)
+}
+
+// GetInventoryWithDefaults lists every SKU of the tenant at a hub, with zero
+// for SKUs not stocked there. Bundles show the whole bundles their
+// components make up. With asOf it shows the hub as it stood just before
+// that instant, rebuilt from snapshots and the ledger.
+func GetInventoryWithDefaults(ctx context.Context, tenantID, hubID uuid.UUID, asOf *time.Time) ([]InventoryView, error) {
+	if asOf != nil {
+		return inventoryViewsAsOf(getDB(ctx), tenantID, hubID, *asOf)
+	}
+	return inventoryViews(getDB(ctx), tenantID, hubID, nil)
 }
 
-func GetInventoryWithDefaults(ctx context.Context, tenantID, hubID uuid.UUID) ([]InventoryView, error) {
-	var result []InventoryView
-	db := getDB(ctx)
+// inventoryViews builds the hub view of the tenant's SKUs, or of skuIDs
+// only when they are given.
+func inventoryViews(db *gorm.DB, tenantID, hubID uuid.UUID, skuIDs []uuid.UUID) ([]InventoryView, error) {
+	rows, err := hubInventoryViews(db, tenantID, []uuid.UUID{hubID}, skuIDs)
+	if err != nil {
+		return nil, err
+	}
 
-	err := db.Raw(`
-		SELECT 
+	views := make([]InventoryView, len(rows))
+	for i, row := range rows {
+		views[i] = row.InventoryView
+	}
+	return views, nil
+}
+
+type hubInventoryView struct {
+	HubID uuid.UUID
+	InventoryView
+}
+
+// hubInventoryViews builds the views of several hubs in one query, ordered
+// by hub.
+func hubInventoryViews(db *gorm.DB, tenantID uuid.UUID, hubIDs, skuIDs []uuid.UUID) ([]hubInventoryView, error) {
+	if len(hubIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		WITH hub_list AS (
+			SELECT id AS hub_id FROM hubs WHERE id IN ?
+		),
+		reserved AS (
+			SELECT hub_id, sku_id, SUM(quantity) AS reserved
+			FROM reservations
+			WHERE hub_id IN (SELECT hub_id FROM hub_list) AND status = ? AND expires_at > NOW()
+			GROUP BY hub_id, sku_id
+		),
+		bundles AS (
+			SELECT
+				h.hub_id,
+				b.bundle_sku_id,
+				MIN(GREATEST(COALESCE(ci.quantity, 0), 0) / b.quantity) AS on_hand,
+				MIN(GREATEST(COALESCE(ci.quantity, 0) - COALESCE(cr.reserved, 0), 0) / b.quantity) AS quantity
+			FROM bundle_components b
+			CROSS JOIN hub_list h
+			LEFT JOIN inventories ci ON ci.sku_id = b.component_sku_id AND ci.hub_id = h.hub_id
+			LEFT JOIN reserved cr ON cr.sku_id = b.component_sku_id AND cr.hub_id = h.hub_id
+			GROUP BY h.hub_id, b.bundle_sku_id
+		)
+		SELECT
+			h.hub_id,
 			s.id AS sku_id,
 			s.sku_code,
 			s.name AS sku_name,
-			COALESCE(i.quantity, 0) AS quantity
-		FROM skus s
-		LEFT JOIN inventories i 
-			ON s.id = i.sku_id AND i.hub_id = ? AND i.tenant_id = s.tenant_id
-		WHERE s.tenant_id = ?
-	`, hubID, tenantID).Scan(&result).Error
+			bd.bundle_sku_id IS NOT NULL AS bundle,
+			CASE WHEN bd.bundle_sku_id IS NOT NULL THEN bd.on_hand
+				ELSE COALESCE(i.quantity, 0) END AS on_hand,
+			CASE WHEN bd.bundle_sku_id IS NOT NULL THEN bd.on_hand - bd.quantity
+				ELSE COALESCE(r.reserved, 0) END AS reserved,
+			CASE WHEN bd.bundle_sku_id IS NOT NULL THEN bd.quantity
+				ELSE COALESCE(i.quantity, 0) - COALESCE(r.reserved, 0) END AS quantity
+		FROM hub_list h
+		CROSS JOIN skus s
+		LEFT JOIN inventories i
+			ON s.id = i.sku_id AND i.hub_id = h.hub_id AND i.tenant_id = s.tenant_id
+		LEFT JOIN reserved r ON r.hub_id = h.hub_id AND r.sku_id = s.id
+		LEFT JOIN bundles bd ON bd.hub_id = h.hub_id AND bd.bundle_sku_id = s.id
+		WHERE s.tenant_id = ?`
+	args := []interface{}{hubIDs, ReservationActive, tenantID}
+
+	if skuIDs != nil {
+		query += " AND s.id IN ?"
+		args = append(args, skuIDs)
+	}
+	query += " ORDER BY h.hub_id"
 
+	var result []hubInventoryView
+	err := db.Raw(query, args...).Scan(&result).Error
 	return result, err
 }
 
 // GetInventoryBySkuHub
 
+func (m InventoryModel) GetInventoryBySkuHub(ctx context.Context, skuID, hubID uuid.UUID) (*Inventory, error) {
+	return GetInventoryBySkuHub(ctx, skuID, hubID)
+}
+
 func GetInventoryBySkuHub(ctx context.Context, skuID, hubID uuid.UUID) (*Inventory, error) {
 	var inv Inventory
 	err := getDB(ctx).Where("sku_id = ? AND hub_id = ?", skuID, hubID).First(&inv).Error
@@ -160,16 +380,235 @@ func GetInventoryBySkuHub(ctx context.Context, skuID, hubID uuid.UUID) (*Invento
 	return &inv, nil
 }
 
-// UpdateInventoryQuantity
+// convertInventoryUnit rewrites the quantity of a create or update from its
+// unit to the SKU's base unit, which is what inventories store.
+func convertInventoryUnit(tx *gorm.DB, inv *Inventory, skuID uuid.UUID) error {
+	quantity, err := toBaseQuantity(tx, skuID, inv.Unit, inv.Quantity)
+	if err != nil {
+		return err
+	}
+	inv.Quantity = quantity
+	inv.Unit = ""
+	return nil
+}
 
-func (m InventoryModel) GetInventoryBySkuHub(ctx context.Context, skuID, hubID uuid.UUID) (*Inventory, error) {
-	return GetInventoryBySkuHub(ctx, skuID, hubID)
+// lockInventoryByID loads the row with a FOR UPDATE lock held for the rest of tx.
+func lockInventoryByID(tx *gorm.DB, id uuid.UUID) (*Inventory, error) {
+	var inv Inventory
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&inv, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &inv, nil
 }
 
-func (m InventoryModel) UpdateInventoryQuantity(ctx context.Context, invID uuid.UUID, newQty int) error {
-	return UpdateInventoryQuantity(ctx, invID, newQty)
+// lockOrCreateInventory locks the hub/SKU row, inserting it with zero
+// quantity first if it does not exist yet. (sku_id, hub_id) is unique, so
+// concurrent callers end up locking the same row.
+func lockOrCreateInventory(tx *gorm.DB, tenantID, hubID, skuID uuid.UUID) (*Inventory, error) {
+	if err := ensureNotBundle(tx, skuID); err != nil {
+		return nil, err
+	}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "sku_id"}, {Name: "hub_id"}}, // conflict target
+		DoNothing: true,
+	}).Create(&Inventory{TenantID: tenantID, HubID: hubID, SkuID: skuID}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return lockInventoryBySkuHub(tx, skuID, hubID)
 }
 
-func UpdateInventoryQuantity(ctx context.Context, id uuid.UUID, quantity int) error {
-	return getDB(ctx).Model(&Inventory{}).Where("id = ?", id).Update("quantity", quantity).Error
+// lockInventoryBySkuHub loads the hub/SKU row with a FOR UPDATE lock so that
+// quantity changes on it are serialised for the rest of tx.
+func lockInventoryBySkuHub(tx *gorm.DB, skuID, hubID uuid.UUID) (*Inventory, error) {
+	var inv Inventory
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("sku_id = ? AND hub_id = ?", skuID, hubID).
+		First(&inv).Error
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// AllocateInventory
+
+type StockAllocation struct {
+	Requested    int `json:"requested"`
+	Allocated    int `json:"allocated"`
+	Remaining    int `json:"remaining"`     // requested but not allocated
+	CurrentStock int `json:"current_stock"` // on hand after the allocation
+}
+
+func (m InventoryModel) AllocateInventory(ctx context.Context, skuID, hubID uuid.UUID, quantity int, allowPartial bool) (*StockAllocation, error) {
+	return AllocateInventory(ctx, skuID, hubID, quantity, allowPartial)
+}
+
+// AllocateInventory checks and takes quantity off a hub/SKU under a row lock,
+// so concurrent orders cannot both pass the check. Without allowPartial it
+// takes all or nothing; with it, it takes whatever unreserved stock there is.
+func AllocateInventory(ctx context.Context, skuID, hubID uuid.UUID, quantity int, allowPartial bool) (*StockAllocation, error) {
+	allocation := &StockAllocation{Requested: quantity, Remaining: quantity}
+
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		components, err := bundleComponents(tx, skuID)
+		if err != nil {
+			return err
+		}
+		if len(components) > 0 {
+			allocation, err = allocateBundle(ctx, tx, components, hubID, quantity, allowPartial)
+			return err
+		}
+
+		inv, err := lockInventoryBySkuHub(tx, skuID, hubID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		allocation.CurrentStock = inv.Quantity
+
+		reserved, err := activeReservedQuantity(tx, skuID, hubID)
+		if err != nil {
+			return err
+		}
+
+		take := min(max(inv.Quantity-reserved, 0), quantity)
+		if take < quantity && !allowPartial {
+			return nil
+		}
+
+		if err := applyQuantityChange(ctx, tx, inv, -take, MovementOrderConsumption); err != nil {
+			return err
+		}
+
+		allocation.Allocated = take
+		allocation.Remaining = quantity - take
+		allocation.CurrentStock = inv.Quantity
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return allocation, nil
+}
+
+// DecrementInventoryLines
+
+type StockLine struct {
+	SkuID    uuid.UUID `json:"sku_id"`
+	HubID    uuid.UUID `json:"hub_id"`
+	Quantity int       `json:"quantity"`
+	Unit     string    `json:"unit,omitempty"` // defaults to the SKU's base unit
+}
+
+type StockShortage struct {
+	SkuID     uuid.UUID `json:"sku_id"`
+	HubID     uuid.UUID `json:"hub_id"`
+	Requested int       `json:"requested"`
+	Available int       `json:"available"`
+	ShortBy   int       `json:"short_by"`
+}
+
+func (m InventoryModel) DecrementInventoryLines(ctx context.Context, lines []StockLine) ([]StockShortage, error) {
+	return DecrementInventoryLines(ctx, lines)
+}
+
+// stockRowKey identifies the inventory row of a SKU at a hub.
+type stockRowKey struct{ hubID, skuID uuid.UUID }
+
+// mergeStockLines sums repeated hub/SKU lines and orders the result by hub
+// then SKU, so every caller locks rows in the same order and cannot deadlock.
+func mergeStockLines(lines []StockLine) []StockLine {
+	merged := make(map[stockRowKey]int, len(lines))
+	for _, line := range lines {
+		merged[stockRowKey{line.HubID, line.SkuID}] += line.Quantity
+	}
+
+	result := make([]StockLine, 0, len(merged))
+	for k, qty := range merged {
+		result = append(result, StockLine{SkuID: k.skuID, HubID: k.hubID, Quantity: qty})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].HubID != result[j].HubID {
+			return result[i].HubID.String() < result[j].HubID.String()
+		}
+		return result[i].SkuID.String() < result[j].SkuID.String()
+	})
+	return result
+}
+
+// DecrementInventoryLines takes every line off stock in one transaction, or
+// none of them. Bundle lines take their components. When any line is short
+// nothing is written and the shortages are returned, per component SKU and
+// in base units.
+func DecrementInventoryLines(ctx context.Context, lines []StockLine) ([]StockShortage, error) {
+	var shortages []StockShortage
+
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		converted := make([]StockLine, len(lines))
+		for i, line := range lines {
+			quantity, err := toBaseQuantity(tx, line.SkuID, line.Unit, line.Quantity)
+			if err != nil {
+				return err
+			}
+			converted[i] = StockLine{SkuID: line.SkuID, HubID: line.HubID, Quantity: quantity}
+		}
+
+		expanded, err := expandBundleLines(tx, converted)
+		if err != nil {
+			return err
+		}
+
+		merged := mergeStockLines(expanded)
+		locked := make([]*Inventory, len(merged))
+
+		// Lock and check every line before touching any of them
+		for i, line := range merged {
+			inv, err := lockInventoryBySkuHub(tx, line.SkuID, line.HubID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			available := 0
+			if inv != nil {
+				reserved, err := activeReservedQuantity(tx, line.SkuID, line.HubID)
+				if err != nil {
+					return err
+				}
+				available = inv.Quantity - reserved
+			}
+
+			if available < line.Quantity {
+				shortages = append(shortages, StockShortage{
+					SkuID:     line.SkuID,
+					HubID:     line.HubID,
+					Requested: line.Quantity,
+					Available: max(available, 0),
+					ShortBy:   line.Quantity - max(available, 0),
+				})
+			}
+			locked[i] = inv
+		}
+
+		if len(shortages) > 0 {
+			return nil
+		}
+
+		for i, line := range merged {
+			if err := applyQuantityChange(ctx, tx, locked[i], -line.Quantity, MovementOrderConsumption); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shortages, nil
 }
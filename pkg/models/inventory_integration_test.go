@@ -0,0 +1,76 @@
+//go:build integration
+
+package models
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAllocateInventoryConcurrent fires parallel allocations at one row. The
+// row lock in AllocateInventory must keep the quantity from going negative
+// and the allocations from adding up to more than was in stock.
+func TestAllocateInventoryConcurrent(t *testing.T) {
+	const (
+		initial  = 50
+		perOrder = 3
+		orders   = 40
+	)
+
+	for _, allowPartial := range []bool{false, true} {
+		name := "all or nothing"
+		if allowPartial {
+			name = "partial"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			inv := seedStock(t, ctx, initial)
+
+			var (
+				wg        sync.WaitGroup
+				mu        sync.Mutex
+				allocated int
+				failed    []error
+				start     = make(chan struct{})
+			)
+			for i := 0; i < orders; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+
+					allocation, err := AllocateInventory(ctx, inv.SkuID, inv.HubID, perOrder, allowPartial)
+					mu.Lock()
+					defer mu.Unlock()
+					if err != nil {
+						failed = append(failed, err)
+						return
+					}
+					allocated += allocation.Allocated
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			assert.Empty(t, failed)
+
+			var final Inventory
+			if err := getDB(ctx).First(&final, "id = ?", inv.ID).Error; err != nil {
+				t.Fatalf("reload inventory: %v", err)
+			}
+
+			assert.GreaterOrEqual(t, final.Quantity, 0)
+			assert.LessOrEqual(t, allocated, initial)
+			assert.Equal(t, initial-allocated, final.Quantity)
+			if allowPartial {
+				assert.Equal(t, initial, allocated)
+			} else {
+				assert.Equal(t, initial-initial%perOrder, allocated)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	LocationZone  = "zone"
+	LocationAisle = "aisle"
+	LocationBin   = "bin"
+)
+
+// locationParentType is the type a location's parent must have; zones are roots.
+var locationParentType = map[string]string{
+	LocationZone:  "",
+	LocationAisle: LocationZone,
+	LocationBin:   LocationAisle,
+}
+
+var ErrInvalidLocation = errors.New("invalid location")
+
+type Location struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID  uuid.UUID  `gorm:"type:uuid;not null" json:"tenant_id"`
+	HubID     uuid.UUID  `gorm:"type:uuid;not null" json:"hub_id"`
+	ParentID  *uuid.UUID `gorm:"type:uuid" json:"parent_id"`
+	Type      string     `gorm:"not null" json:"type"`
+	Code      string     `gorm:"not null" json:"code"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type BinInventory struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	InventoryID uuid.UUID `gorm:"type:uuid;not null" json:"inventory_id"`
+	BinID       uuid.UUID `gorm:"type:uuid;not null" json:"bin_id"`
+	Quantity    int       `gorm:"not null" json:"quantity"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type BinStockView struct {
+	BinID    uuid.UUID `json:"bin_id"`
+	BinCode  string    `json:"bin_code"`
+	SkuID    uuid.UUID `json:"sku_id"`
+	SkuCode  string    `json:"sku_code"`
+	Quantity int       `json:"quantity"`
+}
+
+// BinMove moves stock of one SKU inside a hub. A nil bin stands for the
+// hub's unbinned stock, so the same move covers put-away and pick-down.
+type BinMove struct {
+	HubID     uuid.UUID  `json:"hub_id"`
+	SkuID     uuid.UUID  `json:"sku_id"`
+	FromBinID *uuid.UUID `json:"from_bin_id"`
+	ToBinID   *uuid.UUID `json:"to_bin_id"`
+	Quantity  int        `json:"quantity"`
+}
+
+type LocationModel struct{}
+
+// GetLocations
+
+func (l LocationModel) GetLocations(ctx context.Context, hubID uuid.UUID) ([]Location, error) {
+	return GetLocations(ctx, hubID)
+}
+
+func GetLocations(ctx context.Context, hubID uuid.UUID) ([]Location, error) {
+	var locations []Location
+	if err := getDB(ctx).Where("hub_id = ?", hubID).Order("code").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// CreateLocation
+
+func (l LocationModel) CreateLocation(ctx context.Context, location *Location) error {
+	return CreateLocation(ctx, location)
+}
+
+func CreateLocation(ctx context.Context, location *Location) error {
+	// Check if hub exists before creating location
+	hub, err := GetHub(ctx, location.HubID)
+	if err != nil {
+		return err // This will be a gorm.ErrRecordNotFound if hub doesn't exist
+	}
+	location.TenantID = hub.TenantID
+
+	parentType, ok := locationParentType[location.Type]
+	if !ok {
+		return ErrInvalidLocation
+	}
+
+	if parentType == "" {
+		location.ParentID = nil
+	} else {
+		if location.ParentID == nil {
+			return ErrInvalidLocation
+		}
+
+		var parent Location
+		if err := getDB(ctx).First(&parent, "id = ?", *location.ParentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidLocation
+			}
+			return err
+		}
+		if parent.HubID != location.HubID || parent.Type != parentType {
+			return ErrInvalidLocation
+		}
+	}
+
+	return getDB(ctx).Create(location).Error
+}
+
+// GetBinStock
+
+func (l LocationModel) GetBinStock(ctx context.Context, hubID, skuID uuid.UUID) ([]BinStockView, error) {
+	return GetBinStock(ctx, hubID, skuID)
+}
+
+// GetBinStock lists per-bin quantities in a hub, optionally for one SKU.
+func GetBinStock(ctx context.Context, hubID, skuID uuid.UUID) ([]BinStockView, error) {
+	query := getDB(ctx).Table("bin_inventories b").
+		Select("b.bin_id, l.code AS bin_code, i.sku_id, s.sku_code, b.quantity").
+		Joins("JOIN inventories i ON i.id = b.inventory_id").
+		Joins("JOIN locations l ON l.id = b.bin_id").
+		Joins("JOIN skus s ON s.id = i.sku_id").
+		Where("i.hub_id = ? AND b.quantity > 0", hubID)
+
+	if skuID != uuid.Nil {
+		query = query.Where("i.sku_id = ?", skuID)
+	}
+
+	var result []BinStockView
+	if err := query.Order("l.code, s.sku_code").Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// MoveBinStock
+
+func (l LocationModel) MoveBinStock(ctx context.Context, move BinMove) error {
+	return MoveBinStock(ctx, move)
+}
+
+// MoveBinStock shifts quantity between bins of a hub. The hub-level total in
+// inventories is untouched, so no ledger entry is written.
+func MoveBinStock(ctx context.Context, move BinMove) error {
+	return getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the hub-level row so bin moves serialise with decrements
+		inv, err := lockInventoryBySkuHub(tx, move.SkuID, move.HubID)
+		if err != nil {
+			return err
+		}
+
+		for _, binID := range []*uuid.UUID{move.FromBinID, move.ToBinID} {
+			if binID == nil {
+				continue
+			}
+			var bin Location
+			if err := tx.First(&bin, "id = ? AND hub_id = ? AND type = ?", *binID, move.HubID, LocationBin).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrInvalidLocation
+				}
+				return err
+			}
+		}
+
+		if move.FromBinID == nil {
+			binned, err := binnedQuantity(tx, inv.ID)
+			if err != nil {
+				return err
+			}
+			if inv.Quantity-binned < move.Quantity {
+				return ErrInsufficientStock
+			}
+		} else {
+			result := tx.Model(&BinInventory{}).
+				Where("inventory_id = ? AND bin_id = ? AND quantity >= ?", inv.ID, *move.FromBinID, move.Quantity).
+				Update("quantity", gorm.Expr("quantity - ?", move.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrInsufficientStock
+			}
+		}
+
+		if move.ToBinID == nil {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "inventory_id"}, {Name: "bin_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"quantity": gorm.Expr("bin_inventories.quantity + EXCLUDED.quantity"), "updated_at": gorm.Expr("NOW()")}),
+		}).Create(&BinInventory{InventoryID: inv.ID, BinID: *move.ToBinID, Quantity: move.Quantity}).Error
+	})
+}
+
+func binnedQuantity(tx *gorm.DB, inventoryID uuid.UUID) (int, error) {
+	var binned int
+	err := tx.Model(&BinInventory{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("inventory_id = ?", inventoryID).
+		Scan(&binned).Error
+	return binned, err
+}
+
+// reconcileBinStock keeps the bins within the hub-level total after a
+// decrement. Unbinned stock is consumed first; any excess still left in bins
+// is taken from them in bin code order.
+func reconcileBinStock(tx *gorm.DB, inv *Inventory) error {
+	binned, err := binnedQuantity(tx, inv.ID)
+	if err != nil {
+		return err
+	}
+
+	excess := binned - inv.Quantity
+	if excess <= 0 {
+		return nil
+	}
+
+	// The caller holds the inventory row lock, which covers its bins too
+	var bins []BinInventory
+	err = tx.Table("bin_inventories b").
+		Select("b.*").
+		Joins("JOIN locations l ON l.id = b.bin_id").
+		Where("b.inventory_id = ? AND b.quantity > 0", inv.ID).
+		Order("l.code").
+		Scan(&bins).Error
+	if err != nil {
+		return err
+	}
+
+	for _, bin := range bins {
+		if excess == 0 {
+			break
+		}
+		take := min(bin.Quantity, excess)
+		if err := tx.Model(&BinInventory{}).Where("id = ?", bin.ID).
+			Update("quantity", gorm.Expr("quantity - ?", take)).Error; err != nil {
+			return err
+		}
+		excess -= take
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrLotMismatch = errors.New("lot already exists with different dates")
+
+type InventoryLot struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	InventoryID    uuid.UUID  `gorm:"type:uuid;not null" json:"inventory_id"`
+	LotNumber      string     `gorm:"not null" json:"lot_number"`
+	ManufacturedAt *time.Time `gorm:"type:date" json:"manufactured_at"`
+	ExpiresAt      *time.Time `gorm:"type:date" json:"expires_at"`
+	Quantity       int        `gorm:"not null" json:"quantity"`
+	UnitCost       *float64   `gorm:"-" json:"unit_cost,omitempty"` // cost per unit of this receipt
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type ExpiringLotView struct {
+	LotID          uuid.UUID  `json:"lot_id"`
+	InventoryID    uuid.UUID  `json:"inventory_id"`
+	SkuID          uuid.UUID  `json:"sku_id"`
+	SkuCode        string     `json:"sku_code"`
+	LotNumber      string     `json:"lot_number"`
+	ManufacturedAt *time.Time `json:"manufactured_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	Quantity       int        `json:"quantity"`
+}
+
+type LotModel struct{}
+
+// GetInventoryLots
+
+func (l LotModel) GetInventoryLots(ctx context.Context, inventoryID uuid.UUID) ([]InventoryLot, error) {
+	return GetInventoryLots(ctx, inventoryID)
+}
+
+// GetInventoryLots lists the lots of an inventory row in FEFO order.
+func GetInventoryLots(ctx context.Context, inventoryID uuid.UUID) ([]InventoryLot, error) {
+	var lots []InventoryLot
+	err := getDB(ctx).Where("inventory_id = ? AND quantity > 0", inventoryID).
+		Order("expires_at NULLS LAST, lot_number").
+		Find(&lots).Error
+	if err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// ReceiveLot
+
+func (l LotModel) ReceiveLot(ctx context.Context, inventoryID uuid.UUID, lot *InventoryLot) error {
+	return ReceiveLot(ctx, inventoryID, lot)
+}
+
+// ReceiveLot adds lot.Quantity to an inventory row and books it against the
+// lot, creating the lot on first receipt. Receiving more of an existing lot
+// must carry the same dates.
+func ReceiveLot(ctx context.Context, inventoryID uuid.UUID, lot *InventoryLot) error {
+	ctx, err := withUnitCost(ctx, lot.UnitCost)
+	if err != nil {
+		return err
+	}
+
+	return getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		inv, err := lockInventoryByID(tx, inventoryID)
+		if err != nil {
+			return err
+		}
+
+		received := lot.Quantity
+
+		// The inventory row lock serialises receipts of the same lot
+		var existing InventoryLot
+		err = tx.Where("inventory_id = ? AND lot_number = ?", inv.ID, lot.LotNumber).First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			lot.InventoryID = inv.ID
+			if err := tx.Create(lot).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			if !sameDate(existing.ExpiresAt, lot.ExpiresAt) || !sameDate(existing.ManufacturedAt, lot.ManufacturedAt) {
+				return ErrLotMismatch
+			}
+			if err := tx.Model(&existing).Update("quantity", gorm.Expr("quantity + ?", received)).Error; err != nil {
+				return err
+			}
+			*lot = existing
+			lot.Quantity += received
+		}
+
+		return applyQuantityChange(withMovementReference(ctx, lot.LotNumber), tx, inv, received, MovementLotReceipt)
+	})
+}
+
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Format(time.DateOnly) == b.Format(time.DateOnly)
+}
+
+// GetExpiringLots
+
+func (l LotModel) GetExpiringLots(ctx context.Context, hubID uuid.UUID, before time.Time) ([]ExpiringLotView, error) {
+	return GetExpiringLots(ctx, hubID, before)
+}
+
+// GetExpiringLots lists lots in a hub that still hold stock and expire on or
+// before the given time, already expired ones included.
+func GetExpiringLots(ctx context.Context, hubID uuid.UUID, before time.Time) ([]ExpiringLotView, error) {
+	var result []ExpiringLotView
+	err := getDB(ctx).Table("inventory_lots l").
+		Select("l.id AS lot_id, l.inventory_id, i.sku_id, s.sku_code, l.lot_number, l.manufactured_at, l.expires_at, l.quantity").
+		Joins("JOIN inventories i ON i.id = l.inventory_id").
+		Joins("JOIN skus s ON s.id = i.sku_id").
+		Where("i.hub_id = ? AND l.quantity > 0 AND l.expires_at IS NOT NULL AND l.expires_at <= ?", hubID, before).
+		Order("l.expires_at, s.sku_code, l.lot_number").
+		Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// consumeLots takes quantity units out of the lots of an inventory row, first
+// expiry first out. Lots without an expiry go last and any shortfall is
+// unlotted stock. The caller holds the inventory row lock.
+func consumeLots(tx *gorm.DB, inventoryID uuid.UUID, quantity int) error {
+	var lots []InventoryLot
+	err := tx.Where("inventory_id = ? AND quantity > 0", inventoryID).
+		Order("expires_at NULLS LAST, lot_number").
+		Find(&lots).Error
+	if err != nil {
+		return err
+	}
+
+	for _, lot := range lots {
+		if quantity == 0 {
+			break
+		}
+		take := min(lot.Quantity, quantity)
+		if err := tx.Model(&InventoryLot{}).Where("id = ?", lot.ID).
+			Update("quantity", gorm.Expr("quantity - ?", take)).Error; err != nil {
+			return err
+		}
+		quantity -= take
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Movement reasons
+const (
+	MovementOrderConsumption  = "order_consumption"
+	MovementReservationCommit = "reservation_commit"
+	MovementLotReceipt        = "lot_receipt"
+	MovementSerialReceipt     = "serial_receipt"
+	MovementSerialDispatch    = "serial_dispatch"
+	MovementStatusChange      = "status_change"
+	MovementTransferDispatch  = "transfer_dispatch"
+	MovementTransferReceipt   = "transfer_receipt"
+	MovementAdjustment        = "adjustment"
+	MovementPurchaseReceipt   = "purchase_receipt"
+	MovementReturnReceipt     = "return_receipt"
+)
+
+type InventoryMovement struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID       uuid.UUID `gorm:"type:uuid;not null" json:"tenant_id"`
+	InventoryID    uuid.UUID `gorm:"type:uuid;not null" json:"inventory_id"`
+	HubID          uuid.UUID `gorm:"type:uuid;not null" json:"hub_id"`
+	SkuID          uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
+	Delta          int       `gorm:"not null" json:"delta"`
+	QuantityBefore int       `gorm:"not null" json:"quantity_before"`
+	QuantityAfter  int       `gorm:"not null" json:"quantity_after"`
+	Reason         string    `gorm:"not null" json:"reason"`
+	Source         string    `json:"source"`
+	Actor          string    `json:"actor"`
+	ReferenceID    string    `json:"reference_id"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// MovementMeta describes who changed stock and through which endpoint. It
+// travels in the context so the ledger entry can be written alongside the
+// change without widening every model signature.
+type MovementMeta struct {
+	Reason      string
+	Source      string
+	Actor       string
+	ReferenceID string
+}
+
+type movementMetaKey struct{}
+
+func WithMovementMeta(ctx context.Context, meta MovementMeta) context.Context {
+	return context.WithValue(ctx, movementMetaKey{}, meta)
+}
+
+func movementMetaFromContext(ctx context.Context) MovementMeta {
+	meta, _ := ctx.Value(movementMetaKey{}).(MovementMeta)
+	return meta
+}
+
+// withMovementReference sets the ledger reference if the caller did not.
+func withMovementReference(ctx context.Context, referenceID string) context.Context {
+	meta := movementMetaFromContext(ctx)
+	if meta.ReferenceID == "" {
+		meta.ReferenceID = referenceID
+	}
+	return WithMovementMeta(ctx, meta)
+}
+
+type MovementFilter struct {
+	From     *time.Time
+	To       *time.Time
+	Page     int
+	PageSize int
+}
+
+// recordMovement appends a ledger entry for a change already applied in tx.
+// A reason carried in the context overrides the caller's default. Decrements
+// also wake the low-stock evaluator.
+func recordMovement(ctx context.Context, tx *gorm.DB, inv *Inventory, before int, reason string) error {
+	meta := movementMetaFromContext(ctx)
+	if meta.Reason != "" {
+		reason = meta.Reason
+	}
+
+	if inv.Quantity < before {
+		signalStockDecrement(inv.ID)
+	}
+
+	if err := trackCostLayers(ctx, tx, inv, inv.Quantity-before); err != nil {
+		return err
+	}
+
+	return tx.Create(&InventoryMovement{
+		TenantID:       inv.TenantID,
+		InventoryID:    inv.ID,
+		HubID:          inv.HubID,
+		SkuID:          inv.SkuID,
+		Delta:          inv.Quantity - before,
+		QuantityBefore: before,
+		QuantityAfter:  inv.Quantity,
+		Reason:         reason,
+		Source:         meta.Source,
+		Actor:          meta.Actor,
+		ReferenceID:    meta.ReferenceID,
+	}).Error
+}
+
+// applyQuantityChange adds delta to a row locked in tx and records the
+// movement. Every stock change should go through here.
+func applyQuantityChange(ctx context.Context, tx *gorm.DB, inv *Inventory, delta int, reason string) error {
+	if delta == 0 {
+		return nil
+	}
+
+	if reason != MovementSerialReceipt && reason != MovementSerialDispatch {
+		if err := ensureNotSerialized(tx, inv.SkuID); err != nil {
+			return err
+		}
+	}
+
+	before := inv.Quantity
+	if err := tx.Model(&Inventory{}).Where("id = ?", inv.ID).
+		Update("quantity", gorm.Expr("quantity + ?", delta)).Error; err != nil {
+		return err
+	}
+	inv.Quantity = before + delta
+
+	if delta < 0 {
+		if err := consumeSubLedgers(tx, inv, -delta); err != nil {
+			return err
+		}
+	}
+
+	return recordMovement(ctx, tx, inv, before, reason)
+}
+
+// consumeSubLedgers keeps the lot and bin breakdowns of a locked row in step
+// after taken units have left its total.
+func consumeSubLedgers(tx *gorm.DB, inv *Inventory, taken int) error {
+	if err := consumeLots(tx, inv.ID, taken); err != nil {
+		return err
+	}
+	return reconcileBinStock(tx, inv)
+}
+
+// GetInventoryMovements
+
+func (i InventoryModel) GetInventoryMovements(ctx context.Context, inventoryID uuid.UUID, filter MovementFilter) ([]InventoryMovement, int64, error) {
+	return GetInventoryMovements(ctx, inventoryID, filter)
+}
+
+func GetInventoryMovements(ctx context.Context, inventoryID uuid.UUID, filter MovementFilter) ([]InventoryMovement, int64, error) {
+	query := getDB(ctx).Model(&InventoryMovement{}).Where("inventory_id = ?", inventoryID)
+
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var movements []InventoryMovement
+	err := query.Order("created_at DESC").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&movements).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return movements, total, nil
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidProduct = errors.New("product not found for tenant")
+
+// VariantAttributes are what set a SKU apart from the other variants of its
+// product, e.g. {"size": "M", "colour": "red"}. Stored as JSONB.
+type VariantAttributes map[string]string
+
+func (a VariantAttributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	bytes, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
+}
+
+func (a *VariantAttributes) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into VariantAttributes", value)
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+type Product struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID    uuid.UUID `gorm:"type:uuid;not null" json:"tenant_id"`
+	Name        string    `gorm:"not null" json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// VariantStock is one SKU of a product with its stock summed over the
+// tenant's hubs.
+type VariantStock struct {
+	SkuID      uuid.UUID         `json:"sku_id"`
+	SkuCode    string            `json:"sku_code"`
+	SkuName    string            `json:"sku_name"`
+	Attributes VariantAttributes `json:"attributes"`
+	Hubs       int               `json:"hubs"` // hubs holding any stock
+	OnHand     int               `json:"on_hand"`
+	Reserved   int               `json:"reserved"`
+	Quantity   int               `json:"quantity"` // available: on hand minus active reservations
+}
+
+type ProductModel struct{}
+
+// CreateProduct
+
+func (p ProductModel) CreateProduct(ctx context.Context, product *Product) error {
+	return CreateProduct(ctx, product)
+}
+
+func CreateProduct(ctx context.Context, product *Product) error {
+	if _, err := GetTenant(ctx, product.TenantID); err != nil {
+		return err
+	}
+	return getDB(ctx).Create(product).Error
+}
+
+// GetProducts
+
+func (p ProductModel) GetProducts(ctx context.Context, tenantID uuid.UUID) ([]Product, error) {
+	return GetProducts(ctx, tenantID)
+}
+
+func GetProducts(ctx context.Context, tenantID uuid.UUID) ([]Product, error) {
+	var products []Product
+	if err := getDB(ctx).Where("tenant_id = ?", tenantID).Order("name").Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// GetProduct
+
+func (p ProductModel) GetProduct(ctx context.Context, tenantID, id uuid.UUID) (*Product, error) {
+	return GetProduct(ctx, tenantID, id)
+}
+
+func GetProduct(ctx context.Context, tenantID, id uuid.UUID) (*Product, error) {
+	var product Product
+	if err := getDB(ctx).First(&product, "id = ? AND tenant_id = ?", id, tenantID).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetProductVariants
+
+func (p ProductModel) GetProductVariants(ctx context.Context, tenantID, id uuid.UUID) ([]VariantStock, error) {
+	return GetProductVariants(ctx, tenantID, id)
+}
+
+// GetProductVariants lists the SKUs of a product with their stock summed
+// across all hubs of the tenant.
+func GetProductVariants(ctx context.Context, tenantID, id uuid.UUID) ([]VariantStock, error) {
+	if _, err := GetProduct(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+
+	var variants []VariantStock
+	err := getDB(ctx).Raw(`
+		SELECT
+			s.id AS sku_id,
+			s.sku_code,
+			s.name AS sku_name,
+			s.attributes,
+			COALESCE(i.hubs, 0) AS hubs,
+			COALESCE(i.on_hand, 0) AS on_hand,
+			COALESCE(r.reserved, 0) AS reserved,
+			COALESCE(i.on_hand, 0) - COALESCE(r.reserved, 0) AS quantity
+		FROM skus s
+		LEFT JOIN (
+			SELECT sku_id, SUM(quantity) AS on_hand, COUNT(*) FILTER (WHERE quantity > 0) AS hubs
+			FROM inventories
+			WHERE tenant_id = ?
+			GROUP BY sku_id
+		) i ON i.sku_id = s.id
+		LEFT JOIN (
+			SELECT sku_id, SUM(quantity) AS reserved
+			FROM reservations
+			WHERE status = ? AND expires_at > NOW()
+			GROUP BY sku_id
+		) r ON r.sku_id = s.id
+		WHERE s.product_id = ? AND s.tenant_id = ?
+		ORDER BY s.sku_code
+	`, tenantID, ReservationActive, id, tenantID).Scan(&variants).Error
+
+	return variants, err
+}
+
+// ensureProductOfTenant checks that a SKU's product belongs to the SKU's
+// tenant.
+func ensureProductOfTenant(db *gorm.DB, productID *uuid.UUID, tenantID uuid.UUID) error {
+	if productID == nil {
+		return nil
+	}
+
+	var found int64
+	if err := db.Model(&Product{}).Where("id = ? AND tenant_id = ?", *productID, tenantID).Count(&found).Error; err != nil {
+		return err
+	}
+	if found == 0 {
+		return ErrInvalidProduct
+	}
+	return nil
+}
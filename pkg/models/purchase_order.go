@@ -0,0 +1,396 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	PurchaseOrderOpen              = "open"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderClosed            = "closed"
+)
+
+const (
+	ASNInTransit = "in_transit"
+	ASNReceived  = "received"
+	ASNClosed    = "closed" // closed with its order before it was received
+)
+
+var (
+	ErrInvalidPurchaseOrder = errors.New("invalid purchase order")
+	ErrPurchaseOrderClosed  = errors.New("purchase order is closed")
+	ErrASNReceived          = errors.New("shipping notice was already received")
+)
+
+type PurchaseOrder struct {
+	ID             uuid.UUID               `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID       uuid.UUID               `gorm:"type:uuid;not null" json:"tenant_id"`
+	HubID          uuid.UUID               `gorm:"type:uuid;not null" json:"hub_id"`
+	Reference      string                  `json:"reference"` // the supplier's or ERP's PO number
+	Supplier       string                  `json:"supplier"`
+	Status         string                  `gorm:"not null" json:"status"`
+	HasDiscrepancy bool                    `gorm:"not null;default:false" json:"has_discrepancy"`
+	ExpectedAt     *time.Time              `json:"expected_at"`
+	ClosedAt       *time.Time              `json:"closed_at"`
+	CreatedAt      time.Time               `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time               `gorm:"autoUpdateTime" json:"updated_at"`
+	Lines          []PurchaseOrderLine     `gorm:"foreignKey:PurchaseOrderID" json:"lines"`
+	Shipments      []AdvanceShippingNotice `gorm:"foreignKey:PurchaseOrderID" json:"shipments"`
+}
+
+type PurchaseOrderLine struct {
+	ID               uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	PurchaseOrderID  uuid.UUID `gorm:"type:uuid;not null" json:"purchase_order_id"`
+	SkuID            uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
+	ExpectedQuantity int       `gorm:"not null" json:"expected_quantity"`
+	ReceivedQuantity int       `gorm:"not null;default:0" json:"received_quantity"`
+	Variance         int       `gorm:"not null" json:"variance"`            // received minus expected: negative while outstanding, positive when over-received
+	UnitCost         *float64  `gorm:"type:numeric(14,4)" json:"unit_cost"` // booked as the cost of received units
+}
+
+type AdvanceShippingNotice struct {
+	ID              uuid.UUID                   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	PurchaseOrderID uuid.UUID                   `gorm:"type:uuid;not null" json:"purchase_order_id"`
+	Reference       string                      `json:"reference"`
+	Status          string                      `gorm:"not null" json:"status"`
+	ExpectedAt      *time.Time                  `json:"expected_at"`
+	ReceivedAt      *time.Time                  `json:"received_at"`
+	CreatedAt       time.Time                   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time                   `gorm:"autoUpdateTime" json:"updated_at"`
+	Lines           []AdvanceShippingNoticeLine `gorm:"foreignKey:AdvanceShippingNoticeID" json:"lines"`
+}
+
+type AdvanceShippingNoticeLine struct {
+	ID                      uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	AdvanceShippingNoticeID uuid.UUID `gorm:"type:uuid;not null" json:"advance_shipping_notice_id"`
+	SkuID                   uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
+	Quantity                int       `gorm:"not null" json:"quantity"`
+	ReceivedQuantity        int       `gorm:"not null;default:0" json:"received_quantity"`
+}
+
+type PurchaseOrderModel struct{}
+
+// CreatePurchaseOrder
+
+func (p PurchaseOrderModel) CreatePurchaseOrder(ctx context.Context, order *PurchaseOrder) error {
+	return CreatePurchaseOrder(ctx, order)
+}
+
+// CreatePurchaseOrder saves a purchase order for a hub of the order's
+// tenant. Stock only moves when it is received.
+func CreatePurchaseOrder(ctx context.Context, order *PurchaseOrder) error {
+	hub, err := GetHub(ctx, order.HubID)
+	if err != nil {
+		return err
+	}
+	if hub.TenantID != order.TenantID {
+		return ErrInvalidPurchaseOrder
+	}
+
+	skuIDs := make([]uuid.UUID, len(order.Lines))
+	for i, line := range order.Lines {
+		skuIDs[i] = line.SkuID
+	}
+
+	var known int64
+	if err := getDB(ctx).Model(&Sku{}).Where("id IN ? AND tenant_id = ?", skuIDs, order.TenantID).Count(&known).Error; err != nil {
+		return err
+	}
+	if int(known) != len(skuIDs) {
+		return ErrInvalidPurchaseOrder
+	}
+
+	// Keep lines in SKU order so receipts lock rows consistently
+	sort.Slice(order.Lines, func(i, j int) bool {
+		return order.Lines[i].SkuID.String() < order.Lines[j].SkuID.String()
+	})
+	for i := range order.Lines {
+		order.Lines[i].Variance = -order.Lines[i].ExpectedQuantity
+	}
+	order.Status = PurchaseOrderOpen
+	order.Shipments = []AdvanceShippingNotice{}
+
+	return getDB(ctx).Create(order).Error
+}
+
+// GetPurchaseOrder
+
+func (p PurchaseOrderModel) GetPurchaseOrder(ctx context.Context, tenantID, id uuid.UUID) (*PurchaseOrder, error) {
+	return GetPurchaseOrder(ctx, tenantID, id)
+}
+
+func GetPurchaseOrder(ctx context.Context, tenantID, id uuid.UUID) (*PurchaseOrder, error) {
+	var order PurchaseOrder
+	err := getDB(ctx).Preload("Lines", orderBySku).
+		Preload("Shipments", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Preload("Shipments.Lines", orderBySku).
+		First(&order, "id = ? AND tenant_id = ?", id, tenantID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetPurchaseOrders
+
+func (p PurchaseOrderModel) GetPurchaseOrders(ctx context.Context, tenantID uuid.UUID, status string) ([]PurchaseOrder, error) {
+	return GetPurchaseOrders(ctx, tenantID, status)
+}
+
+// GetPurchaseOrders lists a tenant's purchase orders with their lines,
+// newest first, optionally only those in one status.
+func GetPurchaseOrders(ctx context.Context, tenantID uuid.UUID, status string) ([]PurchaseOrder, error) {
+	query := getDB(ctx).Preload("Lines", orderBySku).Where("tenant_id = ?", tenantID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var orders []PurchaseOrder
+	if err := query.Order("created_at DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func orderBySku(db *gorm.DB) *gorm.DB {
+	return db.Order("sku_id")
+}
+
+// lockPurchaseOrder loads an open purchase order and its lines with the order
+// row locked.
+func lockPurchaseOrder(tx *gorm.DB, tenantID, id uuid.UUID) (*PurchaseOrder, error) {
+	var order PurchaseOrder
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&order, "id = ? AND tenant_id = ?", id, tenantID).Error
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == PurchaseOrderClosed {
+		return nil, ErrPurchaseOrderClosed
+	}
+
+	if err := tx.Where("purchase_order_id = ?", order.ID).Order("sku_id").Find(&order.Lines).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// CreateShippingNotice
+
+func (p PurchaseOrderModel) CreateShippingNotice(ctx context.Context, tenantID, orderID uuid.UUID, asn *AdvanceShippingNotice) error {
+	return CreateShippingNotice(ctx, tenantID, orderID, asn)
+}
+
+// CreateShippingNotice records a shipment announced against an open purchase
+// order. Its SKUs must be on the order; quantities may differ from what is
+// still outstanding.
+func CreateShippingNotice(ctx context.Context, tenantID, orderID uuid.UUID, asn *AdvanceShippingNotice) error {
+	return getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockPurchaseOrder(tx, tenantID, orderID)
+		if err != nil {
+			return err
+		}
+
+		onOrder := make(map[uuid.UUID]bool, len(order.Lines))
+		for _, line := range order.Lines {
+			onOrder[line.SkuID] = true
+		}
+		for _, line := range asn.Lines {
+			if !onOrder[line.SkuID] {
+				return fmt.Errorf("%w: sku %s is not on the purchase order", ErrInvalidPurchaseOrder, line.SkuID)
+			}
+		}
+
+		sort.Slice(asn.Lines, func(i, j int) bool {
+			return asn.Lines[i].SkuID.String() < asn.Lines[j].SkuID.String()
+		})
+		asn.PurchaseOrderID = order.ID
+		asn.Status = ASNInTransit
+		return tx.Create(asn).Error
+	})
+}
+
+// ReceivePurchaseOrder
+
+func (p PurchaseOrderModel) ReceivePurchaseOrder(ctx context.Context, tenantID, id uuid.UUID, asnID *uuid.UUID, received map[uuid.UUID]int) (*PurchaseOrder, error) {
+	return ReceivePurchaseOrder(ctx, tenantID, id, asnID, received)
+}
+
+// ReceivePurchaseOrder adds the received quantities, keyed by SKU, to the
+// hub's sellable stock and to the order's lines. Lines may be received in
+// several goes and over-receipts are accepted; both show up in the line's
+// variance. Receiving against a shipping notice marks it received, and with
+// no quantities given takes the notice's as arrived in full. The order
+// closes once every line has been received in full.
+func ReceivePurchaseOrder(ctx context.Context, tenantID, id uuid.UUID, asnID *uuid.UUID, received map[uuid.UUID]int) (*PurchaseOrder, error) {
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockPurchaseOrder(tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+
+		if asnID != nil {
+			received, err = receiveShippingNotice(tx, order.ID, *asnID, received)
+			if err != nil {
+				return err
+			}
+		}
+
+		lineSkus := make(map[uuid.UUID]bool, len(order.Lines))
+		for _, line := range order.Lines {
+			lineSkus[line.SkuID] = true
+		}
+		for skuID := range received {
+			if !lineSkus[skuID] {
+				return fmt.Errorf("%w: sku %s is not on the purchase order", ErrInvalidPurchaseOrder, skuID)
+			}
+		}
+
+		meta := movementMetaFromContext(ctx)
+		meta.Reason = MovementPurchaseReceipt
+		ctx := withMovementReference(WithMovementMeta(ctx, meta), order.ID.String())
+
+		complete := true
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			if got := received[line.SkuID]; got > 0 {
+				inv, err := lockOrCreateInventory(tx, order.TenantID, order.HubID, line.SkuID)
+				if err != nil {
+					return err
+				}
+				ctx, err := withUnitCost(ctx, line.UnitCost)
+				if err != nil {
+					return err
+				}
+				if err := applyQuantityChange(ctx, tx, inv, got, MovementPurchaseReceipt); err != nil {
+					return err
+				}
+
+				line.ReceivedQuantity += got
+				line.Variance = line.ReceivedQuantity - line.ExpectedQuantity
+				err = tx.Model(&PurchaseOrderLine{}).Where("id = ?", line.ID).Updates(map[string]interface{}{
+					"received_quantity": line.ReceivedQuantity,
+					"variance":          line.Variance,
+				}).Error
+				if err != nil {
+					return err
+				}
+			}
+			if line.ReceivedQuantity < line.ExpectedQuantity {
+				complete = false
+			}
+		}
+
+		if complete {
+			return closePurchaseOrder(tx, order)
+		}
+		return tx.Model(&PurchaseOrder{}).Where("id = ?", order.ID).Update("status", PurchaseOrderPartiallyReceived).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return GetPurchaseOrder(ctx, tenantID, id)
+}
+
+// receiveShippingNotice marks a notice of the order received and returns the
+// quantities to book: the given ones, or the notice's when none are given.
+func receiveShippingNotice(tx *gorm.DB, orderID, asnID uuid.UUID, received map[uuid.UUID]int) (map[uuid.UUID]int, error) {
+	var asn AdvanceShippingNotice
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&asn, "id = ? AND purchase_order_id = ?", asnID, orderID).Error
+	if err != nil {
+		return nil, err
+	}
+	if asn.Status != ASNInTransit {
+		return nil, ErrASNReceived
+	}
+	if err := tx.Where("advance_shipping_notice_id = ?", asn.ID).Find(&asn.Lines).Error; err != nil {
+		return nil, err
+	}
+
+	if len(received) == 0 {
+		received = make(map[uuid.UUID]int, len(asn.Lines))
+		for _, line := range asn.Lines {
+			received[line.SkuID] = line.Quantity
+		}
+	}
+
+	onNotice := make(map[uuid.UUID]bool, len(asn.Lines))
+	for _, line := range asn.Lines {
+		onNotice[line.SkuID] = true
+		err := tx.Model(&AdvanceShippingNoticeLine{}).Where("id = ?", line.ID).
+			Update("received_quantity", received[line.SkuID]).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	for skuID := range received {
+		if !onNotice[skuID] {
+			return nil, fmt.Errorf("%w: sku %s is not on the shipping notice", ErrInvalidPurchaseOrder, skuID)
+		}
+	}
+
+	err = tx.Model(&AdvanceShippingNotice{}).Where("id = ?", asn.ID).Updates(map[string]interface{}{
+		"status":      ASNReceived,
+		"received_at": time.Now(),
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return received, nil
+}
+
+// ClosePurchaseOrder
+
+func (p PurchaseOrderModel) ClosePurchaseOrder(ctx context.Context, tenantID, id uuid.UUID) (*PurchaseOrder, error) {
+	return ClosePurchaseOrder(ctx, tenantID, id)
+}
+
+// ClosePurchaseOrder closes an order before it is complete, once the rest is
+// not coming. Outstanding quantities stay on the lines as negative variance
+// and notices still in transit are closed with it.
+func ClosePurchaseOrder(ctx context.Context, tenantID, id uuid.UUID) (*PurchaseOrder, error) {
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockPurchaseOrder(tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+		return closePurchaseOrder(tx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return GetPurchaseOrder(ctx, tenantID, id)
+}
+
+func closePurchaseOrder(tx *gorm.DB, order *PurchaseOrder) error {
+	for _, line := range order.Lines {
+		if line.Variance != 0 {
+			order.HasDiscrepancy = true
+		}
+	}
+
+	// Nothing more arrives against the order, so its notices stop counting as inbound
+	err := tx.Model(&AdvanceShippingNotice{}).
+		Where("purchase_order_id = ? AND status = ?", order.ID, ASNInTransit).
+		Update("status", ASNClosed).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&PurchaseOrder{}).Where("id = ?", order.ID).Updates(map[string]interface{}{
+		"status":          PurchaseOrderClosed,
+		"has_discrepancy": order.HasDiscrepancy,
+		"closed_at":       time.Now(),
+	}).Error
+}
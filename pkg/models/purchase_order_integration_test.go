@@ -0,0 +1,56 @@
+//go:build integration
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReceiveWithoutShippingNotice checks that units received without their
+// notice are not counted again as inbound, and that closing the order closes
+// the notice.
+func TestReceiveWithoutShippingNotice(t *testing.T) {
+	ctx := WithMovementMeta(context.Background(), MovementMeta{Actor: "receiver"})
+	tenantID, sellerID := seedTenant(t, ctx)
+	hubID := seedHub(t, ctx, tenantID)
+	skuID := seedSku(t, ctx, tenantID, sellerID)
+
+	order := &PurchaseOrder{
+		TenantID: tenantID,
+		HubID:    hubID,
+		Lines:    []PurchaseOrderLine{{SkuID: skuID, ExpectedQuantity: 10}},
+	}
+	if err := CreatePurchaseOrder(ctx, order); err != nil {
+		t.Fatalf("seed purchase order: %v", err)
+	}
+	asn := &AdvanceShippingNotice{Lines: []AdvanceShippingNoticeLine{{SkuID: skuID, Quantity: 6}}}
+	if err := CreateShippingNotice(ctx, tenantID, order.ID, asn); err != nil {
+		t.Fatalf("seed shipping notice: %v", err)
+	}
+
+	inbound := func() int {
+		results, err := GetATP(ctx, tenantID, []ATPQuery{{SkuID: skuID, HubID: &hubID}}, time.Now())
+		assert.NoError(t, err)
+		if !assert.Len(t, results, 1) {
+			return -1
+		}
+		return results[0].Total.Inbound
+	}
+	assert.Equal(t, 6, inbound())
+
+	_, err := ReceivePurchaseOrder(ctx, tenantID, order.ID, nil, map[uuid.UUID]int{skuID: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inbound())
+
+	closed, err := ClosePurchaseOrder(ctx, tenantID, order.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, closed.Shipments, 1) {
+		assert.Equal(t, ASNClosed, closed.Shipments[0].Status)
+	}
+	assert.Equal(t, 0, inbound())
+}
@@ -0,0 +1,219 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ReservationActive    = "active"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+var ErrReservationExists = errors.New("reservation already exists")
+
+type Reservation struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID       uuid.UUID `gorm:"type:uuid;not null" json:"tenant_id"`
+	OrderReference string    `gorm:"not null" json:"order_reference"`
+	HubID          uuid.UUID `gorm:"type:uuid;not null" json:"hub_id"`
+	SkuID          uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
+	Quantity       int       `gorm:"not null" json:"quantity"`
+	Status         string    `gorm:"not null" json:"status"`
+	ExpiresAt      time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type ReservationModel struct{}
+
+// activeReservedQuantity sums the live holds on a hub/SKU. Holds past their
+// expiry are ignored even before the expiry job has flipped their status.
+func activeReservedQuantity(db *gorm.DB, skuID, hubID uuid.UUID) (int, error) {
+	var reserved int
+	err := db.Model(&Reservation{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("sku_id = ? AND hub_id = ? AND status = ? AND expires_at > NOW()", skuID, hubID, ReservationActive).
+		Scan(&reserved).Error
+	return reserved, err
+}
+
+// ReserveInventory
+
+func (r ReservationModel) ReserveInventory(ctx context.Context, res *Reservation, ttl time.Duration) (bool, error) {
+	return ReserveInventory(ctx, res, ttl)
+}
+
+// ReserveInventory places a hold on stock of res.TenantID for an order line.
+// It returns false when the hub/SKU has no inventory row or not enough
+// unreserved quantity, and gorm.ErrRecordNotFound when the row belongs to
+// another tenant.
+func ReserveInventory(ctx context.Context, res *Reservation, ttl time.Duration) (bool, error) {
+	reserved := false
+
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the inventory row serialises holds against concurrent decrements
+		inv, err := lockInventoryBySkuHub(tx, res.SkuID, res.HubID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if inv.TenantID != res.TenantID {
+			return gorm.ErrRecordNotFound
+		}
+
+		// A lapsed hold of this line still counts as active until the expiry
+		// job runs, and would trip both the check below and the unique index
+		if err := tx.Model(&Reservation{}).
+			Where("tenant_id = ? AND order_reference = ? AND hub_id = ? AND sku_id = ? AND status = ? AND expires_at <= NOW()",
+				res.TenantID, res.OrderReference, res.HubID, res.SkuID, ReservationActive).
+			Update("status", ReservationExpired).Error; err != nil {
+			return err
+		}
+
+		var existing int64
+		if err := tx.Model(&Reservation{}).
+			Where("tenant_id = ? AND order_reference = ? AND hub_id = ? AND sku_id = ? AND status = ?",
+				res.TenantID, res.OrderReference, res.HubID, res.SkuID, ReservationActive).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrReservationExists
+		}
+
+		held, err := activeReservedQuantity(tx, res.SkuID, res.HubID)
+		if err != nil {
+			return err
+		}
+		if inv.Quantity-held < res.Quantity {
+			return nil
+		}
+
+		res.Status = ReservationActive
+		res.ExpiresAt = time.Now().Add(ttl)
+		if err := tx.Create(res).Error; err != nil {
+			return err
+		}
+
+		reserved = true
+		return nil
+	})
+
+	return reserved && err == nil, err
+}
+
+// lockActiveReservations loads a tenant's live holds for an order with a row
+// lock. Order references are only unique within a tenant.
+func lockActiveReservations(tx *gorm.DB, tenantID uuid.UUID, orderReference string) ([]Reservation, error) {
+	var reservations []Reservation
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND order_reference = ? AND status = ? AND expires_at > NOW()", tenantID, orderReference, ReservationActive).
+		Order("hub_id, sku_id").
+		Find(&reservations).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(reservations) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return reservations, nil
+}
+
+// CommitReservations
+
+func (r ReservationModel) CommitReservations(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]Reservation, error) {
+	return CommitReservations(ctx, tenantID, orderReference)
+}
+
+// CommitReservations turns every live hold of an order into a real decrement
+// of the held inventory rows, all in one transaction.
+func CommitReservations(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]Reservation, error) {
+	var reservations []Reservation
+	ctx = withMovementReference(ctx, orderReference)
+
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		reservations, err = lockActiveReservations(tx, tenantID, orderReference)
+		if err != nil {
+			return err
+		}
+
+		for i := range reservations {
+			res := &reservations[i]
+
+			inv, err := lockInventoryBySkuHub(tx, res.SkuID, res.HubID)
+			if err != nil {
+				return err
+			}
+			if inv.Quantity < res.Quantity {
+				return ErrInsufficientStock
+			}
+
+			if err := applyQuantityChange(ctx, tx, inv, -res.Quantity, MovementReservationCommit); err != nil {
+				return err
+			}
+
+			if err := tx.Model(res).Update("status", ReservationCommitted).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// ReleaseReservations
+
+func (r ReservationModel) ReleaseReservations(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]Reservation, error) {
+	return ReleaseReservations(ctx, tenantID, orderReference)
+}
+
+// ReleaseReservations drops every live hold of an order without touching stock.
+func ReleaseReservations(ctx context.Context, tenantID uuid.UUID, orderReference string) ([]Reservation, error) {
+	var reservations []Reservation
+
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		reservations, err = lockActiveReservations(tx, tenantID, orderReference)
+		if err != nil {
+			return err
+		}
+
+		for i := range reservations {
+			if err := tx.Model(&reservations[i]).Update("status", ReservationReleased).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// ExpireReservations
+
+// ExpireReservations marks holds past their TTL as expired and returns how
+// many were affected.
+func ExpireReservations(ctx context.Context) (int64, error) {
+	result := getDB(ctx).Model(&Reservation{}).
+		Where("status = ? AND expires_at <= NOW()", ReservationActive).
+		Update("status", ReservationExpired)
+	return result.RowsAffected, result.Error
+}
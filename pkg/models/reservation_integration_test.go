@@ -0,0 +1,54 @@
+//go:build integration
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// TestReserveInventoryAfterExpiry re-reserves an order line whose hold has
+// lapsed but not yet been swept by the expiry job.
+func TestReserveInventoryAfterExpiry(t *testing.T) {
+	ctx := context.Background()
+	inv := seedStock(t, ctx, 10)
+
+	first := &Reservation{TenantID: inv.TenantID, OrderReference: "order-1", HubID: inv.HubID, SkuID: inv.SkuID, Quantity: 4}
+	reserved, err := ReserveInventory(ctx, first, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	again := &Reservation{TenantID: inv.TenantID, OrderReference: "order-1", HubID: inv.HubID, SkuID: inv.SkuID, Quantity: 4}
+	_, err = ReserveInventory(ctx, again, time.Minute)
+	assert.ErrorIs(t, err, ErrReservationExists)
+
+	// Lapse the hold without running ExpireReservations
+	err = getDB(ctx).Model(&Reservation{}).Where("id = ?", first.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error
+	assert.NoError(t, err)
+
+	again = &Reservation{TenantID: inv.TenantID, OrderReference: "order-1", HubID: inv.HubID, SkuID: inv.SkuID, Quantity: 8}
+	reserved, err = ReserveInventory(ctx, again, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	var lapsed Reservation
+	assert.NoError(t, getDB(ctx).First(&lapsed, "id = ?", first.ID).Error)
+	assert.Equal(t, ReservationExpired, lapsed.Status)
+}
+
+// TestReserveInventoryOtherTenant refuses holds on another tenant's stock.
+func TestReserveInventoryOtherTenant(t *testing.T) {
+	ctx := context.Background()
+	inv := seedStock(t, ctx, 10)
+
+	res := &Reservation{TenantID: uuid.New(), OrderReference: "order-1", HubID: inv.HubID, SkuID: inv.SkuID, Quantity: 1}
+	reserved, err := ReserveInventory(ctx, res, time.Minute)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.False(t, reserved)
+}
@@ -0,0 +1,310 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ReturnOpen     = "open"
+	ReturnReceived = "received"
+)
+
+// ReturnGrades lists the buckets a returned unit can be graded into.
+var ReturnGrades = []string{StatusSellable, StatusDamaged, StatusDisposal}
+
+var (
+	ErrInvalidReturn = errors.New("invalid return")
+	ErrReturnClosed  = errors.New("return was already received")
+)
+
+type ReturnAuthorization struct {
+	ID             uuid.UUID                 `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID       uuid.UUID                 `gorm:"type:uuid;not null" json:"tenant_id"`
+	HubID          uuid.UUID                 `gorm:"type:uuid;not null" json:"hub_id"`
+	OrderReference string                    `gorm:"not null" json:"order_reference"`
+	Reason         string                    `json:"reason"`
+	Status         string                    `gorm:"not null" json:"status"`
+	ReceivedAt     *time.Time                `json:"received_at"`
+	CreatedAt      time.Time                 `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time                 `gorm:"autoUpdateTime" json:"updated_at"`
+	Lines          []ReturnAuthorizationLine `gorm:"foreignKey:ReturnAuthorizationID" json:"lines"`
+}
+
+type ReturnAuthorizationLine struct {
+	ID                    uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ReturnAuthorizationID uuid.UUID `gorm:"type:uuid;not null" json:"return_authorization_id"`
+	SkuID                 uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
+	Quantity              int       `gorm:"not null" json:"quantity"` // authorized
+	SellableQuantity      int       `gorm:"not null;default:0" json:"sellable_quantity"`
+	DamagedQuantity       int       `gorm:"not null;default:0" json:"damaged_quantity"`
+	DisposalQuantity      int       `gorm:"not null;default:0" json:"disposal_quantity"`
+}
+
+// ReturnGrading is how the received units of one SKU were graded.
+type ReturnGrading struct {
+	Sellable int `json:"sellable"`
+	Damaged  int `json:"damaged"`
+	Disposal int `json:"disposal"`
+}
+
+func (g ReturnGrading) Total() int {
+	return g.Sellable + g.Damaged + g.Disposal
+}
+
+type ReturnLedgerEntry struct {
+	ID                    uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID              uuid.UUID `gorm:"type:uuid;not null" json:"tenant_id"`
+	ReturnAuthorizationID uuid.UUID `gorm:"type:uuid;not null" json:"return_authorization_id"`
+	OrderReference        string    `gorm:"not null" json:"order_reference"`
+	HubID                 uuid.UUID `gorm:"type:uuid;not null" json:"hub_id"`
+	SkuID                 uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
+	Grade                 string    `gorm:"not null" json:"grade"`
+	Quantity              int       `gorm:"not null" json:"quantity"`
+	Actor                 string    `json:"actor"`
+	CreatedAt             time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type ReturnLedgerFilter struct {
+	HubID    *uuid.UUID
+	From     *time.Time
+	To       *time.Time
+	Page     int
+	PageSize int
+}
+
+type ReturnModel struct{}
+
+// CreateReturn
+
+func (r ReturnModel) CreateReturn(ctx context.Context, rma *ReturnAuthorization) error {
+	return CreateReturn(ctx, rma)
+}
+
+// CreateReturn authorizes a return of the order's SKUs to a hub of the
+// tenant. Serial-tracked SKUs go through the serial endpoints instead.
+func CreateReturn(ctx context.Context, rma *ReturnAuthorization) error {
+	hub, err := GetHub(ctx, rma.HubID)
+	if err != nil {
+		return err
+	}
+	if hub.TenantID != rma.TenantID {
+		return ErrInvalidReturn
+	}
+
+	skuIDs := make([]uuid.UUID, len(rma.Lines))
+	for i, line := range rma.Lines {
+		skuIDs[i] = line.SkuID
+	}
+
+	var skus []Sku
+	if err := getDB(ctx).Where("id IN ? AND tenant_id = ?", skuIDs, rma.TenantID).Find(&skus).Error; err != nil {
+		return err
+	}
+	if len(skus) != len(skuIDs) {
+		return ErrInvalidReturn
+	}
+	for _, sku := range skus {
+		if sku.Serialized {
+			return fmt.Errorf("%w: %s", ErrSerializedSku, sku.SkuCode)
+		}
+	}
+
+	// Keep lines in SKU order so receiving locks rows consistently
+	sort.Slice(rma.Lines, func(i, j int) bool {
+		return rma.Lines[i].SkuID.String() < rma.Lines[j].SkuID.String()
+	})
+	rma.Status = ReturnOpen
+
+	return getDB(ctx).Create(rma).Error
+}
+
+// GetReturn
+
+func (r ReturnModel) GetReturn(ctx context.Context, tenantID, id uuid.UUID) (*ReturnAuthorization, error) {
+	return GetReturn(ctx, tenantID, id)
+}
+
+func GetReturn(ctx context.Context, tenantID, id uuid.UUID) (*ReturnAuthorization, error) {
+	var rma ReturnAuthorization
+	err := getDB(ctx).Preload("Lines", orderBySku).
+		First(&rma, "id = ? AND tenant_id = ?", id, tenantID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rma, nil
+}
+
+// GetReturns
+
+func (r ReturnModel) GetReturns(ctx context.Context, tenantID uuid.UUID, orderReference, status string) ([]ReturnAuthorization, error) {
+	return GetReturns(ctx, tenantID, orderReference, status)
+}
+
+// GetReturns lists a tenant's returns, newest first, optionally for one
+// order or in one status.
+func GetReturns(ctx context.Context, tenantID uuid.UUID, orderReference, status string) ([]ReturnAuthorization, error) {
+	query := getDB(ctx).Preload("Lines", orderBySku).Where("tenant_id = ?", tenantID)
+	if orderReference != "" {
+		query = query.Where("order_reference = ?", orderReference)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var returns []ReturnAuthorization
+	if err := query.Order("created_at DESC").Find(&returns).Error; err != nil {
+		return nil, err
+	}
+	return returns, nil
+}
+
+// ReceiveReturn
+
+func (r ReturnModel) ReceiveReturn(ctx context.Context, tenantID, id uuid.UUID, grades map[uuid.UUID]ReturnGrading) (*ReturnAuthorization, error) {
+	return ReceiveReturn(ctx, tenantID, id, grades)
+}
+
+// ReceiveReturn books the received units of an open return, keyed by SKU,
+// into the hub's buckets as graded: sellable units go back to quantity,
+// damaged and disposal units to their buckets. SKUs missing from grades
+// count as not returned. Each graded quantity is written to the return
+// ledger and the return is closed.
+func ReceiveReturn(ctx context.Context, tenantID, id uuid.UUID, grades map[uuid.UUID]ReturnGrading) (*ReturnAuthorization, error) {
+	var rma ReturnAuthorization
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&rma, "id = ? AND tenant_id = ?", id, tenantID).Error
+		if err != nil {
+			return err
+		}
+		if rma.Status != ReturnOpen {
+			return ErrReturnClosed
+		}
+		if err := tx.Where("return_authorization_id = ?", rma.ID).Order("sku_id").Find(&rma.Lines).Error; err != nil {
+			return err
+		}
+
+		lineSkus := make(map[uuid.UUID]bool, len(rma.Lines))
+		for _, line := range rma.Lines {
+			lineSkus[line.SkuID] = true
+		}
+		for skuID := range grades {
+			if !lineSkus[skuID] {
+				return fmt.Errorf("%w: sku %s is not on the return", ErrInvalidReturn, skuID)
+			}
+		}
+
+		meta := movementMetaFromContext(ctx)
+		meta.Reason = MovementReturnReceipt
+		ctx := withMovementReference(WithMovementMeta(ctx, meta), rma.ID.String())
+
+		for i := range rma.Lines {
+			line := &rma.Lines[i]
+			grading := grades[line.SkuID]
+			if grading.Total() > line.Quantity {
+				return fmt.Errorf("%w: received more than authorized for sku %s", ErrInvalidReturn, line.SkuID)
+			}
+			if grading.Total() == 0 {
+				continue
+			}
+
+			inv, err := lockOrCreateInventory(tx, rma.TenantID, rma.HubID, line.SkuID)
+			if err != nil {
+				return err
+			}
+
+			graded := map[string]int{StatusSellable: grading.Sellable, StatusDamaged: grading.Damaged, StatusDisposal: grading.Disposal}
+			for _, grade := range ReturnGrades {
+				quantity := graded[grade]
+				if quantity == 0 {
+					continue
+				}
+				if err := addToBucket(ctx, tx, inv, grade, quantity); err != nil {
+					return err
+				}
+				err := tx.Create(&ReturnLedgerEntry{
+					TenantID:              rma.TenantID,
+					ReturnAuthorizationID: rma.ID,
+					OrderReference:        rma.OrderReference,
+					HubID:                 rma.HubID,
+					SkuID:                 line.SkuID,
+					Grade:                 grade,
+					Quantity:              quantity,
+					Actor:                 meta.Actor,
+				}).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			line.SellableQuantity = grading.Sellable
+			line.DamagedQuantity = grading.Damaged
+			line.DisposalQuantity = grading.Disposal
+			err = tx.Model(&ReturnAuthorizationLine{}).Where("id = ?", line.ID).Updates(map[string]interface{}{
+				"sellable_quantity": line.SellableQuantity,
+				"damaged_quantity":  line.DamagedQuantity,
+				"disposal_quantity": line.DisposalQuantity,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		rma.Status = ReturnReceived
+		rma.ReceivedAt = &now
+		return tx.Model(&ReturnAuthorization{}).Where("id = ?", rma.ID).Updates(map[string]interface{}{
+			"status":      rma.Status,
+			"received_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rma, nil
+}
+
+// GetReturnLedger
+
+func (r ReturnModel) GetReturnLedger(ctx context.Context, tenantID uuid.UUID, filter ReturnLedgerFilter) ([]ReturnLedgerEntry, int64, error) {
+	return GetReturnLedger(ctx, tenantID, filter)
+}
+
+func GetReturnLedger(ctx context.Context, tenantID uuid.UUID, filter ReturnLedgerFilter) ([]ReturnLedgerEntry, int64, error) {
+	query := getDB(ctx).Model(&ReturnLedgerEntry{}).Where("tenant_id = ?", tenantID)
+
+	if filter.HubID != nil {
+		query = query.Where("hub_id = ?", *filter.HubID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []ReturnLedgerEntry
+	err := query.Order("created_at DESC").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
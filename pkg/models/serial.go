@@ -0,0 +1,193 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	SerialInStock   = "in_stock"
+	SerialAllocated = "allocated"
+)
+
+var (
+	ErrSerializedSku     = errors.New("sku is serial-tracked, use the serial endpoints")
+	ErrNotSerialized     = errors.New("sku is not serial-tracked")
+	ErrSkuHasStock       = errors.New("sku already has stock without serials")
+	ErrSerialExists      = errors.New("serial already in stock")
+	ErrSerialUnavailable = errors.New("serial not in stock at hub")
+)
+
+type Serial struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID       uuid.UUID `gorm:"type:uuid;not null" json:"tenant_id"`
+	SkuID          uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
+	HubID          uuid.UUID `gorm:"type:uuid;not null" json:"hub_id"`
+	SerialNumber   string    `gorm:"not null" json:"serial_number"`
+	Status         string    `gorm:"not null" json:"status"`
+	OrderReference string    `json:"order_reference"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type SerialModel struct{}
+
+// GetSerials
+
+func (s SerialModel) GetSerials(ctx context.Context, serialNumber string, skuID uuid.UUID) ([]Serial, error) {
+	return GetSerials(ctx, serialNumber, skuID)
+}
+
+// GetSerials looks a serial number up across SKUs, or within one if skuID is set.
+func GetSerials(ctx context.Context, serialNumber string, skuID uuid.UUID) ([]Serial, error) {
+	query := getDB(ctx).Where("serial_number = ?", serialNumber)
+	if skuID != uuid.Nil {
+		query = query.Where("sku_id = ?", skuID)
+	}
+
+	var serials []Serial
+	if err := query.Order("created_at").Find(&serials).Error; err != nil {
+		return nil, err
+	}
+	return serials, nil
+}
+
+// RegisterSerials
+
+func (s SerialModel) RegisterSerials(ctx context.Context, hubID, skuID uuid.UUID, serialNumbers []string) ([]Serial, error) {
+	return RegisterSerials(ctx, hubID, skuID, serialNumbers)
+}
+
+// RegisterSerials receives serialized units into a hub, adding one to the
+// hub inventory per serial. A serial that was allocated earlier, e.g. a
+// returned unit, goes back in stock at the receiving hub.
+func RegisterSerials(ctx context.Context, hubID, skuID uuid.UUID, serialNumbers []string) ([]Serial, error) {
+	if _, err := GetHub(ctx, hubID); err != nil {
+		return nil, err
+	}
+
+	var serials []Serial
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		var sku Sku
+		if err := tx.First(&sku, "id = ?", skuID).Error; err != nil {
+			return err
+		}
+		if !sku.Serialized {
+			return ErrNotSerialized
+		}
+
+		inv, err := lockOrCreateInventory(tx, sku.TenantID, hubID, skuID)
+		if err != nil {
+			return err
+		}
+
+		for _, number := range serialNumbers {
+			var serial Serial
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("sku_id = ? AND serial_number = ?", skuID, number).
+				First(&serial).Error
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				serial = Serial{
+					TenantID:     sku.TenantID,
+					SkuID:        skuID,
+					HubID:        hubID,
+					SerialNumber: number,
+					Status:       SerialInStock,
+				}
+				if err := tx.Create(&serial).Error; err != nil {
+					return err
+				}
+			case err != nil:
+				return err
+			case serial.Status == SerialInStock:
+				return ErrSerialExists
+			default:
+				serial.HubID = hubID
+				serial.Status = SerialInStock
+				serial.OrderReference = ""
+				if err := tx.Select("hub_id", "status", "order_reference", "updated_at").Save(&serial).Error; err != nil {
+					return err
+				}
+			}
+			serials = append(serials, serial)
+		}
+
+		return applyQuantityChange(ctx, tx, inv, len(serials), MovementSerialReceipt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return serials, nil
+}
+
+// AllocateSerials
+
+func (s SerialModel) AllocateSerials(ctx context.Context, hubID, skuID uuid.UUID, orderReference string, serialNumbers []string) ([]Serial, error) {
+	return AllocateSerials(ctx, hubID, skuID, orderReference, serialNumbers)
+}
+
+// AllocateSerials dispatches the given in-stock serials from a hub against an
+// order, taking one unit per serial off the hub inventory. Either every
+// serial is allocated or none is.
+func AllocateSerials(ctx context.Context, hubID, skuID uuid.UUID, orderReference string, serialNumbers []string) ([]Serial, error) {
+	var serials []Serial
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		inv, err := lockInventoryBySkuHub(tx, skuID, hubID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("sku_id = ? AND hub_id = ? AND status = ? AND serial_number IN ?", skuID, hubID, SerialInStock, serialNumbers).
+			Order("serial_number").
+			Find(&serials).Error
+		if err != nil {
+			return err
+		}
+		if len(serials) != len(serialNumbers) {
+			return ErrSerialUnavailable
+		}
+
+		ids := make([]uuid.UUID, len(serials))
+		for i := range serials {
+			serials[i].Status = SerialAllocated
+			serials[i].OrderReference = orderReference
+			ids[i] = serials[i].ID
+		}
+		err = tx.Model(&Serial{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":          SerialAllocated,
+			"order_reference": orderReference,
+			"updated_at":      gorm.Expr("NOW()"),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return applyQuantityChange(withMovementReference(ctx, orderReference), tx, inv, -len(serials), MovementSerialDispatch)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return serials, nil
+}
+
+// ensureNotSerialized stops quantity changes that bypass the serials of a
+// serial-tracked SKU, which would break quantity == in-stock serials.
+func ensureNotSerialized(tx *gorm.DB, skuID uuid.UUID) error {
+	var serialized bool
+	if err := tx.Model(&Sku{}).Select("serialized").Where("id = ?", skuID).Scan(&serialized).Error; err != nil {
+		return err
+	}
+	if serialized {
+		return ErrSerializedSku
+	}
+	return nil
+}
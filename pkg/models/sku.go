@@ -9,16 +9,24 @@ import (
 	"github.com/aditya-goyal-omniful/ims/pkg/configs"
 	"github.com/aditya-goyal-omniful/ims/pkg/constants"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Sku struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Name      string    `gorm:"not null" json:"name"`
-	SkuCode   string    `gorm:"unique;not null" json:"sku_code"`
-	SellerID  uuid.UUID `gorm:"not null" json:"seller_id"`
-	TenantID  uuid.UUID `gorm:"not null" json:"tenant_id"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID         uuid.UUID         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name       string            `gorm:"not null" json:"name"`
+	SkuCode    string            `gorm:"unique;not null" json:"sku_code"`
+	SellerID   uuid.UUID         `gorm:"not null" json:"seller_id"`
+	TenantID   uuid.UUID         `gorm:"not null" json:"tenant_id"`
+	Serialized bool              `gorm:"not null;default:false" json:"serialized"`
+	BaseUnit   string            `gorm:"not null;default:each" json:"base_unit"`
+	ProductID  *uuid.UUID        `gorm:"type:uuid" json:"product_id"`
+	Attributes VariantAttributes `gorm:"type:jsonb;not null;default:'{}'" json:"attributes"`
+	CreatedAt  time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Components []BundleComponent `gorm:"foreignKey:BundleSkuID" json:"components,omitempty"` // set for bundles only
+	Barcodes   []SkuBarcode      `gorm:"foreignKey:SkuID" json:"barcodes,omitempty"`
 }
 
 type SKUModel struct{}
@@ -53,7 +61,7 @@ func GetSku(ctx context.Context, id uuid.UUID) (*Sku, error) {
 	
 	// Fallback to DB
 	var sku Sku
-	if err := getDB(ctx).First(&sku, "id = ?", id).Error; err != nil {
+	if err := getDB(ctx).Preload("Components", orderByComponent).Preload("Barcodes").First(&sku, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
@@ -84,10 +92,20 @@ func CreateSku(ctx context.Context, sku *Sku) error {
 		return err // This will be a gorm.ErrRecordNotFound if seller doesn't exist
 	}
 
-	if err := getDB(ctx).Create(sku).Error; err != nil {
-		return err
-	}
-	return nil
+	return getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureProductOfTenant(tx, sku.ProductID, sku.TenantID); err != nil {
+			return err
+		}
+
+		components, barcodes := sku.Components, sku.Barcodes
+		if err := tx.Omit("Components", "Barcodes").Create(sku).Error; err != nil {
+			return err
+		}
+		if err := addSkuBarcodes(tx, sku, barcodes); err != nil {
+			return err
+		}
+		return setBundleComponents(tx, sku, components)
+	})
 }
 
 // DeleteSku
@@ -123,7 +141,29 @@ func (s SKUModel) GetTenant(ctx context.Context, id uuid.UUID) (*Tenant, error)
 }
 
 func UpdateSku(ctx context.Context, id uuid.UUID, updated *Sku) error {
-	err := getDB(ctx).Model(&Sku{}).Where("id = ?", id).Updates(updated).Error
+	// Serial tracking can only start while no stock exists without serials
+	if updated.Serialized {
+		if err := ensureNoUnserializedStock(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if updated.ProductID != nil {
+		var current Sku
+		if err := getDB(ctx).First(&current, "id = ?", id).Error; err != nil {
+			return err
+		}
+		tenantID := current.TenantID
+		if updated.TenantID != uuid.Nil {
+			tenantID = updated.TenantID
+		}
+		if err := ensureProductOfTenant(getDB(ctx), updated.ProductID, tenantID); err != nil {
+			return err
+		}
+	}
+
+	// Components and barcodes have endpoints of their own
+	err := getDB(ctx).Model(&Sku{}).Where("id = ?", id).Omit("Components", "Barcodes").Updates(updated).Error
 
 	// Invalidate cache
 	_, _ = configs.RedisClient.Del(ctx, fmt.Sprintf("sku:%s", id))
@@ -133,26 +173,60 @@ func UpdateSku(ctx context.Context, id uuid.UUID, updated *Sku) error {
 
 // GetFilteredSkus
 
-func (s SKUModel) GetFilteredSkus(ctx context.Context, tenantID, sellerID uuid.UUID, skuCodes []string) ([]Sku, error) {
-	return GetFilteredSkus(ctx, tenantID, sellerID, skuCodes)
+type SkuFilter struct {
+	SellerID   uuid.UUID
+	SkuCodes   []string
+	ProductID  *uuid.UUID
+	Attributes VariantAttributes // every pair must match
 }
 
-func GetFilteredSkus(ctx context.Context, tenantID uuid.UUID, sellerID uuid.UUID, skuCodes []string) ([]Sku, error) {
+func (s SKUModel) GetFilteredSkus(ctx context.Context, tenantID uuid.UUID, filter SkuFilter) ([]Sku, error) {
+	return GetFilteredSkus(ctx, tenantID, filter)
+}
+
+func GetFilteredSkus(ctx context.Context, tenantID uuid.UUID, filter SkuFilter) ([]Sku, error) {
 	db := getDB(ctx)
 	query := db.Model(&Sku{}).Where("tenant_id = ?", tenantID)
 
-	if sellerID != uuid.Nil {
-		query = query.Where("seller_id = ?", sellerID)
+	if filter.SellerID != uuid.Nil {
+		query = query.Where("seller_id = ?", filter.SellerID)
+	}
+
+	if len(filter.SkuCodes) > 0 {
+		query = query.Where("sku_code IN ?", filter.SkuCodes)
+	}
+
+	if filter.ProductID != nil {
+		query = query.Where("product_id = ?", *filter.ProductID)
 	}
 
-	if len(skuCodes) > 0 {
-		query = query.Where("sku_code IN ?", skuCodes)
+	if len(filter.Attributes) > 0 {
+		query = query.Where("attributes @> ?", filter.Attributes)
 	}
 
 	var skus []Sku
-	if err := query.Find(&skus).Error; err != nil {
+	if err := query.Preload("Components", orderByComponent).Preload("Barcodes").Find(&skus).Error; err != nil {
 		return nil, err
 	}
 
 	return skus, nil
-}
\ No newline at end of file
+}
+
+func ensureNoUnserializedStock(ctx context.Context, id uuid.UUID) error {
+	var current Sku
+	if err := getDB(ctx).First(&current, "id = ?", id).Error; err != nil {
+		return err
+	}
+	if current.Serialized {
+		return nil
+	}
+
+	var stocked int64
+	if err := getDB(ctx).Model(&Inventory{}).Where("sku_id = ? AND quantity > 0", id).Count(&stocked).Error; err != nil {
+		return err
+	}
+	if stocked > 0 {
+		return ErrSkuHasStock
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InventorySnapshot is an inventory row's quantity as it stood when the
+// day's snapshot was taken.
+type InventorySnapshot struct {
+	SnapshotDate time.Time `gorm:"type:date;primaryKey" json:"snapshot_date"`
+	InventoryID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"inventory_id"`
+	TenantID     uuid.UUID `gorm:"type:uuid;not null" json:"tenant_id"`
+	HubID        uuid.UUID `gorm:"type:uuid;not null" json:"hub_id"`
+	SkuID        uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
+	Quantity     int       `gorm:"not null" json:"quantity"`
+}
+
+// InventorySnapshotRun marks a day's snapshot as taken. Its rows hold every
+// movement created before TakenAt and none after.
+type InventorySnapshotRun struct {
+	SnapshotDate time.Time `gorm:"type:date;primaryKey" json:"snapshot_date"`
+	TakenAt      time.Time `gorm:"not null" json:"taken_at"`
+	RowCount     int64     `gorm:"not null;default:0" json:"row_count"`
+}
+
+// TakeInventorySnapshot copies every inventory row into today's (UTC)
+// snapshot and returns how many it copied. Once today's snapshot exists it
+// does nothing, so it is safe to run more often than daily.
+func TakeInventorySnapshot(ctx context.Context) (int64, error) {
+	var copied int64
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		// Hold stock writes until the copy is done, so no change that has
+		// already been ledgered can be missing from it
+		if err := tx.Exec("LOCK TABLE inventories IN SHARE MODE").Error; err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		run := &InventorySnapshotRun{
+			SnapshotDate: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+			TakenAt:      now,
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(run)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := ensureSnapshotPartition(tx, run.SnapshotDate); err != nil {
+			return err
+		}
+
+		result = tx.Exec(`
+			INSERT INTO inventory_snapshots (snapshot_date, inventory_id, tenant_id, hub_id, sku_id, quantity)
+			SELECT ?, id, tenant_id, hub_id, sku_id, quantity
+			FROM inventories
+		`, run.SnapshotDate)
+		if result.Error != nil {
+			return result.Error
+		}
+		copied = result.RowsAffected
+
+		return tx.Model(&InventorySnapshotRun{}).Where("snapshot_date = ?", run.SnapshotDate).
+			Update("row_count", copied).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return copied, nil
+}
+
+// ensureSnapshotPartition creates the monthly partition holding date.
+func ensureSnapshotPartition(tx *gorm.DB, date time.Time) error {
+	start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	return tx.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS inventory_snapshots_%04d_%02d PARTITION OF inventory_snapshots FOR VALUES FROM ('%s') TO ('%s')",
+		start.Year(), int(start.Month()), start.Format(time.DateOnly), end.Format(time.DateOnly),
+	)).Error
+}
+
+// stockAsOf builds a query of on-hand quantity per SKU at a hub as it stood
+// just before asOf. It starts from whichever is nearer in time, a snapshot or
+// the current rows, and replays the ledger between there and asOf: forward
+// from an earlier snapshot, backward from a later one.
+func stockAsOf(db *gorm.DB, hubID uuid.UUID, asOf time.Time) (string, []interface{}, error) {
+	var before, after []InventorySnapshotRun
+	if err := db.Where("taken_at <= ?", asOf).Order("taken_at DESC").Limit(1).Find(&before).Error; err != nil {
+		return "", nil, err
+	}
+	if err := db.Where("taken_at > ?", asOf).Order("taken_at").Limit(1).Find(&after).Error; err != nil {
+		return "", nil, err
+	}
+
+	const fromSnapshot = `SELECT sku_id, quantity FROM inventory_snapshots WHERE snapshot_date = ? AND hub_id = ?`
+	const wrap = `SELECT sku_id, SUM(quantity) AS quantity FROM (%s UNION ALL %s) replayed GROUP BY sku_id`
+
+	afterAt := time.Now()
+	if len(after) > 0 {
+		afterAt = after[0].TakenAt
+	}
+
+	switch {
+	case len(before) > 0 && asOf.Sub(before[0].TakenAt) <= afterAt.Sub(asOf):
+		query := fmt.Sprintf(wrap, fromSnapshot,
+			`SELECT sku_id, delta FROM inventory_movements WHERE hub_id = ? AND created_at >= ? AND created_at < ?`)
+		return query, []interface{}{before[0].SnapshotDate, hubID, hubID, before[0].TakenAt, asOf}, nil
+	case len(after) > 0:
+		query := fmt.Sprintf(wrap, fromSnapshot,
+			`SELECT sku_id, -delta FROM inventory_movements WHERE hub_id = ? AND created_at >= ? AND created_at < ?`)
+		return query, []interface{}{after[0].SnapshotDate, hubID, hubID, asOf, after[0].TakenAt}, nil
+	default:
+		query := fmt.Sprintf(wrap, `SELECT sku_id, quantity FROM inventories WHERE hub_id = ?`,
+			`SELECT sku_id, -delta FROM inventory_movements WHERE hub_id = ? AND created_at >= ?`)
+		return query, []interface{}{hubID, hubID, asOf}, nil
+	}
+}
+
+// inventoryViewsAsOf is inventoryViews as the hub stood just before asOf.
+// Reservations are not kept historically, so everything on hand counts as
+// available, and bundles use their current components.
+func inventoryViewsAsOf(db *gorm.DB, tenantID, hubID uuid.UUID, asOf time.Time) ([]InventoryView, error) {
+	stock, args, err := stockAsOf(db, hubID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH stock AS (` + stock + `),
+		bundles AS (
+			SELECT
+				b.bundle_sku_id,
+				MIN(GREATEST(COALESCE(cs.quantity, 0), 0) / b.quantity) AS on_hand
+			FROM bundle_components b
+			LEFT JOIN stock cs ON cs.sku_id = b.component_sku_id
+			GROUP BY b.bundle_sku_id
+		)
+		SELECT sku_id, sku_code, sku_name, bundle, on_hand, 0 AS reserved, on_hand AS quantity
+		FROM (
+			SELECT
+				s.id AS sku_id,
+				s.sku_code,
+				s.name AS sku_name,
+				bd.bundle_sku_id IS NOT NULL AS bundle,
+				CASE WHEN bd.bundle_sku_id IS NOT NULL THEN bd.on_hand
+					ELSE COALESCE(st.quantity, 0) END AS on_hand
+			FROM skus s
+			LEFT JOIN stock st ON st.sku_id = s.id
+			LEFT JOIN bundles bd ON bd.bundle_sku_id = s.id
+			WHERE s.tenant_id = ?
+		) v`
+	args = append(args, tenantID)
+
+	var result []InventoryView
+	err = db.Raw(query, args...).Scan(&result).Error
+	return result, err
+}
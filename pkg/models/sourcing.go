@@ -0,0 +1,88 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/sourcing"
+	"github.com/google/uuid"
+)
+
+type SourcingModel struct{}
+
+// GetTenantSourcingStrategy
+
+func (s SourcingModel) GetTenantSourcingStrategy(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	return GetTenantSourcingStrategy(ctx, tenantID)
+}
+
+func GetTenantSourcingStrategy(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	tenant, err := GetTenant(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+	if tenant.SourcingStrategy == "" {
+		return sourcing.DefaultStrategy, nil
+	}
+	return tenant.SourcingStrategy, nil
+}
+
+// GetSourcingCandidates
+
+func (s SourcingModel) GetSourcingCandidates(ctx context.Context, tenantID uuid.UUID, skuIDs []uuid.UUID) ([]sourcing.Candidate, error) {
+	return GetSourcingCandidates(ctx, tenantID, skuIDs)
+}
+
+// GetSourcingCandidates lists the tenant's hubs holding any of skuIDs with
+// what they can ship now: the ATP figures without inbound stock.
+func GetSourcingCandidates(ctx context.Context, tenantID uuid.UUID, skuIDs []uuid.UUID) ([]sourcing.Candidate, error) {
+	queries := make([]ATPQuery, len(skuIDs))
+	for i, skuID := range skuIDs {
+		queries[i] = ATPQuery{SkuID: skuID}
+	}
+
+	results, err := GetATP(ctx, tenantID, queries, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	available := make(map[uuid.UUID]map[uuid.UUID]int)
+	for _, result := range results {
+		for _, line := range result.Hubs {
+			shippable := max(line.OnHand-line.Reserved-line.SafetyStock, 0)
+			if shippable == 0 {
+				continue
+			}
+			if available[*line.HubID] == nil {
+				available[*line.HubID] = make(map[uuid.UUID]int)
+			}
+			available[*line.HubID][result.SkuID] = shippable
+		}
+	}
+	if len(available) == 0 {
+		return []sourcing.Candidate{}, nil
+	}
+
+	hubIDs := make([]uuid.UUID, 0, len(available))
+	for hubID := range available {
+		hubIDs = append(hubIDs, hubID)
+	}
+
+	var hubs []Hub
+	if err := getDB(ctx).Where("id IN ?", hubIDs).Find(&hubs).Error; err != nil {
+		return nil, err
+	}
+
+	candidates := make([]sourcing.Candidate, 0, len(hubs))
+	for _, hub := range hubs {
+		candidates = append(candidates, sourcing.Candidate{
+			HubID:      hub.ID,
+			HubName:    hub.Name,
+			PostalCode: hub.PostalCode,
+			Latitude:   hub.Latitude,
+			Longitude:  hub.Longitude,
+			Available:  available[hub.ID],
+		})
+	}
+	return candidates, nil
+}
@@ -0,0 +1,339 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/aditya-goyal-omniful/ims/pkg/constants"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	StockAlertReorder = "reorder"
+	StockAlertMinimum = "minimum"
+)
+
+const (
+	StockAlertOpen         = "open"
+	StockAlertAcknowledged = "acknowledged"
+	StockAlertResolved     = "resolved"
+)
+
+var (
+	ErrInvalidThresholds = errors.New("min_quantity must not exceed reorder_point")
+	ErrAlertResolved     = errors.New("alert is already resolved")
+)
+
+type StockAlert struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID       uuid.UUID  `gorm:"type:uuid;not null" json:"tenant_id"`
+	InventoryID    uuid.UUID  `gorm:"type:uuid;not null" json:"inventory_id"`
+	HubID          uuid.UUID  `gorm:"type:uuid;not null" json:"hub_id"`
+	SkuID          uuid.UUID  `gorm:"type:uuid;not null" json:"sku_id"`
+	Level          string     `gorm:"not null" json:"level"`
+	Threshold      int        `gorm:"not null" json:"threshold"`
+	Quantity       int        `gorm:"not null" json:"quantity"` // sellable quantity when last evaluated
+	Status         string     `gorm:"not null" json:"status"`
+	AcknowledgedBy string     `json:"acknowledged_by"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// OptionalInt is a JSON number that can also be left out. Set tells a field
+// sent as null, which clears it, from one left out, which leaves it as is.
+type OptionalInt struct {
+	Set   bool
+	Value *int
+}
+
+func (o *OptionalInt) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	var value int
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	o.Value = &value
+	return nil
+}
+
+// StockThresholds changes only the settings present in the request.
+type StockThresholds struct {
+	MinQuantity  OptionalInt `json:"min_quantity" swaggertype:"integer"`
+	ReorderPoint OptionalInt `json:"reorder_point" swaggertype:"integer"`
+	SafetyStock  OptionalInt `json:"safety_stock" swaggertype:"integer"`         // held back from available-to-promise; null is zero
+	LeadTimeDays OptionalInt `json:"lead_time_days" swaggertype:"integer"`       // days a reorder takes to arrive; null uses the default
+	DaysOfCover  OptionalInt `json:"target_days_of_cover" swaggertype:"integer"` // demand to hold after a reorder arrives; null uses the default
+}
+
+type StockAlertFilter struct {
+	HubID  *uuid.UUID
+	Status string // empty lists every unresolved alert
+}
+
+type StockAlertModel struct{}
+
+// stockDecrements carries inventory IDs from stock changes to the evaluator.
+// Sends never block: a full queue drops the signal and the periodic sweep
+// picks the row up instead.
+var stockDecrements = make(chan uuid.UUID, constants.StockAlertQueueSize)
+
+func signalStockDecrement(inventoryID uuid.UUID) {
+	select {
+	case stockDecrements <- inventoryID:
+	default:
+	}
+}
+
+// StockDecrements is the queue read by the low-stock evaluator job.
+func StockDecrements() <-chan uuid.UUID {
+	return stockDecrements
+}
+
+// SetStockThresholds
+
+func (i InventoryModel) SetStockThresholds(ctx context.Context, inventoryID uuid.UUID, thresholds StockThresholds) (*Inventory, error) {
+	return SetStockThresholds(ctx, inventoryID, thresholds)
+}
+
+// SetStockThresholds changes the thresholds, safety stock and replenishment
+// settings of a row that are set in thresholds and leaves the rest alone; a
+// null value clears one. The row is re-evaluated right away so a raised
+// threshold alerts too.
+func SetStockThresholds(ctx context.Context, inventoryID uuid.UUID, thresholds StockThresholds) (*Inventory, error) {
+	var inv *Inventory
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		inv, err = lockInventoryByID(tx, inventoryID)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if thresholds.MinQuantity.Set {
+			inv.MinQuantity = thresholds.MinQuantity.Value
+			updates["min_quantity"] = inv.MinQuantity
+		}
+		if thresholds.ReorderPoint.Set {
+			inv.ReorderPoint = thresholds.ReorderPoint.Value
+			updates["reorder_point"] = inv.ReorderPoint
+		}
+		if thresholds.SafetyStock.Set {
+			inv.SafetyStock = 0
+			if thresholds.SafetyStock.Value != nil {
+				inv.SafetyStock = *thresholds.SafetyStock.Value
+			}
+			updates["safety_stock"] = inv.SafetyStock
+		}
+		if thresholds.LeadTimeDays.Set {
+			inv.LeadTimeDays = thresholds.LeadTimeDays.Value
+			updates["lead_time_days"] = inv.LeadTimeDays
+		}
+		if thresholds.DaysOfCover.Set {
+			inv.DaysOfCover = thresholds.DaysOfCover.Value
+			updates["target_days_of_cover"] = inv.DaysOfCover
+		}
+
+		// Checked against the stored value of whichever side was left out
+		if inv.MinQuantity != nil && inv.ReorderPoint != nil && *inv.MinQuantity > *inv.ReorderPoint {
+			return ErrInvalidThresholds
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Model(&Inventory{}).Where("id = ?", inventoryID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		return evaluateStockAlerts(tx, inv)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+// EvaluateStockAlerts
+
+// EvaluateStockAlerts compares a row's sellable quantity with its thresholds.
+// Locking the row waits out the transaction that signalled the decrement, so
+// only committed quantities are judged.
+func EvaluateStockAlerts(ctx context.Context, inventoryID uuid.UUID) error {
+	return getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		inv, err := lockInventoryByID(tx, inventoryID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		return evaluateStockAlerts(tx, inv)
+	})
+}
+
+// SweepStockAlerts re-evaluates every row that is below a threshold or still
+// has an unresolved alert, catching signals dropped from a full queue.
+func SweepStockAlerts(ctx context.Context) (int, error) {
+	var ids []uuid.UUID
+	err := getDB(ctx).Model(&Inventory{}).
+		Where("quantity < min_quantity OR quantity < reorder_point").
+		Or("id IN (?)", getDB(ctx).Model(&StockAlert{}).Select("inventory_id").Where("status <> ?", StockAlertResolved)).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		if err := EvaluateStockAlerts(ctx, id); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+// evaluateStockAlerts raises or resolves the alert of each level for a row
+// locked in tx.
+func evaluateStockAlerts(tx *gorm.DB, inv *Inventory) error {
+	levels := []struct {
+		level     string
+		threshold *int
+	}{
+		{StockAlertReorder, inv.ReorderPoint},
+		{StockAlertMinimum, inv.MinQuantity},
+	}
+
+	for _, l := range levels {
+		var err error
+		if l.threshold != nil && inv.Quantity < *l.threshold {
+			err = raiseStockAlert(tx, inv, l.level, *l.threshold)
+		} else {
+			err = resolveStockAlert(tx, inv.ID, l.level)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// raiseStockAlert keeps a single unresolved alert per row and level. An alert
+// resolved within the reopen window is reopened instead of raising a new one,
+// so stock hovering around a threshold does not repeat itself.
+func raiseStockAlert(tx *gorm.DB, inv *Inventory, level string, threshold int) error {
+	updates := map[string]interface{}{"threshold": threshold, "quantity": inv.Quantity}
+
+	result := tx.Model(&StockAlert{}).
+		Where("inventory_id = ? AND level = ? AND status <> ?", inv.ID, level, StockAlertResolved).
+		Updates(updates)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	var recent StockAlert
+	err := tx.Where("inventory_id = ? AND level = ? AND resolved_at > ?", inv.ID, level, time.Now().Add(-constants.StockAlertReopenWindow)).
+		Order("resolved_at DESC").
+		First(&recent).Error
+	if err == nil {
+		updates["status"] = StockAlertOpen
+		if recent.AcknowledgedAt != nil {
+			updates["status"] = StockAlertAcknowledged
+		}
+		updates["resolved_at"] = nil
+		return tx.Model(&StockAlert{}).Where("id = ?", recent.ID).Updates(updates).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&StockAlert{
+		TenantID:    inv.TenantID,
+		InventoryID: inv.ID,
+		HubID:       inv.HubID,
+		SkuID:       inv.SkuID,
+		Level:       level,
+		Threshold:   threshold,
+		Quantity:    inv.Quantity,
+		Status:      StockAlertOpen,
+	}).Error
+}
+
+func resolveStockAlert(tx *gorm.DB, inventoryID uuid.UUID, level string) error {
+	return tx.Model(&StockAlert{}).
+		Where("inventory_id = ? AND level = ? AND status <> ?", inventoryID, level, StockAlertResolved).
+		Updates(map[string]interface{}{"status": StockAlertResolved, "resolved_at": time.Now()}).Error
+}
+
+// GetStockAlerts
+
+func (s StockAlertModel) GetStockAlerts(ctx context.Context, tenantID uuid.UUID, filter StockAlertFilter) ([]StockAlert, error) {
+	return GetStockAlerts(ctx, tenantID, filter)
+}
+
+func GetStockAlerts(ctx context.Context, tenantID uuid.UUID, filter StockAlertFilter) ([]StockAlert, error) {
+	query := getDB(ctx).Where("tenant_id = ?", tenantID)
+	if filter.HubID != nil {
+		query = query.Where("hub_id = ?", *filter.HubID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	} else {
+		query = query.Where("status <> ?", StockAlertResolved)
+	}
+
+	var alerts []StockAlert
+	if err := query.Order("created_at DESC").Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// AcknowledgeStockAlert
+
+func (s StockAlertModel) AcknowledgeStockAlert(ctx context.Context, tenantID, id uuid.UUID, actor string) (*StockAlert, error) {
+	return AcknowledgeStockAlert(ctx, tenantID, id, actor)
+}
+
+// AcknowledgeStockAlert marks an unresolved alert as seen. Acknowledging it
+// again keeps the first acknowledgement.
+func AcknowledgeStockAlert(ctx context.Context, tenantID, id uuid.UUID, actor string) (*StockAlert, error) {
+	var alert StockAlert
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&alert, "id = ? AND tenant_id = ?", id, tenantID).Error; err != nil {
+			return err
+		}
+
+		switch alert.Status {
+		case StockAlertResolved:
+			return ErrAlertResolved
+		case StockAlertAcknowledged:
+			return nil
+		}
+
+		now := time.Now()
+		alert.Status = StockAlertAcknowledged
+		alert.AcknowledgedBy = actor
+		alert.AcknowledgedAt = &now
+		return tx.Model(&StockAlert{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":          alert.Status,
+			"acknowledged_by": actor,
+			"acknowledged_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &alert, nil
+}
@@ -0,0 +1,307 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	StocktakeOpen     = "open"
+	StocktakeApproved = "approved"
+)
+
+var (
+	ErrInvalidStocktake      = errors.New("invalid stocktake")
+	ErrStocktakeClosed       = errors.New("stocktake is not open")
+	ErrStocktakeSelfApproval = errors.New("stocktake must be approved by someone other than its creator")
+)
+
+type Stocktake struct {
+	ID         uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID   uuid.UUID       `gorm:"type:uuid;not null" json:"tenant_id"`
+	HubID      uuid.UUID       `gorm:"type:uuid;not null" json:"hub_id"`
+	Status     string          `gorm:"not null" json:"status"`
+	CreatedBy  string          `json:"created_by"`
+	ApprovedBy string          `json:"approved_by"`
+	ApprovedAt *time.Time      `json:"approved_at"`
+	CreatedAt  time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+	Lines      []StocktakeLine `gorm:"foreignKey:StocktakeID" json:"lines"`
+}
+
+type StocktakeLine struct {
+	ID               uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	StocktakeID      uuid.UUID  `gorm:"type:uuid;not null" json:"stocktake_id"`
+	InventoryID      uuid.UUID  `gorm:"type:uuid;not null" json:"inventory_id"`
+	SkuID            uuid.UUID  `gorm:"type:uuid;not null" json:"sku_id"`
+	ExpectedQuantity int        `gorm:"not null" json:"expected_quantity"`
+	CountedQuantity  *int       `json:"counted_quantity"`
+	Variance         *int       `json:"variance"`          // counted minus expected
+	AdjustedQuantity *int       `json:"adjusted_quantity"` // delta applied on approval
+	AdjustmentID     *uuid.UUID `gorm:"type:uuid" json:"adjustment_id"`
+	CountedAt        *time.Time `json:"counted_at"`
+}
+
+type StocktakeVarianceLine struct {
+	SkuID            uuid.UUID `json:"sku_id"`
+	SkuCode          string    `json:"sku_code"`
+	ExpectedQuantity int       `json:"expected_quantity"`
+	CountedQuantity  *int      `json:"counted_quantity"`
+	Variance         *int      `json:"variance"`
+	AdjustedQuantity *int      `json:"adjusted_quantity"`
+}
+
+type StocktakeVarianceReport struct {
+	StocktakeID       uuid.UUID               `json:"stocktake_id"`
+	HubID             uuid.UUID               `json:"hub_id"`
+	Status            string                  `json:"status"`
+	LinesTotal        int                     `json:"lines_total"`
+	LinesCounted      int                     `json:"lines_counted"`
+	LinesWithVariance int                     `json:"lines_with_variance"`
+	NetVariance       int                     `json:"net_variance"`
+	AbsoluteVariance  int                     `json:"absolute_variance"`
+	Lines             []StocktakeVarianceLine `json:"lines"`
+}
+
+type StocktakeModel struct{}
+
+// CreateStocktake
+
+func (s StocktakeModel) CreateStocktake(ctx context.Context, stocktake *Stocktake, skuIDs []uuid.UUID) error {
+	return CreateStocktake(ctx, stocktake, skuIDs)
+}
+
+// CreateStocktake opens a session for a hub and snapshots the expected
+// (sellable) quantity of every inventory row in it, or only of skuIDs when
+// given. Serialized SKUs are counted through their serials instead.
+func CreateStocktake(ctx context.Context, stocktake *Stocktake, skuIDs []uuid.UUID) error {
+	hub, err := GetHub(ctx, stocktake.HubID)
+	if err != nil {
+		return err
+	}
+	if hub.TenantID != stocktake.TenantID {
+		return ErrInvalidStocktake
+	}
+
+	return getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Table("inventories i").
+			Select("i.id AS inventory_id, i.sku_id, i.quantity AS expected_quantity").
+			Joins("JOIN skus s ON s.id = i.sku_id").
+			Where("i.hub_id = ? AND i.tenant_id = ? AND s.serialized = false", stocktake.HubID, stocktake.TenantID)
+		if len(skuIDs) > 0 {
+			query = query.Where("i.sku_id IN ?", skuIDs)
+		}
+
+		var lines []StocktakeLine
+		if err := query.Order("i.sku_id").Scan(&lines).Error; err != nil {
+			return err
+		}
+		if len(lines) == 0 {
+			return fmt.Errorf("%w: no inventory to count", ErrInvalidStocktake)
+		}
+
+		stocktake.Status = StocktakeOpen
+		stocktake.Lines = lines
+		return tx.Create(stocktake).Error
+	})
+}
+
+// GetStocktake
+
+func (s StocktakeModel) GetStocktake(ctx context.Context, tenantID, id uuid.UUID) (*Stocktake, error) {
+	return GetStocktake(ctx, tenantID, id)
+}
+
+func GetStocktake(ctx context.Context, tenantID, id uuid.UUID) (*Stocktake, error) {
+	var stocktake Stocktake
+	err := getDB(ctx).Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("sku_id") }).
+		First(&stocktake, "id = ? AND tenant_id = ?", id, tenantID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stocktake, nil
+}
+
+// lockOpenStocktake locks a session row and checks it is still open.
+func lockOpenStocktake(tx *gorm.DB, tenantID, id uuid.UUID) (*Stocktake, error) {
+	var stocktake Stocktake
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&stocktake, "id = ? AND tenant_id = ?", id, tenantID).Error
+	if err != nil {
+		return nil, err
+	}
+	if stocktake.Status != StocktakeOpen {
+		return nil, ErrStocktakeClosed
+	}
+	return &stocktake, nil
+}
+
+// RecordStocktakeCounts
+
+func (s StocktakeModel) RecordStocktakeCounts(ctx context.Context, tenantID, id uuid.UUID, counts map[uuid.UUID]int) error {
+	return RecordStocktakeCounts(ctx, tenantID, id, counts)
+}
+
+// RecordStocktakeCounts stores counted quantities keyed by SKU and their
+// variance against the snapshot. Counting a SKU again overwrites it.
+func RecordStocktakeCounts(ctx context.Context, tenantID, id uuid.UUID, counts map[uuid.UUID]int) error {
+	return getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockOpenStocktake(tx, tenantID, id); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for skuID, counted := range counts {
+			result := tx.Model(&StocktakeLine{}).
+				Where("stocktake_id = ? AND sku_id = ?", id, skuID).
+				Updates(map[string]interface{}{
+					"counted_quantity": counted,
+					"variance":         gorm.Expr("? - expected_quantity", counted),
+					"counted_at":       now,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("%w: sku %s is not in the stocktake", ErrInvalidStocktake, skuID)
+			}
+		}
+		return nil
+	})
+}
+
+// ApproveStocktake
+
+func (s StocktakeModel) ApproveStocktake(ctx context.Context, tenantID, id uuid.UUID) (*Stocktake, error) {
+	return ApproveStocktake(ctx, tenantID, id)
+}
+
+// ApproveStocktake closes a session and books each counted variance,
+// measured against the current quantity so stock that moved since the
+// snapshot is kept, as an adjustment with the stocktake reason code.
+// Variances above the tenant's thresholds stay pending like any other
+// adjustment. The approver must differ from whoever opened the session.
+// Uncounted lines are left alone.
+func ApproveStocktake(ctx context.Context, tenantID, id uuid.UUID) (*Stocktake, error) {
+	meta := movementMetaFromContext(ctx)
+	if meta.ReferenceID == "" {
+		meta.ReferenceID = id.String()
+	}
+	ctx = WithMovementMeta(ctx, meta)
+
+	var stocktake *Stocktake
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		stocktake, err = lockOpenStocktake(tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+		if meta.Actor == "" || meta.Actor == stocktake.CreatedBy {
+			return ErrStocktakeSelfApproval
+		}
+
+		if err := tx.Where("stocktake_id = ?", id).Order("sku_id").Find(&stocktake.Lines).Error; err != nil {
+			return err
+		}
+
+		for i := range stocktake.Lines {
+			line := &stocktake.Lines[i]
+			if line.Variance == nil {
+				continue
+			}
+
+			inv, err := lockInventoryByID(tx, line.InventoryID)
+			if err != nil {
+				return err
+			}
+
+			delta := max(inv.Quantity+*line.Variance, 0) - inv.Quantity
+			adj, err := adjustQuantity(ctx, tx, inv, delta, StocktakeReasonCode.Code)
+			if err != nil {
+				return err
+			}
+
+			updates := map[string]interface{}{}
+			if adj == nil || adj.Status == AdjustmentApplied {
+				applied := delta
+				line.AdjustedQuantity = &applied
+				updates["adjusted_quantity"] = applied
+			}
+			if adj != nil {
+				line.AdjustmentID = &adj.ID
+				updates["adjustment_id"] = adj.ID
+			}
+			if err := tx.Model(&StocktakeLine{}).Where("id = ?", line.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		stocktake.Status = StocktakeApproved
+		stocktake.ApprovedBy = meta.Actor
+		stocktake.ApprovedAt = &now
+		return tx.Model(&Stocktake{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":      stocktake.Status,
+			"approved_by": stocktake.ApprovedBy,
+			"approved_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stocktake, nil
+}
+
+// GetStocktakeVariance
+
+func (s StocktakeModel) GetStocktakeVariance(ctx context.Context, tenantID, id uuid.UUID) (*StocktakeVarianceReport, error) {
+	return GetStocktakeVariance(ctx, tenantID, id)
+}
+
+// GetStocktakeVariance summarises the counts of a session, listing lines
+// with the largest absolute variance first.
+func GetStocktakeVariance(ctx context.Context, tenantID, id uuid.UUID) (*StocktakeVarianceReport, error) {
+	var stocktake Stocktake
+	if err := getDB(ctx).First(&stocktake, "id = ? AND tenant_id = ?", id, tenantID).Error; err != nil {
+		return nil, err
+	}
+
+	var lines []StocktakeVarianceLine
+	err := getDB(ctx).Table("stocktake_lines l").
+		Select("l.sku_id, s.sku_code, l.expected_quantity, l.counted_quantity, l.variance, l.adjusted_quantity").
+		Joins("JOIN skus s ON s.id = l.sku_id").
+		Where("l.stocktake_id = ?", id).
+		Order("ABS(l.variance) DESC NULLS LAST, s.sku_code").
+		Scan(&lines).Error
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StocktakeVarianceReport{
+		StocktakeID: stocktake.ID,
+		HubID:       stocktake.HubID,
+		Status:      stocktake.Status,
+		LinesTotal:  len(lines),
+		Lines:       lines,
+	}
+	for _, line := range lines {
+		if line.Variance == nil {
+			continue
+		}
+		report.LinesCounted++
+		if *line.Variance != 0 {
+			report.LinesWithVariance++
+		}
+		report.NetVariance += *line.Variance
+		report.AbsoluteVariance += max(*line.Variance, -*line.Variance)
+	}
+
+	return report, nil
+}
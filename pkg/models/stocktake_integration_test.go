@@ -0,0 +1,70 @@
+//go:build integration
+
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApproveStocktake checks that the creator cannot approve their own
+// count and that variances are booked as stocktake adjustments, held for
+// review above the tenant's threshold.
+func TestApproveStocktake(t *testing.T) {
+	ctx := context.Background()
+	tenantID, sellerID := seedTenant(t, ctx)
+	hubID := seedHub(t, ctx, tenantID)
+	small := seedInventory(t, ctx, tenantID, hubID, seedSku(t, ctx, tenantID, sellerID), 10)
+	large := seedInventory(t, ctx, tenantID, hubID, seedSku(t, ctx, tenantID, sellerID), 10)
+
+	if err := getDB(ctx).Model(&Tenant{}).Where("id = ?", tenantID).Update("adjustment_approval_units", 3).Error; err != nil {
+		t.Fatalf("seed thresholds: %v", err)
+	}
+
+	stocktake := &Stocktake{TenantID: tenantID, HubID: hubID, CreatedBy: "counter"}
+	if err := CreateStocktake(ctx, stocktake, nil); err != nil {
+		t.Fatalf("create stocktake: %v", err)
+	}
+	counts := map[uuid.UUID]int{small.SkuID: 8, large.SkuID: 2}
+	if err := RecordStocktakeCounts(ctx, tenantID, stocktake.ID, counts); err != nil {
+		t.Fatalf("record counts: %v", err)
+	}
+
+	_, err := ApproveStocktake(WithMovementMeta(ctx, MovementMeta{Actor: "counter"}), tenantID, stocktake.ID)
+	assert.ErrorIs(t, err, ErrStocktakeSelfApproval)
+
+	approved, err := ApproveStocktake(WithMovementMeta(ctx, MovementMeta{Actor: "lead"}), tenantID, stocktake.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "lead", approved.ApprovedBy)
+
+	for _, line := range approved.Lines {
+		if !assert.NotNil(t, line.AdjustmentID) {
+			continue
+		}
+		var adj InventoryAdjustment
+		if err := getDB(ctx).First(&adj, "id = ?", *line.AdjustmentID).Error; err != nil {
+			t.Fatalf("load adjustment: %v", err)
+		}
+		assert.Equal(t, StocktakeReasonCode.Code, adj.ReasonCode)
+
+		var inv Inventory
+		if err := getDB(ctx).First(&inv, "id = ?", line.InventoryID).Error; err != nil {
+			t.Fatalf("load inventory: %v", err)
+		}
+		switch line.SkuID {
+		case small.SkuID:
+			assert.Equal(t, AdjustmentApplied, adj.Status)
+			assert.Equal(t, -2, *line.AdjustedQuantity)
+			assert.Equal(t, 8, inv.Quantity)
+		case large.SkuID:
+			assert.Equal(t, AdjustmentPending, adj.Status)
+			assert.Nil(t, line.AdjustedQuantity)
+			assert.Equal(t, 10, inv.Quantity)
+		}
+	}
+}
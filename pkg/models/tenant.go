@@ -8,10 +8,14 @@ import (
 )
 
 type Tenant struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Name      string    `gorm:"not null;unique" json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name                      string    `gorm:"not null;unique" json:"name"`
+	SourcingStrategy          string    `gorm:"default:single_hub" json:"sourcing_strategy"`
+	AdjustmentApprovalUnits   *int      `json:"adjustment_approval_units"`            // adjustments of more units need approval
+	AdjustmentApprovalPercent *float64  `json:"adjustment_approval_percent"`          // as do those above this % of on-hand
+	ValuationMethod           string    `gorm:"default:fifo" json:"valuation_method"` // fifo or weighted_average
+	CreatedAt                 time.Time `json:"created_at"`
+	UpdatedAt                 time.Time `json:"updated_at"`
 }
 
 type TenantModel struct{}
@@ -0,0 +1,357 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	TransferCreated    = "created"
+	TransferDispatched = "dispatched"
+	TransferReceived   = "received"
+)
+
+var (
+	ErrInvalidTransfer = errors.New("invalid transfer order")
+	ErrTransferState   = errors.New("transfer order is not in the required status")
+)
+
+type TransferOrder struct {
+	ID               uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID         uuid.UUID           `gorm:"type:uuid;not null" json:"tenant_id"`
+	SourceHubID      uuid.UUID           `gorm:"type:uuid;not null" json:"source_hub_id"`
+	DestinationHubID uuid.UUID           `gorm:"type:uuid;not null" json:"destination_hub_id"`
+	Status           string              `gorm:"not null" json:"status"`
+	HasDiscrepancy   bool                `gorm:"not null;default:false" json:"has_discrepancy"`
+	ExpectedAt       *time.Time          `json:"expected_at"`
+	DispatchedAt     *time.Time          `json:"dispatched_at"`
+	ReceivedAt       *time.Time          `json:"received_at"`
+	CreatedAt        time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+	Lines            []TransferOrderLine `gorm:"foreignKey:TransferOrderID" json:"lines"`
+}
+
+type TransferOrderLine struct {
+	ID               uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TransferOrderID  uuid.UUID `gorm:"type:uuid;not null" json:"transfer_order_id"`
+	SkuID            uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
+	Quantity         int       `gorm:"not null" json:"quantity"`
+	ReceivedQuantity int       `gorm:"not null;default:0" json:"received_quantity"`
+	Discrepancy      int       `gorm:"not null;default:0" json:"discrepancy"` // dispatched minus received
+	UnitCost         *float64  `gorm:"type:numeric(14,4)" json:"unit_cost"`   // average cost of the dispatched units
+}
+
+type TransferModel struct{}
+
+// CreateTransferOrder
+
+func (t TransferModel) CreateTransferOrder(ctx context.Context, order *TransferOrder) error {
+	return CreateTransferOrder(ctx, order)
+}
+
+// CreateTransferOrder saves a transfer between two hubs of the order's
+// tenant. No stock moves until it is dispatched.
+func CreateTransferOrder(ctx context.Context, order *TransferOrder) error {
+	for _, hubID := range []uuid.UUID{order.SourceHubID, order.DestinationHubID} {
+		hub, err := GetHub(ctx, hubID)
+		if err != nil {
+			return err
+		}
+		if hub.TenantID != order.TenantID {
+			return ErrInvalidTransfer
+		}
+	}
+
+	skuIDs := make([]uuid.UUID, len(order.Lines))
+	for i, line := range order.Lines {
+		skuIDs[i] = line.SkuID
+	}
+
+	var known int64
+	if err := getDB(ctx).Model(&Sku{}).Where("id IN ? AND tenant_id = ?", skuIDs, order.TenantID).Count(&known).Error; err != nil {
+		return err
+	}
+	if int(known) != len(skuIDs) {
+		return ErrInvalidTransfer
+	}
+
+	// Keep lines in SKU order so dispatch and receive lock rows consistently
+	sort.Slice(order.Lines, func(i, j int) bool {
+		return order.Lines[i].SkuID.String() < order.Lines[j].SkuID.String()
+	})
+	order.Status = TransferCreated
+
+	return getDB(ctx).Create(order).Error
+}
+
+// GetTransferOrder
+
+func (t TransferModel) GetTransferOrder(ctx context.Context, tenantID, id uuid.UUID) (*TransferOrder, error) {
+	return GetTransferOrder(ctx, tenantID, id)
+}
+
+func GetTransferOrder(ctx context.Context, tenantID, id uuid.UUID) (*TransferOrder, error) {
+	var order TransferOrder
+	err := getDB(ctx).Preload("Lines", orderTransferLines).
+		First(&order, "id = ? AND tenant_id = ?", id, tenantID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetTransferOrders
+
+func (t TransferModel) GetTransferOrders(ctx context.Context, tenantID uuid.UUID, status string) ([]TransferOrder, error) {
+	return GetTransferOrders(ctx, tenantID, status)
+}
+
+// GetTransferOrders lists a tenant's transfers, newest first, optionally
+// only those in one status.
+func GetTransferOrders(ctx context.Context, tenantID uuid.UUID, status string) ([]TransferOrder, error) {
+	query := getDB(ctx).Preload("Lines", orderTransferLines).Where("tenant_id = ?", tenantID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var orders []TransferOrder
+	if err := query.Order("created_at DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func orderTransferLines(db *gorm.DB) *gorm.DB {
+	return db.Order("sku_id")
+}
+
+// lockTransferOrder loads a transfer and its lines with the order row locked.
+func lockTransferOrder(tx *gorm.DB, tenantID, id uuid.UUID, status string) (*TransferOrder, error) {
+	var order TransferOrder
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&order, "id = ? AND tenant_id = ?", id, tenantID).Error
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != status {
+		return nil, ErrTransferState
+	}
+
+	if err := tx.Where("transfer_order_id = ?", order.ID).Order("sku_id").Find(&order.Lines).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// lockTransferRows locks the source and destination rows of every line of a
+// transfer being received, creating missing destination rows. Rows are locked
+// by hub then SKU, like mergeStockLines, rather than line by line: two
+// transfers received at once in opposite directions would otherwise each hold
+// a row the other is waiting for.
+func lockTransferRows(tx *gorm.DB, order *TransferOrder) (map[stockRowKey]*Inventory, error) {
+	keys := make([]stockRowKey, 0, 2*len(order.Lines))
+	for _, line := range order.Lines {
+		keys = append(keys,
+			stockRowKey{order.SourceHubID, line.SkuID},
+			stockRowKey{order.DestinationHubID, line.SkuID},
+		)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].hubID != keys[j].hubID {
+			return keys[i].hubID.String() < keys[j].hubID.String()
+		}
+		return keys[i].skuID.String() < keys[j].skuID.String()
+	})
+
+	rows := make(map[stockRowKey]*Inventory, len(keys))
+	for _, key := range keys {
+		if _, ok := rows[key]; ok {
+			continue
+		}
+
+		var (
+			inv *Inventory
+			err error
+		)
+		if key.hubID == order.SourceHubID {
+			inv, err = lockInventoryBySkuHub(tx, key.skuID, key.hubID)
+		} else {
+			inv, err = lockOrCreateInventory(tx, order.TenantID, key.hubID, key.skuID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows[key] = inv
+	}
+	return rows, nil
+}
+
+// withTransferMeta tags the ledger entries of a transfer step.
+func withTransferMeta(ctx context.Context, order *TransferOrder, reason string) context.Context {
+	meta := movementMetaFromContext(ctx)
+	meta.Reason = reason
+	if meta.ReferenceID == "" {
+		meta.ReferenceID = order.ID.String()
+	}
+	return WithMovementMeta(ctx, meta)
+}
+
+// DispatchTransferOrder
+
+func (t TransferModel) DispatchTransferOrder(ctx context.Context, tenantID, id uuid.UUID) (*TransferOrder, error) {
+	return DispatchTransferOrder(ctx, tenantID, id)
+}
+
+// DispatchTransferOrder takes every line out of the source hub's sellable
+// stock into its in-transit bucket. Either all lines go or none do.
+func DispatchTransferOrder(ctx context.Context, tenantID, id uuid.UUID) (*TransferOrder, error) {
+	var order *TransferOrder
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		order, err = lockTransferOrder(tx, tenantID, id, TransferCreated)
+		if err != nil {
+			return err
+		}
+
+		// Lines come ordered by SKU, the same order lockTransferRows uses
+		ctx := withTransferMeta(ctx, order, MovementTransferDispatch)
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			inv, err := lockInventoryBySkuHub(tx, line.SkuID, order.SourceHubID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w for sku %s", ErrInsufficientStock, line.SkuID)
+			}
+			if err != nil {
+				return err
+			}
+
+			// The destination books the units at what they cost here
+			cost, ok, err := nextUnitCost(tx, inv, line.Quantity)
+			if err != nil {
+				return err
+			}
+			if ok {
+				if err := tx.Model(&TransferOrderLine{}).Where("id = ?", line.ID).Update("unit_cost", cost).Error; err != nil {
+					return err
+				}
+				line.UnitCost = &cost
+			}
+
+			if err := takeFromBucket(ctx, tx, inv, StatusSellable, line.Quantity); err != nil {
+				if errors.Is(err, ErrInsufficientStock) {
+					return fmt.Errorf("%w for sku %s", err, line.SkuID)
+				}
+				return err
+			}
+			if err := addToBucket(ctx, tx, inv, StatusInTransit, line.Quantity); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		order.Status = TransferDispatched
+		order.DispatchedAt = &now
+		return tx.Model(&TransferOrder{}).Where("id = ?", order.ID).Updates(map[string]interface{}{
+			"status":        order.Status,
+			"dispatched_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// ReceiveTransferOrder
+
+func (t TransferModel) ReceiveTransferOrder(ctx context.Context, tenantID, id uuid.UUID, received map[uuid.UUID]int) (*TransferOrder, error) {
+	return ReceiveTransferOrder(ctx, tenantID, id, received)
+}
+
+// ReceiveTransferOrder closes a dispatched transfer. Each line leaves the
+// source hub's in-transit bucket in full and the received quantity, keyed by
+// SKU, is added to the destination's sellable stock. Lines missing from
+// received count as nothing arrived; any shortfall is kept as a discrepancy.
+func ReceiveTransferOrder(ctx context.Context, tenantID, id uuid.UUID, received map[uuid.UUID]int) (*TransferOrder, error) {
+	var order *TransferOrder
+	err := getDB(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		order, err = lockTransferOrder(tx, tenantID, id, TransferDispatched)
+		if err != nil {
+			return err
+		}
+
+		lineSkus := make(map[uuid.UUID]bool, len(order.Lines))
+		for _, line := range order.Lines {
+			lineSkus[line.SkuID] = true
+		}
+		for skuID := range received {
+			if !lineSkus[skuID] {
+				return fmt.Errorf("%w: sku %s is not on the transfer", ErrInvalidTransfer, skuID)
+			}
+		}
+
+		rows, err := lockTransferRows(tx, order)
+		if err != nil {
+			return err
+		}
+
+		ctx := withTransferMeta(ctx, order, MovementTransferReceipt)
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			got := received[line.SkuID]
+			if got > line.Quantity {
+				return fmt.Errorf("%w: received more than dispatched for sku %s", ErrInvalidTransfer, line.SkuID)
+			}
+
+			source := rows[stockRowKey{order.SourceHubID, line.SkuID}]
+			if err := takeFromBucket(ctx, tx, source, StatusInTransit, line.Quantity); err != nil {
+				return err
+			}
+
+			destination := rows[stockRowKey{order.DestinationHubID, line.SkuID}]
+			ctx, err := withUnitCost(ctx, line.UnitCost)
+			if err != nil {
+				return err
+			}
+			if err := addToBucket(ctx, tx, destination, StatusSellable, got); err != nil {
+				return err
+			}
+
+			line.ReceivedQuantity = got
+			line.Discrepancy = line.Quantity - got
+			if line.Discrepancy != 0 {
+				order.HasDiscrepancy = true
+			}
+			err = tx.Model(&TransferOrderLine{}).Where("id = ?", line.ID).Updates(map[string]interface{}{
+				"received_quantity": line.ReceivedQuantity,
+				"discrepancy":       line.Discrepancy,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		order.Status = TransferReceived
+		order.ReceivedAt = &now
+		return tx.Model(&TransferOrder{}).Where("id = ?", order.ID).Updates(map[string]interface{}{
+			"status":          order.Status,
+			"has_discrepancy": order.HasDiscrepancy,
+			"received_at":     now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
@@ -0,0 +1,75 @@
+//go:build integration
+
+package models
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReceiveTransferOrdersOppositeDirections receives transfers A→B and
+// B→A of the same SKUs at the same time. Locking line by line could leave
+// each holding a row the other waits for; Postgres would then abort one as
+// a deadlock.
+func TestReceiveTransferOrdersOppositeDirections(t *testing.T) {
+	const rounds = 20
+
+	ctx := context.Background()
+	tenantID, sellerID := seedTenant(t, ctx)
+	hubA, hubB := seedHub(t, ctx, tenantID), seedHub(t, ctx, tenantID)
+	skus := []uuid.UUID{seedSku(t, ctx, tenantID, sellerID), seedSku(t, ctx, tenantID, sellerID)}
+	for _, hubID := range []uuid.UUID{hubA, hubB} {
+		for _, skuID := range skus {
+			seedInventory(t, ctx, tenantID, hubID, skuID, rounds)
+		}
+	}
+
+	dispatch := func(source, destination uuid.UUID) *TransferOrder {
+		order := &TransferOrder{TenantID: tenantID, SourceHubID: source, DestinationHubID: destination, Status: TransferCreated}
+		for _, skuID := range skus {
+			order.Lines = append(order.Lines, TransferOrderLine{SkuID: skuID, Quantity: 1})
+		}
+		if err := getDB(ctx).Create(order).Error; err != nil {
+			t.Fatalf("create transfer: %v", err)
+		}
+		if _, err := DispatchTransferOrder(ctx, tenantID, order.ID); err != nil {
+			t.Fatalf("dispatch transfer: %v", err)
+		}
+		return order
+	}
+
+	for i := 0; i < rounds; i++ {
+		orders := []*TransferOrder{dispatch(hubA, hubB), dispatch(hubB, hubA)}
+
+		var wg sync.WaitGroup
+		errs := make([]error, len(orders))
+		start := make(chan struct{})
+		for j, order := range orders {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				received := map[uuid.UUID]int{skus[0]: 1, skus[1]: 1}
+				_, errs[j] = ReceiveTransferOrder(ctx, tenantID, order.ID, received)
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+	}
+
+	var quantities []int
+	if err := getDB(ctx).Model(&Inventory{}).Where("tenant_id = ?", tenantID).Pluck("quantity", &quantities).Error; err != nil {
+		t.Fatalf("reload inventories: %v", err)
+	}
+	for _, quantity := range quantities {
+		assert.Equal(t, rounds, quantity)
+	}
+}
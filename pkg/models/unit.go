@@ -0,0 +1,114 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidUnit           = errors.New("invalid unit conversion")
+	ErrUnknownUnit           = errors.New("unknown unit for sku")
+	ErrNonIntegralConversion = errors.New("quantity does not convert to a whole number of base units")
+)
+
+// SkuUnit converts a pack size of a SKU to its base unit: UnitQuantity of
+// Unit equal BaseQuantity base units, e.g. 1 case = 24 each.
+type SkuUnit struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	SkuID        uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
+	Unit         string    `gorm:"not null" json:"unit"`
+	UnitQuantity int       `gorm:"not null;default:1" json:"unit_quantity"`
+	BaseQuantity int       `gorm:"not null" json:"base_quantity"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type UnitModel struct{}
+
+// GetSkuUnits
+
+func (u UnitModel) GetSkuUnits(ctx context.Context, skuID uuid.UUID) ([]SkuUnit, error) {
+	return GetSkuUnits(ctx, skuID)
+}
+
+func GetSkuUnits(ctx context.Context, skuID uuid.UUID) ([]SkuUnit, error) {
+	var units []SkuUnit
+	if err := getDB(ctx).Where("sku_id = ?", skuID).Order("unit").Find(&units).Error; err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
+// SetSkuUnit
+
+func (u UnitModel) SetSkuUnit(ctx context.Context, unit *SkuUnit) error {
+	return SetSkuUnit(ctx, unit)
+}
+
+// SetSkuUnit adds a conversion to the SKU, or replaces the one it already
+// has for the same unit.
+func SetSkuUnit(ctx context.Context, unit *SkuUnit) error {
+	var sku Sku
+	if err := getDB(ctx).First(&sku, "id = ?", unit.SkuID).Error; err != nil {
+		return err
+	}
+	if unit.Unit == sku.BaseUnit {
+		return fmt.Errorf("%w: %s is the base unit", ErrInvalidUnit, unit.Unit)
+	}
+	if unit.UnitQuantity == 0 {
+		unit.UnitQuantity = 1
+	}
+
+	return getDB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "sku_id"}, {Name: "unit"}},
+		DoUpdates: clause.AssignmentColumns([]string{"unit_quantity", "base_quantity", "updated_at"}),
+	}).Create(unit).Error
+}
+
+// ToBaseQuantity
+
+func (i InventoryModel) ToBaseQuantity(ctx context.Context, skuID uuid.UUID, unit string, quantity int) (int, error) {
+	return ToBaseQuantity(ctx, skuID, unit, quantity)
+}
+
+func ToBaseQuantity(ctx context.Context, skuID uuid.UUID, unit string, quantity int) (int, error) {
+	return toBaseQuantity(getDB(ctx), skuID, unit, quantity)
+}
+
+// toBaseQuantity converts a quantity in unit to the SKU's base unit. An
+// empty unit means the base unit. Conversions that do not come out as a
+// whole number of base units are rejected.
+func toBaseQuantity(tx *gorm.DB, skuID uuid.UUID, unit string, quantity int) (int, error) {
+	if unit == "" {
+		return quantity, nil
+	}
+
+	var baseUnit string
+	if err := tx.Model(&Sku{}).Select("base_unit").Where("id = ?", skuID).Scan(&baseUnit).Error; err != nil {
+		return 0, err
+	}
+	if unit == baseUnit {
+		return quantity, nil
+	}
+
+	var conversion SkuUnit
+	err := tx.Where("sku_id = ? AND unit = ?", skuID, unit).First(&conversion).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("%w: %s", ErrUnknownUnit, unit)
+		}
+		return 0, err
+	}
+
+	base := quantity * conversion.BaseQuantity
+	if base%conversion.UnitQuantity != 0 {
+		return 0, fmt.Errorf("%w: %d %s", ErrNonIntegralConversion, quantity, unit)
+	}
+	return base / conversion.UnitQuantity, nil
+}
@@ -0,0 +1,376 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Valuation methods a tenant can choose between
+const (
+	ValuationFIFO            = "fifo"
+	ValuationWeightedAverage = "weighted_average"
+)
+
+var ErrInvalidUnitCost = errors.New("unit cost must not be negative")
+
+// CostLayer is a lot of units that came into a hub at one unit cost.
+// Outbound stock is taken from the oldest open layer first. Under weighted
+// average every receipt folds the open layers into a single new one at the
+// averaged cost.
+type CostLayer struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TenantID  uuid.UUID `gorm:"type:uuid;not null" json:"tenant_id"`
+	HubID     uuid.UUID `gorm:"type:uuid;not null" json:"hub_id"`
+	SkuID     uuid.UUID `gorm:"type:uuid;not null" json:"sku_id"`
+	Quantity  int       `gorm:"not null" json:"quantity"`
+	Remaining int       `gorm:"not null" json:"remaining"`
+	UnitCost  float64   `gorm:"type:numeric(14,4);not null" json:"unit_cost"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CostLayerConsumption records units leaving a layer, so the value on hand
+// can be rebuilt for any past date.
+type CostLayerConsumption struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	CostLayerID uuid.UUID `gorm:"type:uuid;not null" json:"cost_layer_id"`
+	Quantity    int       `gorm:"not null" json:"quantity"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type SkuValuation struct {
+	SkuID    uuid.UUID `json:"sku_id"`
+	SkuCode  string    `json:"sku_code"`
+	SkuName  string    `json:"sku_name"`
+	Quantity int       `json:"quantity"`
+	UnitCost float64   `json:"unit_cost"` // value over quantity
+	Value    float64   `json:"value"`
+}
+
+type HubValuation struct {
+	HubID    uuid.UUID      `json:"hub_id"`
+	HubName  string         `json:"hub_name"`
+	Quantity int            `json:"quantity"`
+	Value    float64        `json:"value"`
+	Skus     []SkuValuation `json:"skus"`
+}
+
+// ValuationReport is the value of a tenant's sellable stock at one instant,
+// broken down by hub and SKU. Skus sums each SKU over the hubs.
+type ValuationReport struct {
+	TenantID uuid.UUID      `json:"tenant_id"`
+	Method   string         `json:"method"`
+	AsOf     time.Time      `json:"as_of"`
+	Quantity int            `json:"quantity"`
+	Value    float64        `json:"value"`
+	Hubs     []HubValuation `json:"hubs"`
+	Skus     []SkuValuation `json:"skus"`
+}
+
+type ValuationModel struct{}
+
+func IsValuationMethod(method string) bool {
+	return method == ValuationFIFO || method == ValuationWeightedAverage
+}
+
+type unitCostKey struct{}
+
+// withUnitCost attaches the cost of the units about to be received, so the
+// layer is written alongside the movement. A nil cost leaves ctx as it is.
+func withUnitCost(ctx context.Context, cost *float64) (context.Context, error) {
+	if cost == nil {
+		return ctx, nil
+	}
+	if *cost < 0 {
+		return nil, ErrInvalidUnitCost
+	}
+	return context.WithValue(ctx, unitCostKey{}, *cost), nil
+}
+
+func unitCostFromContext(ctx context.Context) (float64, bool) {
+	cost, ok := ctx.Value(unitCostKey{}).(float64)
+	return cost, ok
+}
+
+// trackCostLayers keeps the cost layers of a locked row in step with a
+// quantity change. Receipts without a cost come in at the hub's current
+// average, or the SKU's latest cost anywhere in the tenant.
+func trackCostLayers(ctx context.Context, tx *gorm.DB, inv *Inventory, delta int) error {
+	if delta < 0 {
+		return consumeCostLayers(tx, inv, -delta)
+	}
+	if delta == 0 {
+		return nil
+	}
+
+	cost, ok := unitCostFromContext(ctx)
+	if !ok {
+		var err error
+		if cost, err = fallbackUnitCost(tx, inv); err != nil {
+			return err
+		}
+	}
+
+	var method string
+	if err := tx.Model(&Tenant{}).Where("id = ?", inv.TenantID).Select("valuation_method").Scan(&method).Error; err != nil {
+		return err
+	}
+
+	layer := &CostLayer{
+		TenantID:  inv.TenantID,
+		HubID:     inv.HubID,
+		SkuID:     inv.SkuID,
+		Quantity:  delta,
+		Remaining: delta,
+		UnitCost:  cost,
+	}
+	if method == ValuationWeightedAverage {
+		if err := foldCostLayers(tx, layer); err != nil {
+			return err
+		}
+	}
+	return tx.Create(layer).Error
+}
+
+// openCostLayers locks the layers of a hub/SKU that still hold units, oldest
+// first.
+func openCostLayers(tx *gorm.DB, hubID, skuID uuid.UUID) ([]CostLayer, error) {
+	var layers []CostLayer
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("hub_id = ? AND sku_id = ? AND remaining > 0", hubID, skuID).
+		Order("created_at, id").
+		Find(&layers).Error
+	return layers, err
+}
+
+// takeFromLayer books quantity units out of a layer at the given time.
+func takeFromLayer(tx *gorm.DB, layer *CostLayer, quantity int, at time.Time) error {
+	if err := tx.Model(&CostLayer{}).Where("id = ?", layer.ID).
+		Update("remaining", gorm.Expr("remaining - ?", quantity)).Error; err != nil {
+		return err
+	}
+	layer.Remaining -= quantity
+	return tx.Create(&CostLayerConsumption{CostLayerID: layer.ID, Quantity: quantity, CreatedAt: at}).Error
+}
+
+// consumeCostLayers takes units out of the open layers, oldest first. Units
+// with no layer left to come from are ignored.
+func consumeCostLayers(tx *gorm.DB, inv *Inventory, quantity int) error {
+	layers, err := openCostLayers(tx, inv.HubID, inv.SkuID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range layers {
+		if quantity == 0 {
+			break
+		}
+		take := min(layers[i].Remaining, quantity)
+		if err := takeFromLayer(tx, &layers[i], take, now); err != nil {
+			return err
+		}
+		quantity -= take
+	}
+	return nil
+}
+
+// foldCostLayers closes the open layers of the new layer's hub/SKU and
+// grows the new layer by their units, at the average cost of the lot.
+func foldCostLayers(tx *gorm.DB, layer *CostLayer) error {
+	layers, err := openCostLayers(tx, layer.HubID, layer.SkuID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	value := float64(layer.Quantity) * layer.UnitCost
+	for i := range layers {
+		open := layers[i].Remaining
+		value += float64(open) * layers[i].UnitCost
+		layer.Quantity += open
+		if err := takeFromLayer(tx, &layers[i], open, now); err != nil {
+			return err
+		}
+	}
+
+	layer.Remaining = layer.Quantity
+	layer.UnitCost = value / float64(layer.Quantity)
+	layer.CreatedAt = now
+	return nil
+}
+
+// fallbackUnitCost is the cost for a receipt that carries none: the average
+// of the hub's open layers, else the SKU's most recent layer in the tenant,
+// else zero.
+func fallbackUnitCost(tx *gorm.DB, inv *Inventory) (float64, error) {
+	var average *float64
+	err := tx.Model(&CostLayer{}).
+		Select("SUM(remaining * unit_cost) / NULLIF(SUM(remaining), 0)").
+		Where("hub_id = ? AND sku_id = ? AND remaining > 0", inv.HubID, inv.SkuID).
+		Scan(&average).Error
+	if err != nil {
+		return 0, err
+	}
+	if average != nil {
+		return *average, nil
+	}
+
+	var latest []CostLayer
+	err = tx.Where("tenant_id = ? AND sku_id = ?", inv.TenantID, inv.SkuID).
+		Order("created_at DESC").Limit(1).
+		Find(&latest).Error
+	if err != nil || len(latest) == 0 {
+		return 0, err
+	}
+	return latest[0].UnitCost, nil
+}
+
+// nextUnitCost is the average cost of the next quantity units the open
+// layers of a row would give up. ok is false when there are no open layers.
+func nextUnitCost(tx *gorm.DB, inv *Inventory, quantity int) (cost float64, ok bool, err error) {
+	var layers []CostLayer
+	err = tx.Where("hub_id = ? AND sku_id = ? AND remaining > 0", inv.HubID, inv.SkuID).
+		Order("created_at, id").
+		Find(&layers).Error
+	if err != nil || len(layers) == 0 {
+		return 0, false, err
+	}
+
+	value, taken := 0.0, 0
+	for _, layer := range layers {
+		if taken == quantity {
+			break
+		}
+		take := min(layer.Remaining, quantity-taken)
+		value += float64(take) * layer.UnitCost
+		taken += take
+	}
+	if taken == 0 {
+		return 0, false, nil
+	}
+	return value / float64(taken), true, nil
+}
+
+// GetValuation
+
+func (v ValuationModel) GetValuation(ctx context.Context, tenantID uuid.UUID, asOf time.Time, hubID *uuid.UUID) (*ValuationReport, error) {
+	return GetValuation(ctx, tenantID, asOf, hubID)
+}
+
+type valuationRow struct {
+	HubID    uuid.UUID
+	HubName  string
+	SkuID    uuid.UUID
+	SkuCode  string
+	SkuName  string
+	Quantity int
+	Value    float64
+}
+
+// GetValuation values the tenant's sellable stock as it stood just before
+// asOf, rebuilding each layer from what had been taken out of it by then.
+// Stock in other buckets, such as damaged or in transit, carries no value.
+func GetValuation(ctx context.Context, tenantID uuid.UUID, asOf time.Time, hubID *uuid.UUID) (*ValuationReport, error) {
+	tenant, err := GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	hubFilter, args := "", []interface{}{asOf, tenantID, asOf}
+	if hubID != nil {
+		hubFilter = "AND cl.hub_id = ?"
+		args = append(args, *hubID)
+	}
+
+	var rows []valuationRow
+	err = getDB(ctx).Raw(`
+		SELECT
+			l.hub_id,
+			h.name AS hub_name,
+			l.sku_id,
+			s.sku_code,
+			s.name AS sku_name,
+			SUM(l.on_hand) AS quantity,
+			SUM(l.on_hand * l.unit_cost) AS value
+		FROM (
+			SELECT
+				cl.hub_id,
+				cl.sku_id,
+				cl.unit_cost,
+				cl.quantity - COALESCE((
+					SELECT SUM(c.quantity)
+					FROM cost_layer_consumptions c
+					WHERE c.cost_layer_id = cl.id AND c.created_at < ?
+				), 0) AS on_hand
+			FROM cost_layers cl
+			WHERE cl.tenant_id = ? AND cl.created_at < ? `+hubFilter+`
+		) l
+		JOIN hubs h ON h.id = l.hub_id
+		JOIN skus s ON s.id = l.sku_id
+		WHERE l.on_hand > 0
+		GROUP BY l.hub_id, h.name, l.sku_id, s.sku_code, s.name
+		ORDER BY h.name, l.hub_id, s.sku_code
+	`, args...).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return buildValuationReport(tenant, asOf, rows), nil
+}
+
+// buildValuationReport rolls the per hub and SKU rows up into hub, SKU and
+// tenant totals. Rows arrive grouped by hub.
+func buildValuationReport(tenant *Tenant, asOf time.Time, rows []valuationRow) *ValuationReport {
+	report := &ValuationReport{
+		TenantID: tenant.ID,
+		Method:   tenant.ValuationMethod,
+		AsOf:     asOf,
+		Hubs:     []HubValuation{},
+		Skus:     []SkuValuation{},
+	}
+
+	skuIndex := make(map[uuid.UUID]int)
+	for _, row := range rows {
+		line := SkuValuation{
+			SkuID:    row.SkuID,
+			SkuCode:  row.SkuCode,
+			SkuName:  row.SkuName,
+			Quantity: row.Quantity,
+			UnitCost: row.Value / float64(row.Quantity),
+			Value:    row.Value,
+		}
+
+		if n := len(report.Hubs); n == 0 || report.Hubs[n-1].HubID != row.HubID {
+			report.Hubs = append(report.Hubs, HubValuation{HubID: row.HubID, HubName: row.HubName})
+		}
+		hub := &report.Hubs[len(report.Hubs)-1]
+		hub.Skus = append(hub.Skus, line)
+		hub.Quantity += row.Quantity
+		hub.Value += row.Value
+
+		i, ok := skuIndex[row.SkuID]
+		if !ok {
+			i = len(report.Skus)
+			skuIndex[row.SkuID] = i
+			report.Skus = append(report.Skus, SkuValuation{SkuID: row.SkuID, SkuCode: row.SkuCode, SkuName: row.SkuName})
+		}
+		total := &report.Skus[i]
+		total.Quantity += row.Quantity
+		total.Value += row.Value
+		total.UnitCost = total.Value / float64(total.Quantity)
+
+		report.Quantity += row.Quantity
+		report.Value += row.Value
+	}
+
+	sort.Slice(report.Skus, func(i, j int) bool {
+		return report.Skus[i].SkuCode < report.Skus[j].SkuCode
+	})
+	return report
+}
@@ -29,15 +29,32 @@ func SetupRoutes(server *http.Server) {
 		GET("/:id", controllers.GetHubByID).
 		POST("", controllers.CreateHub).
 		DELETE("/:id", controllers.DeleteHub).
-		PUT("/:id", controllers.UpdateHub)
+		PUT("/:id", controllers.UpdateHub).
+		GET("/:id/locations", controllers.GetLocations).
+		POST("/:id/locations", controllers.CreateLocation).
+		GET("/:id/bins/inventory", controllers.GetBinStock).
+		POST("/:id/bins/move", controllers.MoveBinStock).
+		GET("/:id/lots/expiring", controllers.GetExpiringLots)
 
 	// SKU routes (Tenant + Seller)
 	server.Group("/skus", middlewares.AuthMiddleware()).
 		GET("", controllers.GetSkus).
+		GET("/lookup", controllers.LookupBarcode).
 		GET("/:id", controllers.GetSkuByID).
 		POST("", controllers.CreateSku).
 		DELETE("/:id", controllers.DeleteSku).
-		PUT("/:id", controllers.UpdateSku)
+		PUT("/:id", controllers.UpdateSku).
+		GET("/:id/units", controllers.GetSkuUnits).
+		POST("/:id/units", controllers.SetSkuUnit).
+		PUT("/:id/components", controllers.SetBundleComponents).
+		POST("/:id/barcodes", controllers.AddSkuBarcode)
+
+	// Product routes
+	server.Group("/products", middlewares.AuthMiddleware()).
+		POST("", controllers.CreateProduct).
+		GET("", controllers.GetProducts).
+		GET("/:id", controllers.GetProductByID).
+		GET("/:id/variants", controllers.GetProductVariants)
 
 	// Inventory routes
 	server.Group("/inventories", middlewares.AuthMiddleware()).
@@ -47,12 +64,91 @@ func SetupRoutes(server *http.Server) {
 		DELETE("/:id", controllers.DeleteInventory).
 		PUT("/:id", controllers.UpdateInventory).
 		POST("/upsert", controllers.UpsertInventory).
-		GET("/view", controllers.ViewInventoryWithDefaults)
+		GET("/view", controllers.ViewInventoryWithDefaults).
+		GET("/view/detailed", controllers.ViewInventoryDetail).
+		GET("/aging", controllers.GetInventoryAging).
+		GET("/:id/movements", controllers.GetInventoryMovements).
+		GET("/:id/lots", controllers.GetInventoryLots).
+		POST("/:id/lots", controllers.ReceiveLot).
+		POST("/:id/status-moves", controllers.MoveInventoryStatus).
+		PUT("/:id/thresholds", controllers.SetStockThresholds)
+
+
+	// Transfer order routes
+	server.Group("/transfers", middlewares.AuthMiddleware()).
+		GET("", controllers.GetTransferOrders).
+		GET("/:id", controllers.GetTransferOrderByID).
+		POST("", controllers.CreateTransferOrder).
+		POST("/:id/dispatch", controllers.DispatchTransferOrder).
+		POST("/:id/receive", controllers.ReceiveTransferOrder)
+
+	// Stocktake routes
+	server.Group("/stocktakes", middlewares.AuthMiddleware()).
+		POST("", controllers.CreateStocktake).
+		GET("/:id", controllers.GetStocktake).
+		POST("/:id/counts", controllers.RecordStocktakeCounts).
+		POST("/:id/approve", controllers.ApproveStocktake).
+		GET("/:id/variance", controllers.GetStocktakeVariance)
+
+	// Available-to-promise routes
+	server.Group("/atp", middlewares.AuthMiddleware()).
+		GET("", controllers.GetATP).
+		POST("/batch", controllers.GetBatchATP)
+
+	// Order sourcing routes
+	server.Group("/sourcing", middlewares.AuthMiddleware()).
+		POST("/plan", controllers.PlanSourcing)
+
+	// Low-stock alert routes
+	server.Group("/alerts", middlewares.AuthMiddleware()).
+		GET("", controllers.GetStockAlerts).
+		POST("/:id/acknowledge", controllers.AcknowledgeStockAlert)
+
+	// Adjustment routes
+	server.Group("/adjustments", middlewares.AuthMiddleware()).
+		POST("", controllers.CreateAdjustment).
+		GET("", controllers.GetAdjustments).
+		GET("/reason-codes", controllers.GetAdjustmentReasonCodes).
+		POST("/reason-codes", controllers.CreateAdjustmentReasonCode).
+		POST("/:id/approve", controllers.ApproveAdjustment).
+		POST("/:id/reject", controllers.RejectAdjustment)
+
+	// Purchase order routes
+	server.Group("/purchase-orders", middlewares.AuthMiddleware()).
+		POST("", controllers.CreatePurchaseOrder).
+		GET("", controllers.GetPurchaseOrders).
+		GET("/:id", controllers.GetPurchaseOrderByID).
+		POST("/:id/shipping-notices", controllers.CreateShippingNotice).
+		POST("/:id/receive", controllers.ReceivePurchaseOrder).
+		POST("/:id/close", controllers.ClosePurchaseOrder)
+
+	// Return routes
+	server.Group("/returns", middlewares.AuthMiddleware()).
+		POST("", controllers.CreateReturn).
+		GET("", controllers.GetReturns).
+		GET("/ledger", controllers.GetReturnLedger).
+		GET("/:id", controllers.GetReturnByID).
+		POST("/:id/receive", controllers.ReceiveReturn)
+
+	// Valuation routes
+	server.Group("/valuation", middlewares.AuthMiddleware()).
+		GET("", controllers.GetValuation)
+
+	// Forecast routes
+	server.Group("/forecasts", middlewares.AuthMiddleware()).
+		GET("", controllers.GetDemandForecasts)
 
 
 	// InterService Communication
 	server.GET("validators/validate_order/:hub_id/:sku_id", controllers.ValidateOrder)
 	server.POST("/inventory/check-and-update", controllers.CheckAndUpdateInventory)
+	server.POST("/inventory/check-and-update/batch", controllers.BatchCheckAndUpdateInventory)
+	server.POST("/inventory/reservations", controllers.ReserveInventory)
+	server.POST("/inventory/reservations/:order_reference/commit", controllers.CommitReservation)
+	server.POST("/inventory/reservations/:order_reference/release", controllers.ReleaseReservation)
+	server.POST("/inventory/serials", controllers.RegisterSerials)
+	server.POST("/inventory/serials/allocate", controllers.AllocateSerials)
+	server.GET("/inventory/serials/:serial_number", controllers.GetSerial)
 
 
 	// Swagger Routes
@@ -0,0 +1,214 @@
+// Package sourcing decides which hubs fulfil an order. It only works on the
+// candidates it is given; loading stock and hub positions is up to the caller.
+package sourcing
+
+import (
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// DefaultStrategy is used for tenants without a strategy of their own.
+const DefaultStrategy = "single_hub"
+
+const earthRadiusKm = 6371.0
+
+type Destination struct {
+	PostalCode string   `json:"postal_code"`
+	Latitude   *float64 `json:"latitude"`
+	Longitude  *float64 `json:"longitude"`
+}
+
+type Line struct {
+	SkuID    uuid.UUID `json:"sku_id" binding:"required"`
+	Quantity int       `json:"quantity" binding:"required"`
+}
+
+// Candidate is a hub of the tenant with the stock it can ship right now.
+type Candidate struct {
+	HubID      uuid.UUID
+	HubName    string
+	PostalCode string
+	Latitude   *float64
+	Longitude  *float64
+	Available  map[uuid.UUID]int // per SKU
+	DistanceKm *float64          // set by Rank; nil when unknown
+}
+
+type Allocation struct {
+	HubID      uuid.UUID `json:"hub_id"`
+	HubName    string    `json:"hub_name"`
+	SkuID      uuid.UUID `json:"sku_id"`
+	Quantity   int       `json:"quantity"`
+	DistanceKm *float64  `json:"distance_km,omitempty"`
+}
+
+type Shortage struct {
+	SkuID   uuid.UUID `json:"sku_id"`
+	ShortBy int       `json:"short_by"`
+}
+
+type Plan struct {
+	Strategy    string       `json:"strategy"`
+	Fulfillable bool         `json:"fulfillable"`
+	HubCount    int          `json:"hub_count"`
+	Allocations []Allocation `json:"allocations"`
+	Shortages   []Shortage   `json:"shortages"`
+}
+
+// Strategy turns order lines and ranked candidates into a plan. Candidates
+// arrive nearest first; a strategy may reorder them.
+type Strategy interface {
+	Name() string
+	Plan(lines []Line, candidates []Candidate) Plan
+}
+
+var strategies = map[string]Strategy{}
+
+// Register makes a strategy selectable by name. Registering a name twice
+// replaces the earlier strategy.
+func Register(s Strategy) {
+	strategies[s.Name()] = s
+}
+
+func Lookup(name string) (Strategy, bool) {
+	s, ok := strategies[name]
+	return s, ok
+}
+
+func IsStrategy(name string) bool {
+	_, ok := strategies[name]
+	return ok
+}
+
+// Rank fills in each candidate's distance to dest and orders them nearest
+// first. With coordinates the great-circle distance is used; with only a
+// postal code, hubs in that postal code come first at distance zero. Hubs
+// of unknown distance go last, ordered by name for a stable result.
+func Rank(dest Destination, candidates []Candidate) []Candidate {
+	ranked := make([]Candidate, len(candidates))
+	copy(ranked, candidates)
+
+	for i := range ranked {
+		c := &ranked[i]
+		c.DistanceKm = nil
+		switch {
+		case dest.Latitude != nil && dest.Longitude != nil && c.Latitude != nil && c.Longitude != nil:
+			d := haversineKm(*dest.Latitude, *dest.Longitude, *c.Latitude, *c.Longitude)
+			c.DistanceKm = &d
+		case dest.PostalCode != "" && c.PostalCode == dest.PostalCode:
+			d := 0.0
+			c.DistanceKm = &d
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i].DistanceKm, ranked[j].DistanceKm
+		switch {
+		case a != nil && b != nil && *a != *b:
+			return *a < *b
+		case (a == nil) != (b == nil):
+			return a != nil
+		}
+		return ranked[i].HubName < ranked[j].HubName
+	})
+	return ranked
+}
+
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// allocate takes each line from the candidates in the given order until it
+// is covered, recording what could not be found anywhere.
+func allocate(name string, lines []Line, candidates []Candidate) Plan {
+	plan := Plan{Strategy: name, Allocations: []Allocation{}, Shortages: []Shortage{}}
+	remaining := make([]map[uuid.UUID]int, len(candidates))
+	for i, c := range candidates {
+		remaining[i] = make(map[uuid.UUID]int, len(c.Available))
+		for sku, qty := range c.Available {
+			remaining[i][sku] = qty
+		}
+	}
+
+	for _, line := range lines {
+		need := line.Quantity
+		for i, c := range candidates {
+			if need == 0 {
+				break
+			}
+			take := min(remaining[i][line.SkuID], need)
+			if take <= 0 {
+				continue
+			}
+			remaining[i][line.SkuID] -= take
+			need -= take
+			plan.Allocations = append(plan.Allocations, Allocation{
+				HubID:      c.HubID,
+				HubName:    c.HubName,
+				SkuID:      line.SkuID,
+				Quantity:   take,
+				DistanceKm: c.DistanceKm,
+			})
+		}
+		if need > 0 {
+			plan.Shortages = append(plan.Shortages, Shortage{SkuID: line.SkuID, ShortBy: need})
+		}
+	}
+
+	hubs := make(map[uuid.UUID]bool)
+	for _, a := range plan.Allocations {
+		hubs[a.HubID] = true
+	}
+	plan.HubCount = len(hubs)
+	plan.Fulfillable = len(plan.Shortages) == 0
+	return plan
+}
+
+// covers reports whether a candidate alone holds every line.
+func covers(c Candidate, lines []Line) bool {
+	for _, line := range lines {
+		if c.Available[line.SkuID] < line.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// lineTotals sums the requested quantity per SKU.
+func lineTotals(lines []Line) map[uuid.UUID]int {
+	need := make(map[uuid.UUID]int, len(lines))
+	for _, line := range lines {
+		need[line.SkuID] += line.Quantity
+	}
+	return need
+}
+
+// coverTogether reports whether the candidates between them hold need.
+func coverTogether(candidates []Candidate, need map[uuid.UUID]int) bool {
+	for sku, qty := range need {
+		held := 0
+		for _, c := range candidates {
+			held += c.Available[sku]
+		}
+		if held < qty {
+			return false
+		}
+	}
+	return true
+}
+
+// unitsCovered counts how many of the needed units a candidate can ship.
+func unitsCovered(c Candidate, need map[uuid.UUID]int) int {
+	total := 0
+	for sku, qty := range need {
+		total += min(c.Available[sku], qty)
+	}
+	return total
+}
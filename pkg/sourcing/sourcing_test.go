@@ -0,0 +1,203 @@
+package sourcing
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+func hubNames(candidates []Candidate) []string {
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.HubName
+	}
+	return names
+}
+
+func planHubs(plan Plan) map[string]int {
+	hubs := make(map[string]int)
+	for _, a := range plan.Allocations {
+		hubs[a.HubName] += a.Quantity
+	}
+	return hubs
+}
+
+func TestHaversineKm(t *testing.T) {
+	// London to Paris
+	assert.InDelta(t, 343.5, haversineKm(51.5074, -0.1278, 48.8566, 2.3522), 1)
+	assert.InDelta(t, 0, haversineKm(12.97, 77.59, 12.97, 77.59), 1e-9)
+}
+
+func TestRank(t *testing.T) {
+	tests := []struct {
+		name       string
+		dest       Destination
+		candidates []Candidate
+		expected   []string
+		distances  []*float64
+	}{
+		{
+			name: "nearest first by coordinates",
+			dest: Destination{Latitude: floatPtr(0), Longitude: floatPtr(0)},
+			candidates: []Candidate{
+				{HubName: "far", Latitude: floatPtr(0), Longitude: floatPtr(2)},
+				{HubName: "near", Latitude: floatPtr(0), Longitude: floatPtr(1)},
+			},
+			expected:  []string{"near", "far"},
+			distances: []*float64{floatPtr(111.19), floatPtr(222.39)},
+		},
+		{
+			name: "postal code match at distance zero, then by name",
+			dest: Destination{PostalCode: "560001"},
+			candidates: []Candidate{
+				{HubName: "other", PostalCode: "110001"},
+				{HubName: "b-local", PostalCode: "560001"},
+				{HubName: "a-local", PostalCode: "560001"},
+			},
+			expected:  []string{"a-local", "b-local", "other"},
+			distances: []*float64{floatPtr(0), floatPtr(0), nil},
+		},
+		{
+			name: "hubs without coordinates fall back to postal code or go last",
+			dest: Destination{PostalCode: "560001", Latitude: floatPtr(0), Longitude: floatPtr(0)},
+			candidates: []Candidate{
+				{HubName: "unknown"},
+				{HubName: "mapped", Latitude: floatPtr(0), Longitude: floatPtr(1)},
+				{HubName: "local", PostalCode: "560001"},
+			},
+			expected:  []string{"local", "mapped", "unknown"},
+			distances: []*float64{floatPtr(0), floatPtr(111.19), nil},
+		},
+		{
+			name: "unknown destination keeps name order",
+			dest: Destination{},
+			candidates: []Candidate{
+				{HubName: "b", Latitude: floatPtr(0), Longitude: floatPtr(1)},
+				{HubName: "a"},
+			},
+			expected:  []string{"a", "b"},
+			distances: []*float64{nil, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := Rank(tt.dest, tt.candidates)
+
+			assert.Equal(t, tt.expected, hubNames(ranked))
+			for i, expected := range tt.distances {
+				if expected == nil {
+					assert.Nil(t, ranked[i].DistanceKm)
+				} else if assert.NotNil(t, ranked[i].DistanceKm) {
+					assert.InDelta(t, *expected, *ranked[i].DistanceKm, 0.01)
+				}
+			}
+		})
+	}
+}
+
+func TestStrategies(t *testing.T) {
+	skuA, skuB := uuid.New(), uuid.New()
+	hub := func(name string, a, b int) Candidate {
+		return Candidate{HubID: uuid.New(), HubName: name, Available: map[uuid.UUID]int{skuA: a, skuB: b}}
+	}
+
+	tests := []struct {
+		name        string
+		strategy    string
+		lines       []Line
+		candidates  []Candidate // nearest first
+		expected    map[string]int
+		fulfillable bool
+	}{
+		{
+			name:        "single_hub prefers the nearest hub holding everything",
+			strategy:    "single_hub",
+			lines:       []Line{{SkuID: skuA, Quantity: 2}, {SkuID: skuB, Quantity: 2}},
+			candidates:  []Candidate{hub("near", 2, 1), hub("middle", 2, 2), hub("far", 9, 9)},
+			expected:    map[string]int{"middle": 4},
+			fulfillable: true,
+		},
+		{
+			name:        "single_hub splits over the fewest hubs",
+			strategy:    "single_hub",
+			lines:       []Line{{SkuID: skuA, Quantity: 5}, {SkuID: skuB, Quantity: 5}},
+			candidates:  []Candidate{hub("mixed", 4, 4), hub("only-a", 5, 0), hub("only-b", 0, 5)},
+			expected:    map[string]int{"only-a": 5, "only-b": 5},
+			fulfillable: true,
+		},
+		{
+			name:        "single_hub prefers nearer hubs among equal splits",
+			strategy:    "single_hub",
+			lines:       []Line{{SkuID: skuA, Quantity: 5}, {SkuID: skuB, Quantity: 5}},
+			candidates:  []Candidate{hub("near-a", 5, 0), hub("far-a", 5, 0), hub("b", 0, 5)},
+			expected:    map[string]int{"near-a": 5, "b": 5},
+			fulfillable: true,
+		},
+		{
+			name:        "single_hub splits greedily when nothing covers",
+			strategy:    "single_hub",
+			lines:       []Line{{SkuID: skuA, Quantity: 10}},
+			candidates:  []Candidate{hub("small", 3, 0), hub("empty", 0, 0), hub("big", 4, 0)},
+			expected:    map[string]int{"big": 4, "small": 3},
+			fulfillable: false,
+		},
+		{
+			name:        "nearest drains the closest hubs first",
+			strategy:    "nearest",
+			lines:       []Line{{SkuID: skuA, Quantity: 5}},
+			candidates:  []Candidate{hub("near", 3, 0), hub("far", 5, 0)},
+			expected:    map[string]int{"near": 3, "far": 2},
+			fulfillable: true,
+		},
+		{
+			name:        "most_stock draws from the fullest hub first",
+			strategy:    "most_stock",
+			lines:       []Line{{SkuID: skuA, Quantity: 5}},
+			candidates:  []Candidate{hub("near", 3, 0), hub("far", 5, 0)},
+			expected:    map[string]int{"far": 5},
+			fulfillable: true,
+		},
+		{
+			name:        "most_stock splits when the fullest hub falls short",
+			strategy:    "most_stock",
+			lines:       []Line{{SkuID: skuA, Quantity: 7}},
+			candidates:  []Candidate{hub("near", 3, 0), hub("far", 5, 0)},
+			expected:    map[string]int{"far": 5, "near": 2},
+			fulfillable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, ok := Lookup(tt.strategy)
+			assert.True(t, ok)
+
+			plan := strategy.Plan(tt.lines, tt.candidates)
+
+			assert.Equal(t, tt.strategy, plan.Strategy)
+			assert.Equal(t, tt.expected, planHubs(plan))
+			assert.Equal(t, len(tt.expected), plan.HubCount)
+			assert.Equal(t, tt.fulfillable, plan.Fulfillable)
+		})
+	}
+}
+
+func TestSingleHubGreedyBeyondExactSearch(t *testing.T) {
+	sku := uuid.New()
+	candidates := make([]Candidate, maxExactHubs+1)
+	for i := range candidates {
+		candidates[i] = Candidate{HubID: uuid.New(), HubName: string(rune('a' + i)), Available: map[uuid.UUID]int{sku: 1}}
+	}
+	candidates[maxExactHubs].Available[sku] = 4
+
+	strategy, _ := Lookup("single_hub")
+	plan := strategy.Plan([]Line{{SkuID: sku, Quantity: 6}}, candidates)
+
+	assert.True(t, plan.Fulfillable)
+	assert.Equal(t, 3, plan.HubCount)
+	assert.Equal(t, 4, planHubs(plan)[candidates[maxExactHubs].HubName])
+}
@@ -0,0 +1,139 @@
+package sourcing
+
+import "sort"
+
+func init() {
+	Register(singleHub{})
+	Register(nearest{})
+	Register(mostStock{})
+}
+
+// maxExactHubs bounds how many candidates singleHub searches exhaustively
+// for the fewest hubs covering an order. Beyond it, it splits greedily.
+const maxExactHubs = 12
+
+// singleHub ships from the nearest hub holding the whole order. When no hub
+// does, it splits across the fewest hubs that together hold it, preferring
+// nearer hubs among splits of the same size. When no split covers the order,
+// or there are too many hubs to search, it splits greedily instead.
+type singleHub struct{}
+
+func (singleHub) Name() string { return "single_hub" }
+
+func (s singleHub) Plan(lines []Line, candidates []Candidate) Plan {
+	for _, c := range candidates {
+		if covers(c, lines) {
+			return allocate(s.Name(), lines, []Candidate{c})
+		}
+	}
+
+	if len(candidates) <= maxExactHubs {
+		if chosen := fewestCovering(lines, candidates); chosen != nil {
+			return allocate(s.Name(), lines, chosen)
+		}
+	}
+	return allocate(s.Name(), lines, greedyCover(lines, candidates))
+}
+
+// fewestCovering finds the smallest set of candidates that together hold
+// every line. Sets of one size are tried in rank order, so the nearer hubs
+// win a tie. It returns nil when even all candidates together fall short.
+func fewestCovering(lines []Line, candidates []Candidate) []Candidate {
+	need := lineTotals(lines)
+	n := len(candidates)
+
+	for size := 2; size <= n; size++ {
+		picked := make([]int, size)
+		for i := range picked {
+			picked[i] = i
+		}
+
+		for {
+			chosen := make([]Candidate, size)
+			for i, index := range picked {
+				chosen[i] = candidates[index]
+			}
+			if coverTogether(chosen, need) {
+				return chosen
+			}
+
+			// Advance to the next combination in lexicographic order
+			i := size - 1
+			for i >= 0 && picked[i] == n-size+i {
+				i--
+			}
+			if i < 0 {
+				break
+			}
+			picked[i]++
+			for j := i + 1; j < size; j++ {
+				picked[j] = picked[j-1] + 1
+			}
+		}
+	}
+	return nil
+}
+
+// greedyCover orders the candidates by taking, again and again, the one
+// covering the most of what is still needed, the nearer one on ties. It
+// usually needs few hubs but does not promise the fewest. Candidates that
+// add nothing are left out.
+func greedyCover(lines []Line, candidates []Candidate) []Candidate {
+	need := lineTotals(lines)
+	left := make([]Candidate, len(candidates))
+	copy(left, candidates)
+
+	var ordered []Candidate
+	for len(left) > 0 {
+		best, bestUnits := -1, 0
+		for i, c := range left {
+			if units := unitsCovered(c, need); units > bestUnits {
+				best, bestUnits = i, units
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		c := left[best]
+		for sku, qty := range need {
+			need[sku] = qty - min(c.Available[sku], qty)
+		}
+		ordered = append(ordered, c)
+		left = append(left[:best], left[best+1:]...)
+	}
+	return ordered
+}
+
+// nearest fills every line from the closest hubs first, splitting freely.
+type nearest struct{}
+
+func (nearest) Name() string { return "nearest" }
+
+func (n nearest) Plan(lines []Line, candidates []Candidate) Plan {
+	return allocate(n.Name(), lines, candidates)
+}
+
+// mostStock draws from the hubs holding the most of the ordered SKUs first,
+// spreading demand away from hubs that are running low. Distance only breaks
+// ties.
+type mostStock struct{}
+
+func (mostStock) Name() string { return "most_stock" }
+
+func (m mostStock) Plan(lines []Line, candidates []Candidate) Plan {
+	stock := func(c Candidate) int {
+		total := 0
+		for _, line := range lines {
+			total += c.Available[line.SkuID]
+		}
+		return total
+	}
+
+	ordered := make([]Candidate, len(candidates))
+	copy(ordered, candidates)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return stock(ordered[i]) > stock(ordered[j])
+	})
+	return allocate(m.Name(), lines, ordered)
+}